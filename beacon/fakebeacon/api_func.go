@@ -2,16 +2,28 @@ package fakebeacon
 
 import (
 	"context"
+	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
-	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
 )
 
+// SlotsPerEpoch is the number of the slots in an epoch, in which the finality
+// checkpoints are reported. A slot is a second, as blocks are mapped by time.
+const SlotsPerEpoch = 32
+
+// emptySignature is the signature of the block headers, which are not signed
+// by any beacon proposer.
+var emptySignature = hexutil.Encode(make([]byte, 96))
+
 // spec: https://ethereum.github.io/beacon-APIs/#/Beacon/getBlobSidecars
 type BlobSidecar struct {
 	Blob          kzg4844.Blob       `json:"blob"`
@@ -34,6 +46,7 @@ type APIGenesisResponse struct {
 
 type ReducedConfigData struct {
 	SecondsPerSlot string `json:"SECONDS_PER_SLOT"`
+	SlotsPerEpoch  string `json:"SLOTS_PER_EPOCH"`
 }
 
 type IndexedBlobHash struct {
@@ -59,28 +72,121 @@ func (v *Uint64String) UnmarshalText(b []byte) error {
 }
 
 func configSpec() ReducedConfigData {
-	return ReducedConfigData{SecondsPerSlot: "1"}
+	return ReducedConfigData{SecondsPerSlot: "1", SlotsPerEpoch: strconv.Itoa(SlotsPerEpoch)}
 }
 
 func beaconGenesis() APIGenesisResponse {
 	return APIGenesisResponse{Data: ReducedGenesisData{GenesisTime: "0"}}
 }
 
-func beaconBlobSidecars(ctx context.Context, backend ethapi.Backend, slot uint64, indices []int) (APIGetBlobSidecarsResponse, error) {
-	var blockNrOrHash rpc.BlockNumberOrHash
-	header, err := fetchBlockNumberByTime(ctx, int64(slot), backend)
-	if err != nil {
-		log.Error("Error fetching block number", "slot", slot, "indices", indices)
-		return APIGetBlobSidecarsResponse{}, err
+// resolveBlockID returns the block identified by the block_id of the Beacon API,
+// which is one of "head", "genesis", "finalized", "justified", a slot, or a
+// block root. The slot is the timestamp of the block, and the root is its hash.
+func resolveBlockID(ctx context.Context, backend ethapi.Backend, id string) (*types.Header, error) {
+	var (
+		header *types.Header
+		err    error
+	)
+	switch {
+	case id == "head":
+		header = backend.CurrentHeader()
+	case id == "genesis":
+		header, err = backend.HeaderByNumber(ctx, 0)
+	case id == "finalized":
+		header, err = backend.HeaderByNumber(ctx, rpc.FinalizedBlockNumber)
+	case id == "justified":
+		header, err = backend.HeaderByNumber(ctx, rpc.SafeBlockNumber)
+	case strings.HasPrefix(id, "0x"):
+		hash, decodeErr := hexutil.Decode(id)
+		if decodeErr != nil || len(hash) != common.HashLength {
+			return nil, fmt.Errorf("invalid block root: %s", id)
+		}
+		header, err = backend.HeaderByHash(ctx, common.BytesToHash(hash))
+	default:
+		slot, parseErr := strconv.ParseUint(id, 10, 64)
+		if parseErr != nil {
+			return nil, fmt.Errorf("invalid block id: %s", id)
+		}
+		return fetchBlockNumberByTime(ctx, int64(slot), backend)
 	}
-	sideCars, err := backend.GetBlobSidecars(ctx, header.Hash())
-	if err != nil {
-		log.Error("Error fetching Sidecars", "blockNrOrHash", blockNrOrHash, "err", err)
-		return APIGetBlobSidecarsResponse{}, err
+	if err != nil || header == nil {
+		return nil, fmt.Errorf("%w: %s", errBlockNotFound, id)
 	}
+	return header, nil
+}
+
+// isCanonical reports whether the block is in the canonical chain.
+func isCanonical(ctx context.Context, backend ethapi.Backend, header *types.Header) bool {
+	canonical, err := backend.HeaderByNumber(ctx, rpc.BlockNumber(header.Number.Int64()))
+	return err == nil && canonical != nil && canonical.Hash() == header.Hash()
+}
+
+// isFinalized reports whether the block is finalized in the canonical chain.
+func isFinalized(ctx context.Context, backend ethapi.Backend, header *types.Header) bool {
+	finalized, err := backend.HeaderByNumber(ctx, rpc.FinalizedBlockNumber)
+	if err != nil || finalized == nil || finalized.Number.Cmp(header.Number) < 0 {
+		return false
+	}
+	return isCanonical(ctx, backend, header)
+}
+
+func beaconBlockHeader(ctx context.Context, backend ethapi.Backend, header *types.Header) *structs.GetBlockHeaderResponse {
+	return &structs.GetBlockHeaderResponse{
+		Finalized: isFinalized(ctx, backend, header),
+		Data: &structs.SignedBeaconBlockHeaderContainer{
+			Root:      header.Hash().Hex(),
+			Canonical: isCanonical(ctx, backend, header),
+			Header: &structs.SignedBeaconBlockHeader{
+				Message: &structs.BeaconBlockHeader{
+					Slot:          strconv.FormatUint(header.Time, 10),
+					ProposerIndex: "0",
+					ParentRoot:    header.ParentHash.Hex(),
+					StateRoot:     header.Root.Hex(),
+					BodyRoot:      header.TxHash.Hex(),
+				},
+				Signature: emptySignature,
+			},
+		},
+	}
+}
+
+func beaconBlockRoot(ctx context.Context, backend ethapi.Backend, header *types.Header) *structs.BlockRootResponse {
+	return &structs.BlockRootResponse{
+		Finalized: isFinalized(ctx, backend, header),
+		Data:      &structs.BlockRoot{Root: header.Hash().Hex()},
+	}
+}
+
+// beaconFinalityCheckpoints reports the finalized block and the safe one as the
+// justified checkpoints, or the empty checkpoints if not yet available.
+func beaconFinalityCheckpoints(ctx context.Context, backend ethapi.Backend) *structs.GetFinalityCheckpointsResponse {
+	checkpoint := func(number rpc.BlockNumber) *structs.Checkpoint {
+		header, err := backend.HeaderByNumber(ctx, number)
+		if err != nil || header == nil {
+			return &structs.Checkpoint{Epoch: "0", Root: common.Hash{}.Hex()}
+		}
+		return &structs.Checkpoint{
+			Epoch: strconv.FormatUint(header.Time/SlotsPerEpoch, 10),
+			Root:  header.Hash().Hex(),
+		}
+	}
+	var (
+		finalized = checkpoint(rpc.FinalizedBlockNumber)
+		justified = checkpoint(rpc.SafeBlockNumber)
+	)
+	return &structs.GetFinalityCheckpointsResponse{
+		Data: &structs.FinalityCheckpoints{
+			PreviousJustified: finalized,
+			CurrentJustified:  justified,
+			Finalized:         finalized,
+		},
+	}
+}
+
+func beaconBlobSidecars(sideCars types.BlobSidecars, indices []int) APIGetBlobSidecarsResponse {
 	sort.Ints(indices)
 	fullBlob := len(indices) == 0
-	res := APIGetBlobSidecarsResponse{}
+	res := APIGetBlobSidecarsResponse{Data: []*BlobSidecar{}}
 	idx := 0
 	curIdx := 0
 	for _, sideCar := range sideCars {
@@ -102,5 +208,5 @@ func beaconBlobSidecars(ctx context.Context, backend ethapi.Backend, slot uint64
 		}
 	}
 
-	return res, nil
+	return res
 }
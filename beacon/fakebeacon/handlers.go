@@ -1,11 +1,14 @@
 package fakebeacon
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
-	"strings"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gorilla/mux"
 
 	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
 	"github.com/prysmaticlabs/prysm/v5/network/httputil"
@@ -17,7 +20,11 @@ var (
 	versionMethod        = "/eth/v1/node/version"
 	specMethod           = "/eth/v1/config/spec"
 	genesisMethod        = "/eth/v1/beacon/genesis"
-	sidecarsMethodPrefix = "/eth/v1/beacon/blob_sidecars/{slot}"
+	sidecarsMethodPrefix = "/eth/v1/beacon/blob_sidecars/{block_id}"
+
+	headerMethod              = "/eth/v1/beacon/headers/{block_id}"
+	blockRootMethod           = "/eth/v1/beacon/blocks/{block_id}/root"
+	finalityCheckpointsMethod = "/eth/v1/beacon/states/{state_id}/finality_checkpoints"
 )
 
 func VersionMethod(w http.ResponseWriter, r *http.Request) {
@@ -37,25 +44,71 @@ func GenesisMethod(w http.ResponseWriter, r *http.Request) {
 	httputil.WriteJson(w, beaconGenesis())
 }
 
+func (s *Service) HeaderMethod(w http.ResponseWriter, r *http.Request) {
+	header, ok := s.blockByID(w, r)
+	if !ok {
+		return
+	}
+	httputil.WriteJson(w, beaconBlockHeader(r.Context(), s.backend, header))
+}
+
+func (s *Service) BlockRootMethod(w http.ResponseWriter, r *http.Request) {
+	header, ok := s.blockByID(w, r)
+	if !ok {
+		return
+	}
+	httputil.WriteJson(w, beaconBlockRoot(r.Context(), s.backend, header))
+}
+
+// FinalityCheckpointsMethod responds the latest checkpoints for any state, as
+// the historical states are not tracked.
+func (s *Service) FinalityCheckpointsMethod(w http.ResponseWriter, r *http.Request) {
+	if _, err := resolveBlockID(r.Context(), s.backend, mux.Vars(r)["state_id"]); err != nil {
+		handleBlockError(w, err)
+		return
+	}
+	httputil.WriteJson(w, beaconFinalityCheckpoints(r.Context(), s.backend))
+}
+
 func (s *Service) SidecarsMethod(w http.ResponseWriter, r *http.Request) {
 	indices, err := parseIndices(r.URL)
 	if err != nil {
 		httputil.HandleError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	segments := strings.Split(r.URL.Path, "/")
-	slot, err := strconv.ParseUint(segments[len(segments)-1], 10, 64)
+	header, ok := s.blockByID(w, r)
+	if !ok {
+		return
+	}
+	sidecars, err := s.blobSidecars(r.Context(), header)
 	if err != nil {
-		httputil.HandleError(w, "not a valid slot(timestamp)", http.StatusBadRequest)
+		httputil.HandleError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(sidecars) == 0 && header.BlobGasUsed != nil && *header.BlobGasUsed > 0 {
+		httputil.HandleError(w, fmt.Sprintf("blob sidecars of block %d are pruned", header.Number), http.StatusNotFound)
 		return
 	}
+	httputil.WriteJson(w, beaconBlobSidecars(sidecars, indices))
+}
 
-	resp, err := beaconBlobSidecars(r.Context(), s.backend, slot, indices)
+// blockByID resolves the block_id of the request, or responds the error.
+func (s *Service) blockByID(w http.ResponseWriter, r *http.Request) (*types.Header, bool) {
+	header, err := resolveBlockID(r.Context(), s.backend, mux.Vars(r)["block_id"])
 	if err != nil {
-		httputil.HandleError(w, err.Error(), http.StatusBadRequest)
+		handleBlockError(w, err)
+		return nil, false
+	}
+	return header, true
+}
+
+// handleBlockError responds 404 if the block is not found, or 400 otherwise.
+func handleBlockError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errBlockNotFound) {
+		httputil.HandleError(w, err.Error(), http.StatusNotFound)
 		return
 	}
-	httputil.WriteJson(w, resp)
+	httputil.HandleError(w, err.Error(), http.StatusBadRequest)
 }
 
 // parseIndices filters out invalid and duplicate blob indices
@@ -1,10 +1,14 @@
 package fakebeacon
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/gorilla/mux"
 	"github.com/prysmaticlabs/prysm/v5/api/server/middleware"
 )
@@ -32,9 +36,10 @@ type Service struct {
 	cfg     *Config
 	router  *mux.Router
 	backend ethapi.Backend
+	server  *http.Server
 }
 
-func NewService(cfg *Config, backend ethapi.Backend) *Service {
+func NewService(cfg *Config, backend ethapi.Backend) (*Service, error) {
 	cfgs := defaultConfig()
 	if cfg.Addr != "" {
 		cfgs.Addr = cfg.Addr
@@ -49,11 +54,32 @@ func NewService(cfg *Config, backend ethapi.Backend) *Service {
 	}
 	router := s.newRouter()
 	s.router = router
-	return s
+	return s, nil
 }
 
-func (s *Service) Run() {
-	_ = http.ListenAndServe(s.cfg.Addr+":"+strconv.Itoa(s.cfg.Port), s.router)
+// Start implements node.Lifecycle, serving the API.
+func (s *Service) Start() error {
+	s.server = &http.Server{Addr: s.cfg.Addr + ":" + strconv.Itoa(s.cfg.Port), Handler: s.router}
+	go func() {
+		if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("Fake beacon server failed", "err", err)
+		}
+	}()
+	return nil
+}
+
+// Stop implements node.Lifecycle.
+func (s *Service) Stop() error {
+	if s.server != nil {
+		s.server.Close()
+	}
+	return nil
+}
+
+// blobSidecars returns the sidecars of the block. The backend serves the ones
+// retained by the node, falling back to the blob archive once pruned.
+func (s *Service) blobSidecars(ctx context.Context, header *types.Header) (types.BlobSidecars, error) {
+	return s.backend.GetBlobSidecars(ctx, header.Hash())
 }
 
 func (s *Service) newRouter() *mux.Router {
@@ -88,6 +114,21 @@ func (s *Service) endpoints() []endpoint {
 			handler: GenesisMethod,
 			methods: []string{http.MethodGet},
 		},
+		{
+			path:    headerMethod,
+			handler: s.HeaderMethod,
+			methods: []string{http.MethodGet},
+		},
+		{
+			path:    blockRootMethod,
+			handler: s.BlockRootMethod,
+			methods: []string{http.MethodGet},
+		},
+		{
+			path:    finalityCheckpointsMethod,
+			handler: s.FinalityCheckpointsMethod,
+			methods: []string{http.MethodGet},
+		},
 		{
 			path:    sidecarsMethodPrefix,
 			handler: s.SidecarsMethod,
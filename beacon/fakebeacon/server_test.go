@@ -2,10 +2,16 @@ package fakebeacon
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/prysmaticlabs/prysm/v5/api/server/structs"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -88,3 +94,77 @@ func TestAPI(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, expTx, gotTx)
 }
+
+func TestBeaconEndpoints(t *testing.T) {
+	backend := makeBackend(10, []uint64{2}).(*MockBackendForFakeBeacon)
+	blobGas := uint64(131072)
+	for _, number := range []int{5, 7, 8} {
+		backend.headers[number].BlobGasUsed = &blobGas
+	}
+	headers := backend.headers
+	for _, number := range []int{5, 7} {
+		backend.sidecars[headers[number].Hash()] = types.BlobSidecars{{
+			BlobTxSidecar: types.BlobTxSidecar{
+				Blobs:       []kzg4844.Blob{{byte(number)}, {byte(number + 1)}},
+				Commitments: []kzg4844.Commitment{{}, {}},
+				Proofs:      []kzg4844.Proof{{}, {}},
+			},
+		}}
+	}
+	backend.finalized = headers[6]
+
+	s := &Service{backend: backend}
+	s.router = s.newRouter()
+
+	get := func(path string, status int, res any) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != status {
+			t.Fatalf("%s: status mismatch, have %d, want %d: %s", path, rec.Code, status, rec.Body)
+		}
+		if res != nil {
+			if err := json.NewDecoder(rec.Body).Decode(res); err != nil {
+				t.Fatalf("%s: %v", path, err)
+			}
+		}
+	}
+	slot := func(number int) string { return strconv.FormatUint(headers[number].Time, 10) }
+
+	var header structs.GetBlockHeaderResponse
+	get("/eth/v1/beacon/headers/head", http.StatusOK, &header)
+	assert.Equal(t, headers[9].Hash().Hex(), header.Data.Root)
+	assert.False(t, header.Finalized)
+
+	get("/eth/v1/beacon/headers/"+slot(3), http.StatusOK, &header)
+	assert.Equal(t, headers[3].Hash().Hex(), header.Data.Root)
+	assert.Equal(t, slot(3), header.Data.Header.Message.Slot)
+	assert.True(t, header.Data.Canonical)
+	assert.True(t, header.Finalized)
+
+	get(fmt.Sprintf("/eth/v1/beacon/headers/%d", headers[3].Time+1), http.StatusNotFound, nil)
+	get("/eth/v1/beacon/headers/unknown", http.StatusBadRequest, nil)
+
+	var root structs.BlockRootResponse
+	get("/eth/v1/beacon/blocks/"+headers[5].Hash().Hex()+"/root", http.StatusOK, &root)
+	assert.Equal(t, headers[5].Hash().Hex(), root.Data.Root)
+
+	var sidecars APIGetBlobSidecarsResponse
+	get("/eth/v1/beacon/blob_sidecars/"+headers[5].Hash().Hex()+"?indices=1", http.StatusOK, &sidecars)
+	assert.Len(t, sidecars.Data, 1)
+	assert.Equal(t, Uint64String(1), sidecars.Data[0].Index)
+	assert.Equal(t, byte(6), sidecars.Data[0].Blob[0])
+
+	get("/eth/v1/beacon/blob_sidecars/"+slot(7), http.StatusOK, &sidecars)
+	assert.Len(t, sidecars.Data, 2)
+	assert.Equal(t, byte(7), sidecars.Data[0].Blob[0])
+
+	get("/eth/v1/beacon/blob_sidecars/"+slot(8), http.StatusNotFound, nil)
+	get("/eth/v1/beacon/blob_sidecars/"+slot(9), http.StatusOK, &sidecars)
+	assert.Empty(t, sidecars.Data)
+
+	var checkpoints structs.GetFinalityCheckpointsResponse
+	get("/eth/v1/beacon/states/head/finality_checkpoints", http.StatusOK, &checkpoints)
+	assert.Equal(t, headers[6].Hash().Hex(), checkpoints.Data.Finalized.Root)
+	assert.Equal(t, strconv.FormatUint(headers[6].Time/SlotsPerEpoch, 10), checkpoints.Data.Finalized.Epoch)
+}
@@ -2,14 +2,21 @@ package fakebeacon
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
-	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
+// errBlockNotFound is returned if no block is identified, responded as 404.
+var errBlockNotFound = errors.New("block not found")
+
+// fetchBlockNumberByTime returns the canonical block whose timestamp is the
+// slot, by a binary search over the block numbers. The timestamps are strictly
+// increasing, so the block is found deterministically in the logarithmic number
+// of the lookups, or not found if no block is sealed at the slot.
 func fetchBlockNumberByTime(ctx context.Context, ts int64, backend ethapi.Backend) (*types.Header, error) {
 	currentHeader := backend.CurrentHeader()
 	if ts == int64(currentHeader.Time) {
@@ -17,119 +24,32 @@ func fetchBlockNumberByTime(ctx context.Context, ts int64, backend ethapi.Backen
 		return currentHeader, nil
 	} else if ts > int64(currentHeader.Time) {
 		// Future time so return an error.
-		return nil, fmt.Errorf("future time %d, current time %d", ts, currentHeader.Time)
+		return nil, fmt.Errorf("%w: future time %d, current time %d", errBlockNotFound, ts, currentHeader.Time)
 	}
 
-	// Performs a range search to locate the block whose timestamp matches ts.
-	// Gradually narrows down the search range.
+	// Find the first block whose timestamp is not earlier than the slot.
 	var (
-		// highEdge and lowEdge represent the current known range of block headers.
-		highEdge = currentHeader // higher bound block header
-		lowEdge  *types.Header   // lower bound block header
-		// Start the search from the current header.
-		cursor = currentHeader
-		// isBackward toggles the search direction to help narrow the range.
-		// - true: search past blocks from cursor
-		// - false: search future blocks from cursor
-		isBackward = true
-		// The average block time is used to estimate the block number.
-		// The initial value is from the chain configuration.
-		averageBlockTime = getBlockPeriod(backend.ChainConfig())
+		low  = uint64(0)
+		high = currentHeader.Number.Uint64()
 	)
-
-	for {
-		estimated := estimateBlockNumber(averageBlockTime, int64(cursor.Time), int64(cursor.Number.Uint64()), ts)
-
-		var err error
-		if cursor, err = backend.HeaderByNumber(ctx, rpc.BlockNumber(estimated)); err != nil {
-			return nil, fmt.Errorf("failed to fetch block by timestamp %d: %v", ts, err)
+	for low < high {
+		mid := low + (high-low)/2
+		header, err := backend.HeaderByNumber(ctx, rpc.BlockNumber(mid))
+		if err != nil || header == nil {
+			return nil, fmt.Errorf("failed to fetch block %d by timestamp %d: %v", mid, ts, err)
 		}
-
-		// Succeed! Found the block.
-		if int64(cursor.Time) == ts {
-			return cursor, nil
-		}
-
-		// Alternate updating the boundaries to narrow the search range.
-		if isBackward {
-			if ts > int64(cursor.Time) {
-				lowEdge = cursor
-				isBackward = !isBackward // Toggle the search direction.
-			} else {
-				highEdge = cursor
-				if highEdge.Number.Uint64() == 0 {
-					// higher bound reached genesis block.
-					// Occurs when the target time is earlier than the genesis block
-					return nil, fmt.Errorf("failed to fetch block by timestamp %d: earlier than genesis %d", ts, highEdge.Time)
-				}
-			}
+		if int64(header.Time) < ts {
+			low = mid + 1
 		} else {
-			if ts < int64(cursor.Time) {
-				highEdge = cursor
-				isBackward = !isBackward // Toggle the search direction.
-			} else {
-				lowEdge = cursor
-			}
+			high = mid
 		}
-
-		// Low edge yet to be reached.
-		if lowEdge == nil {
-			continue
-		}
-
-		// Sanity check
-		if highEdge.Number.Cmp(lowEdge.Number) <= 0 {
-			return nil, fmt.Errorf("failed to fetch block by timestamp %d: highEdge %d <= lowEdge %d", ts, highEdge.Number, lowEdge.Number)
-		}
-
-		// Update average block time.
-		averageBlockTime = int64((highEdge.Time - lowEdge.Time) / (highEdge.Number.Uint64() - lowEdge.Number.Uint64()))
 	}
-}
-
-func getBlockPeriod(cfg *params.ChainConfig) int64 {
-	switch {
-	case cfg.ChainID.Cmp(params.OasysMainnetChainConfig.ChainID) == 0:
-		return params.SHORT_BLOCK_TIME_SECONDS
-	case cfg.ChainID.Cmp(params.OasysTestnetChainConfig.ChainID) == 0:
-		return params.SHORT_BLOCK_TIME_SECONDS
-	case cfg.Oasys != nil:
-		return int64(cfg.Oasys.Period) // for local chain
-	default:
-		return 1
-	}
-}
-
-func estimateBlockNumber(blockPeriod, sourceTime, sourceNumber, targetTime int64) int64 {
-	diff := targetTime - sourceTime
-
-	// Determine how many blocks to shift.
-	var shift int64
-	if abs(diff) < blockPeriod {
-		shift = 1
-	} else {
-		shift = abs(diff) / blockPeriod
+	header, err := backend.HeaderByNumber(ctx, rpc.BlockNumber(low))
+	if err != nil || header == nil {
+		return nil, fmt.Errorf("failed to fetch block %d by timestamp %d: %v", low, ts, err)
 	}
-
-	if diff < 0 {
-		// Target time is in the past from source time.
-		estimated := sourceNumber - shift
-		if estimated < 0 {
-			// Smaller than genesis block.
-			return 0
-		}
-		return estimated
-	}
-
-	// Target time is in the future from source time.
-	return sourceNumber + shift
-}
-
-// abs returns the absolute value of an int64.
-// Define as the starndard library does not have an abs for int64.
-func abs(x int64) int64 {
-	if x < 0 {
-		return -x
+	if int64(header.Time) != ts {
+		return nil, fmt.Errorf("%w: no block at timestamp %d", errBlockNotFound, ts)
 	}
-	return x
+	return header, nil
 }
@@ -2,11 +2,13 @@ package fakebeacon
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/params"
@@ -80,9 +82,33 @@ func TestFetchBlockNumberByTime(t *testing.T) {
 	}
 }
 
+func TestFetchBlockNumberByTimeMissing(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		backend = makeBackend(1000, []uint64{2}).(*MockBackendForFakeBeacon)
+		head    = backend.CurrentHeader()
+	)
+	// The slots between the blocks and after the head are not found
+	for _, ts := range []uint64{backend.headers[0].Time - 1, backend.headers[500].Time + 1, head.Time + 1} {
+		if _, err := fetchBlockNumberByTime(ctx, int64(ts), backend); !errors.Is(err, errBlockNotFound) {
+			t.Errorf("slot %d: expected not found, got %v", ts, err)
+		}
+	}
+	// The search is deterministic in the logarithmic number of the lookups
+	backend.searchAttempts = 0
+	if _, err := fetchBlockNumberByTime(ctx, int64(backend.headers[123].Time), backend); err != nil {
+		t.Fatal(err)
+	}
+	if backend.searchAttempts > 11 {
+		t.Errorf("too many lookups: %d", backend.searchAttempts)
+	}
+}
+
 type MockBackendForFakeBeacon struct {
 	ethapi.Backend
 	headers        []*types.Header
+	sidecars       map[common.Hash]types.BlobSidecars
+	finalized      *types.Header
 	searchAttempts int // Count the number of search attempts
 }
 
@@ -91,20 +117,39 @@ func (b *MockBackendForFakeBeacon) CurrentHeader() *types.Header {
 }
 
 func (b *MockBackendForFakeBeacon) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
-	if number < 0 || number > rpc.BlockNumber(len(b.headers)) {
+	if number == rpc.FinalizedBlockNumber || number == rpc.SafeBlockNumber {
+		if b.finalized == nil {
+			return nil, errors.New("finalized block not found")
+		}
+		return b.finalized, nil
+	}
+	if number < 0 || number >= rpc.BlockNumber(len(b.headers)) {
 		return nil, fmt.Errorf("out of range. block number: %d", number)
 	}
 	b.searchAttempts++
 	return b.headers[number], nil
 }
 
+func (b *MockBackendForFakeBeacon) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	for _, header := range b.headers {
+		if header.Hash() == hash {
+			return header, nil
+		}
+	}
+	return nil, nil
+}
+
+func (b *MockBackendForFakeBeacon) GetBlobSidecars(ctx context.Context, hash common.Hash) (types.BlobSidecars, error) {
+	return b.sidecars[hash], nil
+}
+
 func (b *MockBackendForFakeBeacon) ChainConfig() *params.ChainConfig {
 	return params.OasysMainnetChainConfig
 }
 
 func makeBackend(length int, candidateBlockTimes []uint64) ethapi.Backend {
 	var (
-		backend    = MockBackendForFakeBeacon{headers: make([]*types.Header, length)}
+		backend    = MockBackendForFakeBeacon{headers: make([]*types.Header, length), sidecars: make(map[common.Hash]types.BlobSidecars)}
 		timeCursor = uint64(time.Now().Unix()) // Blocktime start from this time
 	)
 	for blockNumber := 0; blockNumber < length; blockNumber++ {
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"encoding/hex"
 	"encoding/json"
@@ -8,6 +9,7 @@ import (
 	"math/big"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/google/uuid"
@@ -27,8 +29,11 @@ import (
 
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/vote"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/signer/core"
 )
 
@@ -213,6 +218,47 @@ Generate ownership proof for the selected BLS account from the BLS wallet. The p
 					},
 				},
 			},
+			{
+				Name:      "slashing-protection",
+				Usage:     "Manage the slashing protection database of the votes",
+				ArgsUsage: "",
+				Category:  "BLS ACCOUNT COMMANDS",
+				Description: `
+
+Export and import the votes signed by the BLS keys, which the node refuses to
+conflict with, in the EIP-3076 interchange format. The block numbers of the
+votes are written as the epochs, and the genesis hash as the genesis validators
+root. Export the votes before migrating a validator to a new host, and import
+them there before starting to vote. The node must be stopped.`,
+				Subcommands: []*cli.Command{
+					{
+						Name:      "export",
+						Usage:     "Export the slashing protection database",
+						Action:    blsSlashingProtectionExport,
+						ArgsUsage: "<file>",
+						Category:  "BLS ACCOUNT COMMANDS",
+						Flags:     slices.Concat([]cli.Flag{utils.DataDirFlag, utils.SlashingProtectionDirFlag}, utils.NetworkFlags),
+						Description: `
+	geth bls slashing-protection export <file>
+
+Export the votes signed by all the BLS keys into <file> in the interchange format.`,
+					},
+					{
+						Name:      "import",
+						Usage:     "Import votes into the slashing protection database",
+						Action:    blsSlashingProtectionImport,
+						ArgsUsage: "<file>",
+						Category:  "BLS ACCOUNT COMMANDS",
+						Flags:     slices.Concat([]cli.Flag{utils.DataDirFlag, utils.SlashingProtectionDirFlag}, utils.NetworkFlags),
+						Description: `
+	geth bls slashing-protection import <file>
+
+Merge the votes in <file> in the interchange format into the slashing protection
+database. The votes lower than the lowest imported ones are refused afterwards.
+The genesis of the file must match the chain, initialize the chain first.`,
+					},
+				},
+			},
 		},
 	}
 )
@@ -701,3 +747,84 @@ func blsAccountGenerateProof(ctx *cli.Context) error {
 
 	return nil
 }
+
+// blsSlashingProtectionExport exports the slashing protection database in the interchange format.
+func blsSlashingProtectionExport(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	genesisHash := slashingProtectionGenesisHash(ctx, stack)
+	protection, err := vote.OpenSlashingProtection(slashingProtectionPath(stack), true)
+	if err != nil {
+		utils.Fatalf("%v", err)
+	}
+	defer protection.Close()
+
+	data, err := json.MarshalIndent(protection.Export(genesisHash), "", "  ")
+	if err != nil {
+		utils.Fatalf("Failed to encode interchange: %v", err)
+	}
+	if err := os.WriteFile(ctx.Args().First(), data, 0600); err != nil {
+		utils.Fatalf("Failed to write interchange: %v", err)
+	}
+	fmt.Printf("Exported slashing protection database to %s\n", ctx.Args().First())
+	return nil
+}
+
+// blsSlashingProtectionImport merges the votes in the interchange format into the slashing protection database.
+func blsSlashingProtectionImport(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	data, err := os.ReadFile(ctx.Args().First())
+	if err != nil {
+		utils.Fatalf("Failed to read interchange: %v", err)
+	}
+	var interchange vote.Interchange
+	if err := json.Unmarshal(data, &interchange); err != nil {
+		utils.Fatalf("Failed to decode interchange: %v", err)
+	}
+
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	genesisHash := slashingProtectionGenesisHash(ctx, stack)
+	protection, err := vote.OpenSlashingProtection(slashingProtectionPath(stack), false)
+	if err != nil {
+		utils.Fatalf("%v", err)
+	}
+	defer protection.Close()
+
+	if err := protection.Import(&interchange, genesisHash); err != nil {
+		utils.Fatalf("Failed to import interchange: %v", err)
+	}
+	fmt.Printf("Imported slashing protection of %d BLS keys\n", len(interchange.Data))
+	return nil
+}
+
+// slashingProtectionPath returns the path of the slashing protection database.
+func slashingProtectionPath(stack *node.Node) string {
+	return stack.ResolvePath(cmp.Or(stack.Config().SlashingProtectionDir, vote.SlashingProtectionDatabase))
+}
+
+// slashingProtectionGenesisHash returns the genesis hash of the network preset
+// if one is set, otherwise the one of the chain in the datadir.
+func slashingProtectionGenesisHash(ctx *cli.Context, stack *node.Node) common.Hash {
+	if utils.IsNetworkPreset(ctx) {
+		return utils.MakeGenesis(ctx).ToBlock().Hash()
+	}
+	db, err := stack.OpenDatabaseWithOptions("chaindata", node.DatabaseOptions{ReadOnly: true})
+	if err != nil {
+		utils.Fatalf("Failed to open chain database: %v", err)
+	}
+	defer db.Close()
+
+	genesisHash := rawdb.ReadCanonicalHash(db, 0)
+	if genesisHash == (common.Hash{}) {
+		utils.Fatalf("The chain is not initialized, run geth init first")
+	}
+	return genesisHash
+}
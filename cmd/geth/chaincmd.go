@@ -39,6 +39,7 @@ import (
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	contracts "github.com/ethereum/go-ethereum/contracts/oasys"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/history"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -102,10 +103,14 @@ It expects the genesis file as argument.`,
 		Name:      "dumpgenesis",
 		Usage:     "Dumps genesis block JSON configuration to stdout",
 		ArgsUsage: "",
-		Flags:     slices.Concat([]cli.Flag{utils.DataDirFlag}, utils.NetworkFlags),
+		Flags:     slices.Concat([]cli.Flag{utils.DataDirFlag, dumpOasysDeploymentsFlag}, utils.NetworkFlags),
 		Description: `
 The dumpgenesis command prints the genesis configuration of the network preset
-if one is set.  Otherwise it prints the genesis from the datadir.`,
+if one is set.  Otherwise it prints the genesis from the datadir.
+
+With --oasys.deployments, the effective built-in contract deployment schedule
+of an Oasys chain is written to the config, so that it can be edited for a
+private chain.`,
 	}
 	importCommand = &cli.Command{
 		Action:    importChain,
@@ -269,6 +274,10 @@ helps reduce storage requirements for nodes that don't need full historical data
 )
 
 var (
+	dumpOasysDeploymentsFlag = &cli.BoolFlag{
+		Name:  "oasys.deployments",
+		Usage: "Include the built-in contract deployment schedule in the dumped Oasys chain config",
+	}
 	eraBlockFlag = &cli.StringFlag{
 		Name:  "block",
 		Usage: "Block number to fetch. (can also be a range <start>-<end>)",
@@ -590,6 +599,9 @@ func dumpGenesis(ctx *cli.Context) error {
 	}
 
 	if genesis != nil {
+		if err := fillOasysDeployments(ctx, genesis, genesis.ToBlock().Hash()); err != nil {
+			utils.Fatalf("could not dump deployments: %s", err)
+		}
 		if err := json.NewEncoder(os.Stdout).Encode(genesis); err != nil {
 			utils.Fatalf("could not encode genesis: %s", err)
 		}
@@ -615,6 +627,9 @@ func dumpGenesis(ctx *cli.Context) error {
 	if err != nil {
 		utils.Fatalf("failed to read genesis: %s", err)
 	}
+	if err := fillOasysDeployments(ctx, genesis, rawdb.ReadCanonicalHash(db, 0)); err != nil {
+		utils.Fatalf("could not dump deployments: %s", err)
+	}
 
 	if err := json.NewEncoder(os.Stdout).Encode(*genesis); err != nil {
 		utils.Fatalf("could not encode stored genesis: %s", err)
@@ -623,6 +638,24 @@ func dumpGenesis(ctx *cli.Context) error {
 	return nil
 }
 
+// fillOasysDeployments sets the effective deployment schedule to the Oasys
+// chain config of the genesis if requested.
+func fillOasysDeployments(ctx *cli.Context, genesis *core.Genesis, genesisHash common.Hash) error {
+	if !ctx.Bool(dumpOasysDeploymentsFlag.Name) || genesis.Config == nil || genesis.Config.Oasys == nil {
+		return nil
+	}
+	deployments, err := contracts.DeploymentSchedule(genesis.Config, genesisHash)
+	if err != nil {
+		return err
+	}
+	oasys := *genesis.Config.Oasys // Don't modify the preset config
+	oasys.Deployments = deployments
+	config := *genesis.Config
+	config.Oasys = &oasys
+	genesis.Config = &config
+	return nil
+}
+
 func importChain(ctx *cli.Context) error {
 	if ctx.Args().Len() < 1 {
 		utils.Fatalf("This command requires an argument.")
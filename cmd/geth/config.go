@@ -319,7 +319,11 @@ func makeFullNode(ctx *cli.Context) (*node.Node, ethapi.Backend) {
 		cfg.FakeBeacon.Port = ctx.Int(utils.FakeBeaconPortFlag.Name)
 	}
 	if cfg.FakeBeacon.Enable || ctx.IsSet(utils.FakeBeaconEnabledFlag.Name) {
-		go fakebeacon.NewService(&cfg.FakeBeacon, backend).Run()
+		service, err := fakebeacon.NewService(&cfg.FakeBeacon, backend)
+		if err != nil {
+			utils.Fatalf("Failed to create the fake beacon service: %v", err)
+		}
+		stack.RegisterLifecycle(service)
 	}
 
 	git, _ := version.VCS()
@@ -34,6 +34,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/console/prompt"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/filtermaps"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state/snapshot"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -94,6 +96,9 @@ Remove blockchain and state databases`,
 			dbTrieGetCmd,
 			// dbTrieDeleteCmd,
 			dbInspectHistoryCmd,
+			dbExportBlobsCmd,
+			dbImportBlobsCmd,
+			dbFilterMapsCheckpointsCmd,
 		},
 	}
 	dbInspectCmd = &cli.Command{
@@ -270,6 +275,36 @@ WARNING: This is a low-level operation which may cause database corruption!`,
 		Usage: "Inspect the ancientStore information",
 		Description: `This commands will read current offset from kvdb, which is the current offset and starting BlockNumber
 of ancientStore, will also displays the reserved number of blocks in ancientStore `,
+	}
+	dbExportBlobsCmd = &cli.Command{
+		Action:    exportBlobs,
+		Name:      "export-blobs",
+		Usage:     "Exports the blob sidecars into an archive of flat files",
+		ArgsUsage: "<archive> [<start> [<end>]]",
+		Flags:     slices.Concat(utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `Appends the blob sidecars of the canonical blocks in the range into the archive
+directory, created if not exists. The range starts after the last archived block, or
+at the genesis if none, and ends at the head by default.`,
+	}
+	dbImportBlobsCmd = &cli.Command{
+		Action:    importBlobs,
+		Name:      "import-blobs",
+		Usage:     "Imports the blob sidecars from an archive into the archive of the node",
+		ArgsUsage: "<archive>",
+		Flags:     slices.Concat([]cli.Flag{utils.BlobArchiveFlag}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `Verifies the blob sidecars of the archive against the canonical chain, and appends
+them into the archive of the node given by --blob.archive, from which the node serves
+them once pruned.`,
+	}
+	dbFilterMapsCheckpointsCmd = &cli.Command{
+		Action:    filterMapsCheckpoints,
+		Name:      "filtermaps-checkpoints",
+		Usage:     "Generates the checkpoints of the log index",
+		ArgsUsage: "<file> [<block>]",
+		Flags:     slices.Concat(utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `Writes the checkpoints of the log index epochs up to the block, the finalized one by
+default, in the format of the checkpoint files embedded in core/filtermaps. The log
+index has to be complete from the genesis, i.e. built with --history.logs=0.`,
 	}
 	dbInspectHistoryCmd = &cli.Command{
 		Action:    inspectHistory,
@@ -1455,3 +1490,224 @@ func inspectHistory(ctx *cli.Context) error {
 	}
 	return inspectStorage(triedb, start, end, address, slot, ctx.Bool("raw"))
 }
+
+// blobsInterrupt returns a channel closed on SIGINT or SIGTERM, and the function
+// releasing the signal handler.
+func blobsInterrupt() (chan struct{}, func()) {
+	var (
+		interrupt = make(chan os.Signal, 1)
+		stop      = make(chan struct{})
+	)
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		if _, ok := <-interrupt; ok {
+			log.Info("Interrupted, stopping at next block")
+		}
+		close(stop)
+	}()
+	return stop, func() {
+		signal.Stop(interrupt)
+		close(interrupt)
+	}
+}
+
+func exportBlobs(ctx *cli.Context) error {
+	if ctx.NArg() < 1 || ctx.NArg() > 3 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	archive, err := rawdb.NewSidecarArchive(ctx.Args().Get(0), false)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	head := rawdb.ReadHeaderNumber(db, rawdb.ReadHeadBlockHash(db))
+	if head == nil {
+		return errors.New("head block is not found")
+	}
+	var (
+		start = archive.SidecarsArchived()
+		end   = *head
+	)
+	if ctx.NArg() > 1 {
+		if start, err = strconv.ParseUint(ctx.Args().Get(1), 10, 64); err != nil {
+			return fmt.Errorf("invalid start block: %v", err)
+		}
+		if next := archive.SidecarsArchived(); next > 0 && start < next {
+			return fmt.Errorf("start block %d is already archived, next %d", start, next)
+		}
+	}
+	if ctx.NArg() > 2 {
+		if end, err = strconv.ParseUint(ctx.Args().Get(2), 10, 64); err != nil {
+			return fmt.Errorf("invalid end block: %v", err)
+		}
+	}
+	stop, release := blobsInterrupt()
+	defer release()
+
+	var (
+		exported int
+		begin    = time.Now()
+		logged   = time.Now()
+	)
+	log.Info("Exporting blob sidecars", "archive", ctx.Args().Get(0), "start", start, "end", end)
+	for number := start; number <= end; number++ {
+		select {
+		case <-stop:
+			log.Info("Blob sidecars exporting interrupted", "number", number, "exported", exported)
+			return nil
+		default:
+		}
+		hash := rawdb.ReadCanonicalHash(db, number)
+		if hash == (common.Hash{}) {
+			return fmt.Errorf("canonical hash missing for block %d", number)
+		}
+		sidecars := rawdb.ReadBlobSidecarsRLP(db, hash, number)
+		if len(sidecars) == 0 || bytes.Equal(sidecars, rlp.EmptyList) {
+			continue
+		}
+		if err := archive.ArchiveSidecarsRLP(number, hash, sidecars); err != nil {
+			return err
+		}
+		exported++
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Exporting blob sidecars", "number", number, "exported", exported, "elapsed", common.PrettyDuration(time.Since(begin)))
+			logged = time.Now()
+		}
+	}
+	log.Info("Exported blob sidecars", "blocks", exported, "elapsed", common.PrettyDuration(time.Since(begin)))
+	return nil
+}
+
+func importBlobs(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	dir := ctx.String(utils.BlobArchiveFlag.Name)
+	if dir == "" {
+		return fmt.Errorf("archive of the node is not given by --%s", utils.BlobArchiveFlag.Name)
+	}
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	config, _, err := core.LoadChainConfig(db, nil)
+	if err != nil {
+		return err
+	}
+	source, err := rawdb.NewSidecarArchive(ctx.Args().Get(0), true)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	archive, err := rawdb.NewSidecarArchive(stack.ResolvePath(dir), false)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	stop, release := blobsInterrupt()
+	defer release()
+
+	var (
+		first, next = source.Range()
+		imported    int
+		skipped     int
+		begin       = time.Now()
+		logged      = time.Now()
+	)
+	// The archive is append-only, so the blocks below its first one can't be
+	// imported once the node has started archiving.
+	if archiveFirst, archiveNext := archive.Range(); archiveFirst < archiveNext {
+		if first < min(next, archiveFirst) {
+			return fmt.Errorf("blocks %d-%d are below the first archived block %d, import them before the node starts archiving or into a fresh archive directory", first, min(next, archiveFirst)-1, archiveFirst)
+		}
+		first = max(first, archiveNext) // Already archived by the node
+	}
+	log.Info("Importing blob sidecars", "archive", ctx.Args().Get(0), "start", first, "end", next)
+	for number := first; number < next; number++ {
+		select {
+		case <-stop:
+			log.Info("Blob sidecars importing interrupted", "number", number, "imported", imported)
+			return nil
+		default:
+		}
+		hash, data := source.ReadEntry(number)
+		if len(data) == 0 {
+			continue
+		}
+		// Import only the sidecars of the canonical blocks which are valid
+		if rawdb.ReadCanonicalHash(db, number) != hash {
+			skipped++
+			continue
+		}
+		var sidecars types.BlobSidecars
+		if err := rlp.DecodeBytes(data, &sidecars); err != nil {
+			return fmt.Errorf("invalid sidecars of block %d: %v", number, err)
+		}
+		block := rawdb.ReadBlock(db, hash, number)
+		if block == nil {
+			return fmt.Errorf("block %d is missing", number)
+		}
+		if err := core.VerifySidecars(config, block, sidecars); err != nil {
+			return fmt.Errorf("invalid sidecars of block %d: %v", number, err)
+		}
+		if err := archive.ArchiveSidecarsRLP(number, hash, data); err != nil {
+			return err
+		}
+		imported++
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Importing blob sidecars", "number", number, "imported", imported, "elapsed", common.PrettyDuration(time.Since(begin)))
+			logged = time.Now()
+		}
+	}
+	log.Info("Imported blob sidecars", "blocks", imported, "skipped", skipped, "elapsed", common.PrettyDuration(time.Since(begin)))
+	return nil
+}
+
+func filterMapsCheckpoints(ctx *cli.Context) error {
+	if ctx.NArg() < 1 || ctx.NArg() > 2 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	var finalBlock uint64
+	if ctx.NArg() > 1 {
+		number, err := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid block: %v", err)
+		}
+		finalBlock = number
+	} else {
+		number := rawdb.ReadHeaderNumber(db, rawdb.ReadFinalizedBlockHash(db))
+		if number == nil {
+			return errors.New("finalized block is not found, specify the block")
+		}
+		finalBlock = *number
+	}
+	w, err := os.Create(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	epochs, err := filtermaps.ExportCheckpoints(db, filtermaps.DefaultParams, finalBlock, w)
+	if err != nil {
+		return err
+	}
+	log.Info("Generated log index checkpoints", "file", ctx.Args().Get(0), "epochs", epochs, "block", finalBlock)
+	return nil
+}
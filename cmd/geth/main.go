@@ -46,6 +46,9 @@ import (
 	_ "github.com/ethereum/go-ethereum/eth/tracers/live"
 	_ "github.com/ethereum/go-ethereum/eth/tracers/native"
 
+	// Force-load the builtin suspicious txfilters to trigger registration
+	_ "github.com/ethereum/go-ethereum/txfilter/builtin"
+
 	"github.com/urfave/cli/v2"
 )
 
@@ -168,14 +171,38 @@ var (
 		utils.VotingEnabledFlag,
 		utils.DisableVoteAttestationFlag,
 		utils.DisableSuspiciousTxFilterFlag,
+		utils.SuspiciousTxFilterBackendFlag,
+		utils.SuspiciousTxFilterBuiltinFlag,
+		utils.SuspiciousTxFilterSidecarFlag,
+		utils.SuspiciousTxFilterShadowFlag,
+		utils.SuspiciousTxFilterSourcesFlag,
+		utils.SuspiciousTxFilterMetadataHashFlag,
 		utils.EnableMaliciousVoteMonitorFlag,
+		utils.MaliciousVoteReporterFlag,
+		utils.EnableFinalityReporterFlag,
+		utils.EnableVoteParticipationIndexerFlag,
 		utils.BLSPasswordFileFlag,
 		utils.BLSWalletDirFlag,
+		utils.BLSRemoteSignerFlag,
+		utils.BLSRemoteSignerPubKeyFlag,
+		utils.BLSRemoteSignerClientCertFlag,
+		utils.BLSRemoteSignerClientKeyFlag,
+		utils.BLSRemoteSignerCACertFlag,
+		utils.BLSRemoteSignerTimeoutFlag,
+		utils.StandbyLeaseFlag,
+		utils.StandbyLeaseTTLFlag,
+		utils.StandbyHolderFlag,
 		utils.VoteJournalDirFlag,
+		utils.SlashingProtectionDirFlag,
 		utils.VoteKeyNameFlag,
+		utils.VotePoolCurVotesFlag,
+		utils.VotePoolFutureVotesFlag,
+		utils.VotePoolPruneDistanceFlag,
+		utils.VotePoolFutureDistanceFlag,
 		utils.LogDebugFlag,
 		utils.LogBacktraceAtFlag,
 		utils.BlobExtraReserveFlag,
+		utils.BlobArchiveFlag,
 		// utils.BeaconApiFlag,
 		// utils.BeaconApiHeaderFlag,
 		// utils.BeaconThresholdFlag,
@@ -282,6 +309,8 @@ func init() {
 		// See snapshot.go
 		snapshotCommand,
 		blsCommand,
+		// See oasyscmd.go
+		oasysCommand,
 		// See verkle.go
 		verkleCommand,
 	}
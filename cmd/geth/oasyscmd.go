@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/consensus/oasys"
+	"github.com/ethereum/go-ethereum/core/standby"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	simulateJSONFlag = &cli.BoolFlag{
+		Name:  "json",
+		Usage: "Print the simulation result in JSON",
+	}
+	simulateBlocksFlag = &cli.BoolFlag{
+		Name:  "blocks",
+		Usage: "Print the expected validator and the sealer of every block",
+	}
+	leaseServerAddrFlag = &cli.StringFlag{
+		Name:  "addr",
+		Usage: "Listening address of the lease server",
+		Value: "127.0.0.1:8555",
+	}
+
+	oasysCommand = &cli.Command{
+		Name:     "oasys",
+		Usage:    "Oasys consensus utilities",
+		Category: "OASYS COMMANDS",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "simulate-schedule",
+				Usage:     "Simulate the validator schedule and slashing of an epoch offline",
+				ArgsUsage: "<scenario.json>",
+				Action:    simulateSchedule,
+				Flags: []cli.Flag{
+					simulateJSONFlag,
+					simulateBlocksFlag,
+				},
+				Description: `
+    geth oasys simulate-schedule scenario.json
+
+Calculates the validator schedule of an epoch in the same way as the consensus
+engine, then replays the downtime windows against it and prints the number of
+blocks expected, sealed and missed by each validator and whether it would be jailed.
+Nothing is read from the database, so stake changes can be evaluated before they
+go on-chain. The scenario file looks like:
+
+{
+  "network": "mainnet",
+  "environment": {"startBlock": 4089600, "startEpoch": 711, "blockPeriod": 6, "epochPeriod": 14400, ...},
+  "epoch": 800,
+  "seed": "0x<hash of the last block of the previous epoch>",
+  "validators": [{"operator": "0x...", "stake": "10000000000000000000000000"}],
+  "downtimes": [{"validator": "0x...", "from": 5371200, "to": 5371500}]
+}
+
+The "network" is either "mainnet" or "testnet", otherwise the "chainConfig" must be
+given. The "environment" defaults to the genesis environment of the network, and
+the stakes are in wei. The jail outcome is an approximation of the StakeManager
+contract, which counts the missed blocks against the "jailThreshold".`,
+			},
+			{
+				Name:   "lease-server",
+				Usage:  "Run a lease server coordinating the standby validator nodes",
+				Action: runLeaseServer,
+				Flags: []cli.Flag{
+					leaseServerAddrFlag,
+				},
+				Description: `
+    geth oasys lease-server --addr 127.0.0.1:8555
+
+Serves the validator lease to the nodes started with
+"--standby.lease http://127.0.0.1:8555", so that only the node holding the lease
+seals and votes. The lease is kept in memory, so the server must be run once per
+validator and must not be load balanced.`,
+			},
+		},
+	}
+)
+
+type scheduleScenario struct {
+	Network     string                   `json:"network"`
+	ChainConfig *params.ChainConfig      `json:"chainConfig"`
+	Environment *params.EnvironmentValue `json:"environment"`
+	Epoch       uint64                   `json:"epoch"`
+	Seed        common.Hash              `json:"seed"`
+	Validators  []struct {
+		Operator common.Address        `json:"operator"`
+		Stake    *math.HexOrDecimal256 `json:"stake"`
+	} `json:"validators"`
+	Downtimes []oasys.Downtime `json:"downtimes"`
+}
+
+func simulateSchedule(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		return errors.New("expected a scenario file as argument")
+	}
+	data, err := os.ReadFile(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	var scenario scheduleScenario
+	if err := json.Unmarshal(data, &scenario); err != nil {
+		return fmt.Errorf("invalid scenario file: %v", err)
+	}
+
+	config := scenario.ChainConfig
+	switch scenario.Network {
+	case "mainnet":
+		config = params.OasysMainnetChainConfig
+	case "testnet":
+		config = params.OasysTestnetChainConfig
+	case "":
+		if config == nil || config.Oasys == nil {
+			return errors.New("either the network or the oasys chain config is required")
+		}
+	default:
+		return fmt.Errorf("unknown network: %s", scenario.Network)
+	}
+	env := scenario.Environment
+	if env == nil {
+		env = params.InitialEnvironmentValue(config.Oasys)
+	} else if env.StartBlock == nil || env.StartEpoch == nil || env.EpochPeriod == nil || env.JailThreshold == nil || env.JailPeriod == nil {
+		return errors.New("startBlock, startEpoch, epochPeriod, jailThreshold and jailPeriod of the environment are required")
+	}
+
+	operators := make([]common.Address, len(scenario.Validators))
+	stakes := make([]*big.Int, len(scenario.Validators))
+	for i, v := range scenario.Validators {
+		if v.Stake == nil {
+			return fmt.Errorf("stake of %s is missing", v.Operator)
+		}
+		operators[i], stakes[i] = v.Operator, (*big.Int)(v.Stake)
+	}
+
+	result, err := oasys.SimulateSchedule(config, env, scenario.Epoch, operators, stakes, scenario.Seed, scenario.Downtimes)
+	if err != nil {
+		return err
+	}
+	if ctx.Bool(simulateJSONFlag.Name) {
+		if !ctx.Bool(simulateBlocksFlag.Name) {
+			result.Blocks = nil
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	fmt.Printf("Epoch: %d, blocks: %d-%d, seed: %d\n\n", result.Epoch, result.StartBlock, result.EndBlock, result.Seed)
+	if ctx.Bool(simulateBlocksFlag.Name) {
+		for _, block := range result.Blocks {
+			var note string
+			if block.Slashed {
+				note = " (slashed)"
+			}
+			fmt.Printf("#%-10d expected: %s  sealer: %s%s\n", block.Number, block.Expected, block.Sealer, note)
+		}
+		fmt.Println()
+	}
+	fmt.Printf("%-42s  %10s  %8s  %8s  %8s  %s\n", "Operator", "Stake(OAS)", "Expected", "Sealed", "Missed", "Jailed")
+	fmt.Println(strings.Repeat("-", 95))
+	for _, v := range result.Validators {
+		stake := new(big.Int).Div(v.Stake, big.NewInt(params.Ether))
+		jailed := "no"
+		if v.Jailed {
+			jailed = fmt.Sprintf("yes (%s epochs)", env.JailPeriod)
+		}
+		fmt.Printf("%-42s  %10s  %8d  %8d  %8d  %s\n", v.Operator, stake, v.Expected, v.Sealed, v.Missed, jailed)
+	}
+	return nil
+}
+
+func runLeaseServer(ctx *cli.Context) error {
+	listener, err := net.Listen("tcp", ctx.String(leaseServerAddrFlag.Name))
+	if err != nil {
+		return err
+	}
+	log.Info("Lease server started", "addr", listener.Addr())
+
+	server := &http.Server{
+		Handler:           standby.NewServer(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		sigc := make(chan os.Signal, 1)
+		signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+		<-sigc
+		server.Close()
+	}()
+	if err := server.Serve(listener); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
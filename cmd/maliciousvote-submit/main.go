@@ -11,6 +11,8 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/contracts/oasys"
+	"github.com/ethereum/go-ethereum/contracts/oasys/slashindicator"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -93,7 +95,7 @@ func submitMaliciousVotes(c *cli.Context) error {
 	if evidenceJson == "" {
 		log.Crit("no evidence specified (--evidence)")
 	}
-	var evidence SlashIndicatorFinalityEvidence
+	var evidence slashindicator.SlashIndicatorFinalityEvidence
 	if err = evidence.UnmarshalJSON([]byte(evidenceJson)); err != nil {
 		log.Crit("Error parsing evidence", "error", err)
 	} else {
@@ -102,7 +104,7 @@ func submitMaliciousVotes(c *cli.Context) error {
 
 	ops, _ := bind.NewKeyedTransactorWithChainID(sender, big.NewInt(int64(chainId)))
 	//ops.GasLimit = 800000
-	slashIndicator, _ := NewSlashIndicator(common.HexToAddress("0x0"), client)
+	slashIndicator, _ := slashindicator.NewSlashIndicator(common.HexToAddress(oasys.SlashIndicatorAddress), client)
 	tx, err := slashIndicator.SubmitFinalityViolationEvidence(ops, evidence)
 	if err != nil {
 		log.Crit("submitMaliciousVotes:", "error", err)
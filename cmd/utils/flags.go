@@ -32,6 +32,7 @@ import (
 	"path/filepath"
 	"runtime"
 	godebug "runtime/debug"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -44,9 +45,11 @@ import (
 	"github.com/ethereum/go-ethereum/common/fdlimit"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/standby"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vote/voteconfig"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/ethereum/go-ethereum/eth"
@@ -1158,6 +1161,37 @@ Please note that --` + MetricsHTTPFlag.Name + ` must be set to start the server.
 		Usage:    "Disable suspicious tx filter",
 		Category: flags.MinerCategory,
 	}
+	SuspiciousTxFilterBackendFlag = &cli.StringFlag{
+		Name:     "miner.txfilter.backend",
+		Usage:    "Backend of suspicious tx filter (plugin, builtin or sidecar)",
+		Value:    core.TxfilterBackendPlugin,
+		Category: flags.MinerCategory,
+	}
+	SuspiciousTxFilterBuiltinFlag = &cli.StringFlag{
+		Name:     "miner.txfilter.builtin",
+		Usage:    "Name of the suspicious tx filter compiled into the binary, used by the builtin backend",
+		Category: flags.MinerCategory,
+	}
+	SuspiciousTxFilterShadowFlag = &cli.BoolFlag{
+		Name:     "miner.txfilter.shadow",
+		Usage:    "Evaluate the suspicious tx filter and journal the decisions without dropping transactions",
+		Category: flags.MinerCategory,
+	}
+	SuspiciousTxFilterSidecarFlag = &cli.StringFlag{
+		Name:     "miner.txfilter.sidecar",
+		Usage:    "JSON-RPC endpoint (http, ws or ipc) of the suspicious tx filter sidecar, used by the sidecar backend",
+		Category: flags.MinerCategory,
+	}
+	SuspiciousTxFilterSourcesFlag = &cli.StringFlag{
+		Name:     "miner.txfilter.sources",
+		Usage:    "Comma separated sources of the suspicious tx filter artifacts tried in order, each is a local directory, an HTTP mirror URL or \"cdn\"",
+		Category: flags.MinerCategory,
+	}
+	SuspiciousTxFilterMetadataHashFlag = &cli.StringFlag{
+		Name:     "miner.txfilter.metadatahash",
+		Usage:    "Hex encoded sha256 hash pinning the suspicious tx filter metadata",
+		Category: flags.MinerCategory,
+	}
 
 	EnableMaliciousVoteMonitorFlag = &cli.BoolFlag{
 		Name:     "monitor.maliciousvote",
@@ -1165,6 +1199,24 @@ Please note that --` + MetricsHTTPFlag.Name + ` must be set to start the server.
 		Category: flags.FastFinalityCategory,
 	}
 
+	MaliciousVoteReporterFlag = &cli.StringFlag{
+		Name:     "monitor.maliciousvote.reporter",
+		Usage:    "Account to submit the evidence of malicious votes detected by the monitor (must be unlocked)",
+		Category: flags.FastFinalityCategory,
+	}
+
+	EnableFinalityReporterFlag = &cli.BoolFlag{
+		Name:     "monitor.finality",
+		Usage:    "Enable finality reporter to export the vote propagation and the finality latency of every finalized block as metrics and logs",
+		Category: flags.FastFinalityCategory,
+	}
+
+	EnableVoteParticipationIndexerFlag = &cli.BoolFlag{
+		Name:     "monitor.voteparticipation",
+		Usage:    "Enable vote participation indexer to record which validators voted, voted late or missed in every finalized block",
+		Category: flags.FastFinalityCategory,
+	}
+
 	BLSPasswordFileFlag = &cli.StringFlag{
 		Name:     "blspassword",
 		Usage:    "Password file path for the BLS wallet, which contains the password to unlock BLS wallet for managing votes in fast_finality feature",
@@ -1177,15 +1229,101 @@ Please note that --` + MetricsHTTPFlag.Name + ` must be set to start the server.
 		Category: flags.AccountCategory,
 	}
 
+	BLSRemoteSignerFlag = &cli.StringFlag{
+		Name:     "blsremote.url",
+		Usage:    "URL of the Web3Signer-compatible remote signer to sign the votes with, instead of the local BLS wallet",
+		Category: flags.AccountCategory,
+	}
+
+	BLSRemoteSignerPubKeyFlag = &cli.StringFlag{
+		Name:     "blsremote.pubkey",
+		Usage:    "Comma separated BLS public keys held by the remote signer to vote with, or \"*\" for all (default = first key of the signer)",
+		Category: flags.AccountCategory,
+	}
+
+	BLSRemoteSignerClientCertFlag = &cli.StringFlag{
+		Name:     "blsremote.tls.cert",
+		Usage:    "TLS client certificate file to authenticate to the remote signer",
+		Category: flags.AccountCategory,
+	}
+
+	BLSRemoteSignerClientKeyFlag = &cli.StringFlag{
+		Name:     "blsremote.tls.key",
+		Usage:    "TLS client key file to authenticate to the remote signer",
+		Category: flags.AccountCategory,
+	}
+
+	BLSRemoteSignerCACertFlag = &cli.StringFlag{
+		Name:     "blsremote.tls.ca",
+		Usage:    "CA certificate file to verify the remote signer (default = system roots)",
+		Category: flags.AccountCategory,
+	}
+
+	BLSRemoteSignerTimeoutFlag = &cli.DurationFlag{
+		Name:     "blsremote.timeout",
+		Usage:    "Timeout of a request to the remote signer",
+		Value:    5 * time.Second,
+		Category: flags.AccountCategory,
+	}
+
+	StandbyLeaseFlag = &cli.StringFlag{
+		Name:     "standby.lease",
+		Usage:    "Path of the lease file or URL of the lease server to run as a standby validator, which seals and votes only while holding the lease",
+		Category: flags.MinerCategory,
+	}
+
+	StandbyLeaseTTLFlag = &cli.DurationFlag{
+		Name:     "standby.leasettl",
+		Usage:    "Time for which the validator lease is acquired, bounding the downtime on a crash of the active node",
+		Value:    standby.DefaultLeaseTTL,
+		Category: flags.MinerCategory,
+	}
+
+	StandbyHolderFlag = &cli.StringFlag{
+		Name:     "standby.holder",
+		Usage:    "Name of the node holding the validator lease, unique among the nodes of the validator (default = node ID)",
+		Category: flags.MinerCategory,
+	}
+
 	VoteJournalDirFlag = &flags.DirectoryFlag{
 		Name:     "vote-journal-path",
 		Usage:    "Path for the voteJournal dir in fast finality feature (default = inside the datadir)",
 		Category: flags.FastFinalityCategory,
 	}
+	SlashingProtectionDirFlag = &flags.DirectoryFlag{
+		Name:     "vote.slashingprotection",
+		Usage:    "Path for the slashing protection database of the votes (default = inside the datadir)",
+		Category: flags.FastFinalityCategory,
+	}
+
+	VotePoolCurVotesFlag = &cli.Uint64Flag{
+		Name:     "votepool.curvotes",
+		Usage:    "Minimum number of votes kept per verified block, raised to the number of validators",
+		Value:    ethconfig.Defaults.VotePool.CurVotesPerBlock,
+		Category: flags.FastFinalityCategory,
+	}
+	VotePoolFutureVotesFlag = &cli.Uint64Flag{
+		Name:     "votepool.futurevotes",
+		Usage:    "Minimum number of votes kept per block not verified yet, raised to twice the number of validators",
+		Value:    ethconfig.Defaults.VotePool.FutureVotesPerBlock,
+		Category: flags.FastFinalityCategory,
+	}
+	VotePoolPruneDistanceFlag = &cli.Uint64Flag{
+		Name:     "votepool.prunedistance",
+		Usage:    "Number of blocks behind the head whose votes are kept in the vote pool",
+		Value:    ethconfig.Defaults.VotePool.PruneDistance,
+		Category: flags.FastFinalityCategory,
+	}
+	VotePoolFutureDistanceFlag = &cli.Uint64Flag{
+		Name:     "votepool.futuredistance",
+		Usage:    "Number of blocks ahead of the head whose votes are accepted by the vote pool",
+		Value:    ethconfig.Defaults.VotePool.FutureDistance,
+		Category: flags.FastFinalityCategory,
+	}
 
 	VoteKeyNameFlag = &cli.StringFlag{
 		Name:     "vote-key-name",
-		Usage:    "Name of the BLS public key used for voting (default = first found key)",
+		Usage:    "Comma separated names or public keys of the BLS keys used for voting, or \"*\" for all (default = first found key)",
 		Category: flags.FastFinalityCategory,
 	}
 
@@ -1196,6 +1334,11 @@ Please note that --` + MetricsHTTPFlag.Name + ` must be set to start the server.
 		Value:    params.DefaultExtraReserveForBlobRequests,
 		Category: flags.MiscCategory,
 	}
+	BlobArchiveFlag = &cli.StringFlag{
+		Name:     "blob.archive",
+		Usage:    "Directory of the archive retaining the blob sidecars pruned beyond the extra reserve (disabled if empty)",
+		Category: flags.MiscCategory,
+	}
 
 	// Fake beacon
 	FakeBeaconEnabledFlag = &cli.BoolFlag{
@@ -1495,6 +1638,15 @@ func setMonitors(ctx *cli.Context, cfg *node.Config) {
 	if ctx.Bool(EnableMaliciousVoteMonitorFlag.Name) {
 		cfg.EnableMaliciousVoteMonitor = true
 	}
+	if ctx.IsSet(MaliciousVoteReporterFlag.Name) {
+		cfg.MaliciousVoteReporter = ctx.String(MaliciousVoteReporterFlag.Name)
+	}
+	if ctx.Bool(EnableFinalityReporterFlag.Name) {
+		cfg.EnableFinalityReporter = true
+	}
+	if ctx.Bool(EnableVoteParticipationIndexerFlag.Name) {
+		cfg.EnableVoteParticipationIndexer = true
+	}
 }
 
 // MakeDatabaseHandles raises out the number of allowed file handles per process
@@ -1656,6 +1808,9 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	setMonitors(ctx, cfg)
 	setBLSWalletDir(ctx, cfg)
 	setVoteJournalDir(ctx, cfg)
+	if ctx.IsSet(SlashingProtectionDirFlag.Name) {
+		cfg.SlashingProtectionDir = ctx.String(SlashingProtectionDirFlag.Name)
+	}
 
 	if ctx.IsSet(JWTSecretFlag.Name) {
 		cfg.JWTSecret = ctx.String(JWTSecretFlag.Name)
@@ -1701,6 +1856,8 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(VoteKeyNameFlag.Name) {
 		cfg.VoteKeyName = ctx.String(VoteKeyNameFlag.Name)
 	}
+	setBLSRemoteSigner(ctx, cfg)
+	setStandby(ctx, cfg)
 	if ctx.IsSet(DBEngineFlag.Name) {
 		dbEngine := ctx.String(DBEngineFlag.Name)
 		if dbEngine != "leveldb" && dbEngine != "pebble" {
@@ -1756,6 +1913,39 @@ func setVoteJournalDir(ctx *cli.Context, cfg *node.Config) {
 	}
 }
 
+func setBLSRemoteSigner(ctx *cli.Context, cfg *node.Config) {
+	if ctx.IsSet(BLSRemoteSignerFlag.Name) {
+		cfg.BLSRemoteSigner = ctx.String(BLSRemoteSignerFlag.Name)
+	}
+	if ctx.IsSet(BLSRemoteSignerPubKeyFlag.Name) {
+		cfg.BLSRemoteSignerPubKey = ctx.String(BLSRemoteSignerPubKeyFlag.Name)
+	}
+	if ctx.IsSet(BLSRemoteSignerClientCertFlag.Name) {
+		cfg.BLSRemoteSignerClientCert = ctx.String(BLSRemoteSignerClientCertFlag.Name)
+	}
+	if ctx.IsSet(BLSRemoteSignerClientKeyFlag.Name) {
+		cfg.BLSRemoteSignerClientKey = ctx.String(BLSRemoteSignerClientKeyFlag.Name)
+	}
+	if ctx.IsSet(BLSRemoteSignerCACertFlag.Name) {
+		cfg.BLSRemoteSignerCACert = ctx.String(BLSRemoteSignerCACertFlag.Name)
+	}
+	if ctx.IsSet(BLSRemoteSignerTimeoutFlag.Name) || cfg.BLSRemoteSignerTimeout == 0 {
+		cfg.BLSRemoteSignerTimeout = ctx.Duration(BLSRemoteSignerTimeoutFlag.Name)
+	}
+}
+
+func setStandby(ctx *cli.Context, cfg *node.Config) {
+	if ctx.IsSet(StandbyLeaseFlag.Name) {
+		cfg.StandbyLease = ctx.String(StandbyLeaseFlag.Name)
+	}
+	if ctx.IsSet(StandbyLeaseTTLFlag.Name) || cfg.StandbyLeaseTTL == 0 {
+		cfg.StandbyLeaseTTL = ctx.Duration(StandbyLeaseTTLFlag.Name)
+	}
+	if ctx.IsSet(StandbyHolderFlag.Name) {
+		cfg.StandbyHolder = ctx.String(StandbyHolderFlag.Name)
+	}
+}
+
 func setBLSWalletDir(ctx *cli.Context, cfg *node.Config) {
 	dataDir := cfg.DataDir
 	if ctx.IsSet(BLSWalletDirFlag.Name) {
@@ -1841,6 +2031,21 @@ func setBlobPool(ctx *cli.Context, cfg *blobpool.Config) {
 	}
 }
 
+func setVotePool(ctx *cli.Context, cfg *voteconfig.Config) {
+	if ctx.IsSet(VotePoolCurVotesFlag.Name) {
+		cfg.CurVotesPerBlock = ctx.Uint64(VotePoolCurVotesFlag.Name)
+	}
+	if ctx.IsSet(VotePoolFutureVotesFlag.Name) {
+		cfg.FutureVotesPerBlock = ctx.Uint64(VotePoolFutureVotesFlag.Name)
+	}
+	if ctx.IsSet(VotePoolPruneDistanceFlag.Name) {
+		cfg.PruneDistance = ctx.Uint64(VotePoolPruneDistanceFlag.Name)
+	}
+	if ctx.IsSet(VotePoolFutureDistanceFlag.Name) {
+		cfg.FutureDistance = ctx.Uint64(VotePoolFutureDistanceFlag.Name)
+	}
+}
+
 func setMiner(ctx *cli.Context, cfg *minerconfig.Config) {
 	if ctx.IsSet(MinerExtraDataFlag.Name) {
 		cfg.ExtraData = []byte(ctx.String(MinerExtraDataFlag.Name))
@@ -1873,6 +2078,27 @@ func setMiner(ctx *cli.Context, cfg *minerconfig.Config) {
 	if ctx.Bool(DisableSuspiciousTxFilterFlag.Name) {
 		cfg.DisableSuspiciousTxFilter = true
 	}
+	if ctx.IsSet(SuspiciousTxFilterBackendFlag.Name) {
+		cfg.SuspiciousTxFilterBackend = ctx.String(SuspiciousTxFilterBackendFlag.Name)
+	}
+	if ctx.IsSet(SuspiciousTxFilterBuiltinFlag.Name) {
+		cfg.SuspiciousTxFilterBuiltin = ctx.String(SuspiciousTxFilterBuiltinFlag.Name)
+	}
+	if cfg.SuspiciousTxFilterBackend == core.TxfilterBackendBuiltin && !slices.Contains(core.SuspiciousTxfilters(), cfg.SuspiciousTxFilterBuiltin) {
+		Fatalf("Unknown builtin suspicious tx filter %q, available: %v", cfg.SuspiciousTxFilterBuiltin, core.SuspiciousTxfilters())
+	}
+	if ctx.IsSet(SuspiciousTxFilterSidecarFlag.Name) {
+		cfg.SuspiciousTxFilterSidecar = ctx.String(SuspiciousTxFilterSidecarFlag.Name)
+	}
+	if ctx.Bool(SuspiciousTxFilterShadowFlag.Name) {
+		cfg.SuspiciousTxFilterShadow = true
+	}
+	if ctx.IsSet(SuspiciousTxFilterSourcesFlag.Name) {
+		cfg.SuspiciousTxFilterSources = SplitAndTrim(ctx.String(SuspiciousTxFilterSourcesFlag.Name))
+	}
+	if ctx.IsSet(SuspiciousTxFilterMetadataHashFlag.Name) {
+		cfg.SuspiciousTxFilterMetadataHash = ctx.String(SuspiciousTxFilterMetadataHashFlag.Name)
+	}
 }
 
 func setRequiredBlocks(ctx *cli.Context, cfg *ethconfig.Config) {
@@ -1914,6 +2140,7 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	setGPO(ctx, &cfg.GPO)
 	setTxPool(ctx, &cfg.TxPool)
 	setBlobPool(ctx, &cfg.BlobPool)
+	setVotePool(ctx, &cfg.VotePool)
 	setMiner(ctx, &cfg.Miner)
 	setRequiredBlocks(ctx, cfg)
 
@@ -2253,6 +2480,12 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 		}
 		cfg.BlobExtraReserve = extraReserve
 	}
+	if ctx.IsSet(BlobArchiveFlag.Name) {
+		cfg.BlobArchive = ctx.String(BlobArchiveFlag.Name)
+	}
+	if cfg.BlobArchive != "" {
+		cfg.BlobArchive = stack.ResolvePath(cfg.BlobArchive)
+	}
 	// VM tracing config.
 	if ctx.IsSet(VMTraceFlag.Name) {
 		if name := ctx.String(VMTraceFlag.Name); name != "" {
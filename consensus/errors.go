@@ -38,4 +38,8 @@ var (
 	// ErrInvalidTerminalBlock is returned if a block is invalid wrt. the terminal
 	// total difficulty.
 	ErrInvalidTerminalBlock = errors.New("invalid terminal block")
+
+	// ErrStandbyInactive is returned when sealing a block on a standby validator
+	// node which doesn't hold the validator lease.
+	ErrStandbyInactive = errors.New("standby validator without the lease")
 )
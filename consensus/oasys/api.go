@@ -3,11 +3,13 @@ package oasys
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -77,6 +79,148 @@ func (api *API) GetSignersAtHash(hash common.Hash) ([]common.Address, error) {
 	return snap.validators(), nil
 }
 
+// ValidatorTurn is the sealing priority of a validator in a block.
+type ValidatorTurn struct {
+	Validator   common.Address `json:"validator"`
+	BackOffTime uint64         `json:"backOffTime"` // Seconds added to the block period
+}
+
+// BlockSchedule is the expected validator and the back-off order of a block.
+type BlockSchedule struct {
+	Number   uint64           `json:"number"`
+	Expected common.Address   `json:"expected"`
+	Turns    []*ValidatorTurn `json:"turns"` // Sorted in order of sealing priority
+}
+
+// EpochSchedule is the validator schedule of all blocks in an epoch.
+type EpochSchedule struct {
+	Epoch       uint64                    `json:"epoch"`
+	StartBlock  uint64                    `json:"startBlock"`
+	EndBlock    uint64                    `json:"endBlock"`
+	BlockPeriod uint64                    `json:"blockPeriod"`
+	Counts      map[common.Address]uint64 `json:"counts"` // Number of in-turn blocks per validator
+	Blocks      []*BlockSchedule          `json:"blocks"`
+}
+
+// GetSchedule retrieves the validator schedule of the given epoch (or current if
+// none requested). The schedule of the next epoch is available once the last
+// block of the current epoch has been imported.
+func (api *API) GetSchedule(epoch *uint64) (*EpochSchedule, error) {
+	head := api.chain.CurrentHeader()
+	snap, err := api.oasys.snapshot(api.chain, head.Number.Uint64(), head.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	env := snap.Environment
+	target := env.Epoch(head.Number.Uint64())
+	if epoch != nil {
+		target = *epoch
+	}
+	if target < params.InitialEnvironmentValue(api.oasys.config).StartEpoch.Uint64() {
+		return nil, fmt.Errorf("unknown epoch %d", target)
+	}
+	// Walk back the environment changes to find the one that applies to the epoch.
+	for target < env.StartEpoch.Uint64() {
+		header := api.chain.GetHeaderByNumber(env.StartBlock.Uint64() - 1)
+		if header == nil {
+			return nil, errUnknownBlock
+		}
+		if snap, err = api.oasys.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil); err != nil {
+			return nil, err
+		}
+		env = snap.Environment
+	}
+
+	start := env.NewValueStartBlock(target)
+	env, scheduler, err := api.schedulerAt(max(start, 1))
+	if err != nil {
+		return nil, err
+	}
+	schedule := &EpochSchedule{
+		Epoch:       target,
+		StartBlock:  start,
+		EndBlock:    start + env.EpochPeriod.Uint64() - 1,
+		BlockPeriod: env.BlockPeriod.Uint64(),
+		Counts:      make(map[common.Address]uint64),
+		Blocks:      make([]*BlockSchedule, 0, env.EpochPeriod.Uint64()),
+	}
+	for number := schedule.StartBlock; number <= schedule.EndBlock; number++ {
+		block := newBlockSchedule(scheduler, number)
+		schedule.Counts[block.Expected]++
+		schedule.Blocks = append(schedule.Blocks, block)
+	}
+	return schedule, nil
+}
+
+// GetTurns retrieves the expected validator and the back-off order of the given
+// block (or the next block if none requested).
+func (api *API) GetTurns(number *rpc.BlockNumber) (*BlockSchedule, error) {
+	head := api.chain.CurrentHeader().Number.Uint64()
+	target := head + 1
+	if number != nil {
+		switch {
+		case *number >= 0:
+			target = uint64(number.Int64())
+		case *number == rpc.LatestBlockNumber:
+			target = head
+		case *number != rpc.PendingBlockNumber:
+			return nil, fmt.Errorf("unsupported block number: %s", number)
+		}
+	}
+	if target == 0 {
+		return nil, errUnknownBlock
+	}
+	_, scheduler, err := api.schedulerAt(target)
+	if err != nil {
+		return nil, err
+	}
+	return newBlockSchedule(scheduler, target), nil
+}
+
+// schedulerAt returns the scheduler responsible for the given block number,
+// which must not be beyond the epoch of the block following the current head.
+func (api *API) schedulerAt(number uint64) (*params.EnvironmentValue, *scheduler, error) {
+	head := api.chain.CurrentHeader()
+
+	var header *types.Header
+	if number <= head.Number.Uint64() {
+		if header = api.chain.GetHeaderByNumber(number); header == nil {
+			return nil, nil, errUnknownBlock
+		}
+	} else {
+		// The validators of the next block are decided by the current head.
+		header = &types.Header{
+			Number:     new(big.Int).Add(head.Number, common.Big1),
+			ParentHash: head.Hash(),
+		}
+	}
+	env, scheduler, err := api.oasys.schedulerAt(api.chain, header)
+	if err != nil {
+		return nil, nil, err
+	}
+	if env.GetFirstBlock(number) != env.GetFirstBlock(header.Number.Uint64()) {
+		return nil, nil, fmt.Errorf("schedule of block %d is not determined until block %d",
+			number, env.GetFirstBlock(number)-1)
+	}
+	return env, scheduler, nil
+}
+
+func newBlockSchedule(scheduler *scheduler, number uint64) *BlockSchedule {
+	orders := scheduler.orders(number)
+	block := &BlockSchedule{
+		Number:   number,
+		Expected: *scheduler.expect(number),
+		Turns:    make([]*ValidatorTurn, len(orders)),
+	}
+	for i, validator := range orders {
+		block.Turns[i] = &ValidatorTurn{
+			Validator:   validator,
+			BackOffTime: scheduler.backOffTime(number, validator),
+		}
+	}
+	return block
+}
+
 // Proposals returns the current proposals the node tries to uphold and vote on.
 func (api *API) Proposals() map[common.Address]bool {
 	api.oasys.lock.RLock()
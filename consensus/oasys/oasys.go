@@ -17,6 +17,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/misc"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core/standby"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -197,6 +198,7 @@ type Oasys struct {
 	VotePool consensus.VotePool
 	txSigner types.Signer
 	txSignFn TxSignerFn
+	standby  *standby.Manager // Refuses to seal without the lease if set
 
 	// The fields below are for testing only
 	fakeDiff bool // Skip difficulty verifications
@@ -1179,6 +1181,103 @@ func (c *Oasys) IsActiveValidatorAt(chain consensus.ChainHeaderReader, header *t
 	return false
 }
 
+// ActiveValidatorCount returns the number of the validators eligible to vote for the block.
+func (c *Oasys) ActiveValidatorCount(chain consensus.ChainHeaderReader, header *types.Header) (int, error) {
+	number := header.Number.Uint64()
+	snap, err := c.snapshot(chain, number-1, header.ParentHash, nil)
+	if err != nil {
+		return 0, err
+	}
+	validators, err := c.getNextValidators(chain, header, snap, true)
+	if err != nil {
+		return 0, err
+	}
+	return len(validators.Operators), nil
+}
+
+// AttestationVoter is a validator eligible to vote for the attested block.
+type AttestationVoter struct {
+	Owner       common.Address     `json:"owner"`
+	Operator    common.Address     `json:"operator"`
+	VoteAddress types.BLSPublicKey `json:"voteAddress"`
+	Voted       bool               `json:"voted"`
+}
+
+// AttestationVoters returns the vote attestation in the header and the validators
+// eligible to vote for its target block, marking those whose votes are included.
+// If the header has no attestation, all the validators are returned as not voted
+// unless the fast finality is disabled at the target block.
+func (c *Oasys) AttestationVoters(chain consensus.ChainHeaderReader, header *types.Header) (*types.VoteAttestation, []*AttestationVoter, error) {
+	if header.Number.Uint64() < 2 {
+		return nil, nil, nil // no vote for the genesis
+	}
+	parent := chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	if parent == nil {
+		return nil, nil, consensus.ErrUnknownAncestor
+	}
+	attestation := c.DecodeVoteAttestation(header)
+	if attestation != nil && attestation.Data == nil {
+		attestation = nil
+	}
+	if attestation == nil && !chain.Config().IsFastFinalityEnabled(parent.Number) {
+		return nil, nil, nil
+	}
+	// The same validators as verifyVoteAttestation.
+	snap, err := c.snapshot(chain, parent.Number.Uint64()-1, parent.ParentHash, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	validators, err := c.getNextValidators(chain, header, snap, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The validators taken from the snapshot have no owners, which are resolved
+	// from the validators in the first block of the epoch.
+	var owners map[common.Address]common.Address
+	if len(validators.Owners) == len(validators.Operators) {
+		owners = make(map[common.Address]common.Address, len(validators.Operators))
+		for i, operator := range validators.Operators {
+			owners[operator] = validators.Owners[i]
+		}
+	} else {
+		owners = c.epochOwners(chain, snap.Environment.GetFirstBlock(header.Number.Uint64()))
+	}
+	votedSet := new(bitset.BitSet)
+	if attestation != nil {
+		votedSet = bitset.From([]uint64{uint64(attestation.VoteAddressSet)})
+	}
+	voters := make([]*AttestationVoter, len(validators.Operators))
+	for i := range validators.Operators {
+		voters[i] = &AttestationVoter{
+			Owner:       owners[validators.Operators[i]],
+			Operator:    validators.Operators[i],
+			VoteAddress: validators.VoteAddresses[i],
+			Voted:       votedSet.Test(uint(i + 1)),
+		}
+	}
+	return attestation, voters, nil
+}
+
+// epochOwners returns the owners of the validators by operator, taken from the
+// canonical first block of the epoch. It returns nil if the block is unknown or
+// has no validators in the extra data, which is before the fast finality.
+func (c *Oasys) epochOwners(chain consensus.ChainHeaderReader, number uint64) map[common.Address]common.Address {
+	header := chain.GetHeaderByNumber(number)
+	if header == nil || !c.chainConfig.IsFastFinalityEnabled(header.Number) {
+		return nil
+	}
+	validators, err := getValidatorsFromHeader(header)
+	if err != nil {
+		log.Debug("Failed to get validators from the epoch header", "number", number, "err", err)
+		return nil
+	}
+	owners := make(map[common.Address]common.Address, len(validators.Operators))
+	for i, operator := range validators.Operators {
+		owners[operator] = validators.Owners[i]
+	}
+	return owners
+}
+
 // Period returns the period of corresponding epoch. In case of any error, it returns the value in the config.
 func (c *Oasys) Period(chain consensus.ChainHeaderReader, header *types.Header) uint64 {
 	number := header.Number.Uint64()
@@ -1195,6 +1294,20 @@ func (c *Oasys) Period(chain consensus.ChainHeaderReader, header *types.Header)
 	return env.EpochPeriod.Uint64()
 }
 
+// EnvironmentAt returns the environment value of the epoch of the given header.
+func (c *Oasys) EnvironmentAt(chain consensus.ChainHeaderReader, header *types.Header) (*params.EnvironmentValue, error) {
+	number := header.Number.Uint64()
+	snap, err := c.snapshot(chain, number-1, header.ParentHash, nil)
+	if err != nil {
+		return nil, err
+	}
+	env, err := c.environment(chain, header, snap, true)
+	if err != nil {
+		return nil, err
+	}
+	return env.Copy(), nil
+}
+
 // VerifyVote will verify: 1. If the vote comes from valid validators 2. If the vote's sourceNumber and sourceHash are correct
 func (c *Oasys) VerifyVote(chain consensus.ChainHeaderReader, vote *types.VoteEnvelope) error {
 	targetNumber := vote.Data.TargetNumber
@@ -1251,6 +1364,15 @@ func (c *Oasys) Authorize(signer common.Address, signFn SignerFn, txSignFn TxSig
 	c.txSignFn = txSignFn
 }
 
+// SetStandby makes the engine refuse to seal the blocks unless the standby
+// manager holds the validator lease.
+func (c *Oasys) SetStandby(standby *standby.Manager) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.standby = standby
+}
+
 // Seal implements consensus.Engine, attempting to create a sealed block using
 // the local signing credentials.
 func (c *Oasys) Seal(chain consensus.ChainHeaderReader, block *types.Block, results chan<- *types.Block, stop <-chan struct{}) error {
@@ -1267,9 +1389,14 @@ func (c *Oasys) Seal(chain consensus.ChainHeaderReader, block *types.Block, resu
 	}
 	// Don't hold the signer fields for the entire sealing procedure
 	c.lock.RLock()
-	validator, signFn := c.signer, c.signFn
+	validator, signFn, standby := c.signer, c.signFn, c.standby
 	c.lock.RUnlock()
 
+	// Bail out if another node of the validator holds the lease
+	if standby != nil && !standby.Active() {
+		return consensus.ErrStandbyInactive
+	}
+
 	// Bail out if we're unauthorized to sign a block
 	snap, err := c.snapshot(chain, number-1, header.ParentHash, nil)
 	if err != nil {
@@ -1303,6 +1430,12 @@ func (c *Oasys) Seal(chain consensus.ChainHeaderReader, block *types.Block, resu
 			log.Error("Assemble vote attestation failed when sealing", "err", err)
 		}
 
+		// The lease might have been lost while waiting
+		if standby != nil && !standby.Active() {
+			log.Warn("Discarded the sealing block as the validator lease is lost", "number", number)
+			return
+		}
+
 		// Sign all the things!
 		sighash, err := signFn(accounts.Account{Address: validator}, accounts.MimetypeOasys, OasysRLP(header))
 		if err != nil {
@@ -1649,20 +1782,39 @@ func (c *Oasys) scheduler(chain consensus.ChainHeaderReader, header *types.Heade
 		return cache.(*scheduler), nil
 	}
 
-	var seed int64
-	if env.Epoch(number) >= c.chainConfig.OasysShortenedBlockTimeStartEpoch().Uint64() {
-		// This has nothing to do with reducing block time, but it has been fixed for possible overflow.
-		seed = new(big.Int).Mod(seedHash.Big(), bigMaxInt64).Int64()
-	} else {
-		seed = seedHash.Big().Int64()
-	}
-
+	seed := schedulerSeed(c.chainConfig, env.Epoch(number), seedHash)
 	created := newScheduler(env, env.GetFirstBlock(number),
 		newWeightedChooser(validators, stakes, seed))
 	schedulerCache.Add(seedHash, created)
 	return created, nil
 }
 
+// Returns the environment and the scheduler that apply to the given header.
+// The header does not need to be sealed, so that the schedule of the block
+// following the current head can also be previewed.
+func (c *Oasys) schedulerAt(chain consensus.ChainHeaderReader, header *types.Header) (*params.EnvironmentValue, *scheduler, error) {
+	number := header.Number.Uint64()
+	snap, err := c.snapshot(chain, number-1, header.ParentHash, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve snapshot, in: schedulerAt, blockNumber: %d, parentHash: %x, err: %v", number, header.ParentHash, err)
+	}
+	// Unsealed headers do not contain the validators in the extra field.
+	fromHeader := len(header.Extra) > 0
+	env, err := c.environment(chain, header, snap, fromHeader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get environment, in: schedulerAt, err: %v", err)
+	}
+	validators, err := c.getNextValidators(chain, header, snap, fromHeader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get validators, in: schedulerAt, err: %v", err)
+	}
+	scheduler, err := c.scheduler(chain, header, env, validators.Operators, validators.Stakes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get scheduler, in: schedulerAt, blockNumber: %d, err: %v", number, err)
+	}
+	return env, scheduler, nil
+}
+
 // Argument leftOver is the time reserved for block finalize(calculate root, distribute income...)
 func (c *Oasys) Delay(chain consensus.ChainReader, header *types.Header, leftOver *time.Duration) *time.Duration {
 	number := header.Number.Uint64()
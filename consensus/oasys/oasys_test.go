@@ -5,9 +5,12 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/internal/testrand"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/require"
 )
 
@@ -104,6 +107,105 @@ func TestIsSufficientVotes(t *testing.T) {
 	}
 }
 
+func TestAttestationVoters(t *testing.T) {
+	var (
+		config      = &params.OasysConfig{Period: 15, Epoch: 10}
+		chainConfig = &params.ChainConfig{ChainID: big.NewInt(999), Oasys: config}
+		env         = params.InitialEnvironmentValue(config)
+		engine      = New(chainConfig, config, rawdb.NewMemoryDatabase(), nil)
+		size        = 3
+		validators  = &nextValidators{
+			Owners:        make([]common.Address, size),
+			Operators:     make([]common.Address, size),
+			Stakes:        make([]*big.Int, size),
+			VoteAddresses: make([]types.BLSPublicKey, size),
+		}
+	)
+	for i := 0; i < size; i++ {
+		validators.Owners[i] = testrand.Address()
+		validators.Operators[i] = testrand.Address()
+		validators.Stakes[i] = newEth(int64(i + 1))
+		validators.VoteAddresses[i] = randomBLSPublicKey()
+	}
+	newSnap := func(header *types.Header) *Snapshot {
+		snap := newSnapshot(chainConfig, nil, nil, header.Number.Uint64(), header.Hash(), validators.Operators, env)
+		for i, operator := range validators.Operators {
+			snap.Validators[operator].Stake = validators.Stakes[i]
+			snap.Validators[operator].VoteAddress = validators.VoteAddresses[i]
+		}
+		return snap
+	}
+
+	// The first validator voted for the parent of the non-epoch block.
+	attestation, err := rlp.EncodeToBytes(&types.VoteAttestation{VoteAddressSet: 1 << 1, Data: &types.VoteData{TargetNumber: 11}})
+	require.NoError(t, err)
+	chain := &testChainReader{config: chainConfig}
+	for number := int64(0); number <= 12; number++ {
+		header := &types.Header{Number: big.NewInt(number), Extra: make([]byte, extraVanity)}
+		switch number {
+		case 10:
+			header.Extra = append(header.Extra, assembleEnvironmentValue(env)...)
+			header.Extra = append(header.Extra, assembleValidators(validators)...)
+		case 12:
+			header.Extra = append(header.Extra, attestation...)
+		}
+		header.Extra = append(header.Extra, make([]byte, extraSeal)...)
+		if number > 0 {
+			header.ParentHash = chain.headers[number-1].Hash()
+		}
+		chain.headers = append(chain.headers, header)
+	}
+	engine.recents.Add(chain.headers[8].Hash(), newSnap(chain.headers[8]))
+	engine.recents.Add(chain.headers[10].Hash(), newSnap(chain.headers[10]))
+
+	tests := []struct {
+		name     string
+		number   int
+		attested bool
+		voted    []bool
+	}{
+		{name: "epoch block", number: 10, voted: []bool{false, false, false}},
+		{name: "non-epoch block", number: 12, attested: true, voted: []bool{true, false, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attestation, voters, err := engine.AttestationVoters(chain, chain.headers[tt.number])
+			require.NoError(t, err)
+			require.Equal(t, tt.attested, attestation != nil)
+			require.Len(t, voters, size)
+			for i, voter := range voters {
+				require.Equal(t, validators.Owners[i], voter.Owner)
+				require.Equal(t, validators.Operators[i], voter.Operator)
+				require.Equal(t, validators.VoteAddresses[i], voter.VoteAddress)
+				require.Equal(t, tt.voted[i], voter.Voted)
+			}
+		})
+	}
+}
+
+// testChainReader is a canonical chain of the headers.
+type testChainReader struct {
+	consensus.ChainHeaderReader
+	config  *params.ChainConfig
+	headers []*types.Header
+}
+
+func (r *testChainReader) Config() *params.ChainConfig { return r.config }
+
+func (r *testChainReader) GetHeaderByNumber(number uint64) *types.Header {
+	if number >= uint64(len(r.headers)) {
+		return nil
+	}
+	return r.headers[number]
+}
+
+func (r *testChainReader) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header := r.GetHeaderByNumber(number); header != nil && header.Hash() == hash {
+		return header
+	}
+	return nil
+}
+
 func newEth(n int64) *big.Int {
 	return new(big.Int).Mul(big.NewInt(n), big.NewInt(params.Ether))
 }
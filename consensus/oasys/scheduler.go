@@ -147,6 +147,16 @@ func (s *scheduler) turn(number uint64, validator common.Address) (uint64, error
 	}
 }
 
+// Returns the validators of the given block in order of sealing priority.
+func (s *scheduler) orders(number uint64) []common.Address {
+	validators := make([]common.Address, len(s.chooser.validators))
+	for _, validator := range s.chooser.validators {
+		turn, _ := s.turn(number, validator)
+		validators[turn] = validator
+	}
+	return validators
+}
+
 func (s *scheduler) backOffTime(number uint64, validator common.Address) uint64 {
 	turn, err := s.turn(number, validator)
 	if errors.Is(err, errUnauthorizedValidator) || turn == 0 {
@@ -258,6 +268,15 @@ func newWeightedChooser(
 	return chooser
 }
 
+// Convert the hash of the last block of the previous epoch to the random seed of the scheduler.
+func schedulerSeed(config *params.ChainConfig, epoch uint64, seedHash common.Hash) int64 {
+	if epoch >= config.OasysShortenedBlockTimeStartEpoch().Uint64() {
+		// This has nothing to do with reducing block time, but it has been fixed for possible overflow.
+		return new(big.Int).Mod(seedHash.Big(), bigMaxInt64).Int64()
+	}
+	return seedHash.Big().Int64()
+}
+
 func getPrevEpochLastBlockHash(
 	config *params.OasysConfig,
 	chain consensus.ChainHeaderReader,
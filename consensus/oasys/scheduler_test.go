@@ -197,3 +197,28 @@ func TestDifficulty(t *testing.T) {
 		}
 	}
 }
+
+func TestOrders(t *testing.T) {
+	env := &params.EnvironmentValue{
+		StartBlock:  common.Big0,
+		StartEpoch:  common.Big1,
+		EpochPeriod: epochPeriod,
+	}
+
+	for _, s := range wantSchedules {
+		chooser := newWeightedChooser(validators, stakes, int64(env.GetFirstBlock(s.block)))
+		scheduler := newScheduler(env, env.GetFirstBlock(s.block), chooser)
+
+		want := make([]common.Address, len(validators))
+		for i, validator := range validators {
+			want[s.turns[i]] = validator
+		}
+
+		got := scheduler.orders(s.block)
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("order mismatch, block %v, turn %v, got %v, want %v", s.block, i, names[got[i]], names[want[i]])
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package oasys
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Downtime is a range of blocks (inclusive) in which the validator does not seal.
+type Downtime struct {
+	Validator common.Address `json:"validator"`
+	From      uint64         `json:"from"`
+	To        uint64         `json:"to"`
+}
+
+// SimulatedBlock is the simulated result of a block.
+type SimulatedBlock struct {
+	Number   uint64         `json:"number"`
+	Expected common.Address `json:"expected"`
+	Sealer   common.Address `json:"sealer"` // Zero address if all validators are down
+	Slashed  bool           `json:"slashed"`
+}
+
+// SimulatedValidator is the simulated result of a validator.
+type SimulatedValidator struct {
+	Operator common.Address `json:"operator"`
+	Stake    *big.Int       `json:"stake"`
+	Expected uint64         `json:"expected"` // Number of blocks scheduled to the validator
+	Sealed   uint64         `json:"sealed"`   // Number of blocks actually sealed, including the out-of-turn blocks
+	Missed   uint64         `json:"missed"`   // Number of blocks counted by `StakeManager.slash`
+	Jailed   bool           `json:"jailed"`
+}
+
+// ScheduleSimulation is the result of SimulateSchedule.
+type ScheduleSimulation struct {
+	Epoch      uint64                `json:"epoch"`
+	StartBlock uint64                `json:"startBlock"`
+	EndBlock   uint64                `json:"endBlock"`
+	Seed       int64                 `json:"seed"`
+	Blocks     []*SimulatedBlock     `json:"blocks,omitempty"`
+	Validators []*SimulatedValidator `json:"validators"`
+}
+
+// SimulateSchedule calculates the validator schedule of the epoch in the same way
+// as the consensus engine, and replays the given downtimes against it.
+//
+// A block whose expected validator is down is sealed by the next validator in turn
+// and the expected validator is slashed, as the engine does in `Finalize`. The jail
+// outcome is an approximation of the StakeManager contract: the validator is jailed
+// when the number of slashed blocks in the epoch reaches `JailThreshold`.
+func SimulateSchedule(
+	config *params.ChainConfig,
+	env *params.EnvironmentValue,
+	epoch uint64,
+	operators []common.Address,
+	stakes []*big.Int,
+	seedHash common.Hash,
+	downtimes []Downtime,
+) (*ScheduleSimulation, error) {
+	if config.Oasys == nil {
+		return nil, errors.New("not an oasys chain config")
+	}
+	if len(operators) == 0 || len(operators) != len(stakes) {
+		return nil, errors.New("mismatching number of operators and stakes")
+	}
+	if epoch < env.StartEpoch.Uint64() {
+		return nil, fmt.Errorf("epoch %d is before the environment start epoch %d", epoch, env.StartEpoch)
+	}
+
+	var (
+		start  = env.NewValueStartBlock(epoch)
+		period = env.EpochPeriod.Uint64()
+		seed   int64
+	)
+	// The seed and the slashing are disabled in the first epoch, see `Oasys.scheduler`.
+	slashing := start >= config.Oasys.Epoch
+	if slashing {
+		seed = schedulerSeed(config, epoch, seedHash)
+	}
+	s := newScheduler(env, start, newWeightedChooser(operators, stakes, seed))
+
+	result := &ScheduleSimulation{
+		Epoch:      epoch,
+		StartBlock: start,
+		EndBlock:   start + period - 1,
+		Seed:       seed,
+		Blocks:     make([]*SimulatedBlock, 0, period),
+	}
+	validators := make(map[common.Address]*SimulatedValidator)
+	for i, operator := range operators {
+		if _, ok := validators[operator]; ok {
+			return nil, fmt.Errorf("duplicate operator: %s", operator)
+		}
+		v := &SimulatedValidator{Operator: operator, Stake: new(big.Int).Set(stakes[i])}
+		validators[operator] = v
+		result.Validators = append(result.Validators, v)
+	}
+	isDown := func(validator common.Address, number uint64) bool {
+		for _, d := range downtimes {
+			if d.Validator == validator && d.From <= number && number <= d.To {
+				return true
+			}
+		}
+		return false
+	}
+
+	for i, expected := range s.schedules() {
+		number := start + uint64(i)
+		block := &SimulatedBlock{Number: number, Expected: *expected}
+		validators[*expected].Expected++
+
+		for _, validator := range s.orders(number) {
+			if !isDown(validator, number) {
+				block.Sealer = validator
+				validators[validator].Sealed++
+				break
+			}
+		}
+		if slashing && block.Sealer != *expected {
+			block.Slashed = true
+			validators[*expected].Missed++
+		}
+		result.Blocks = append(result.Blocks, block)
+	}
+	for _, v := range result.Validators {
+		v.Jailed = new(big.Int).SetUint64(v.Missed).Cmp(env.JailThreshold) >= 0
+	}
+	return result, nil
+}
@@ -0,0 +1,103 @@
+package oasys
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestSimulateSchedule(t *testing.T) {
+	config := &params.ChainConfig{
+		ChainID: big.NewInt(12345),
+		Oasys:   &params.OasysConfig{Period: 15, Epoch: epochPeriod.Uint64()},
+	}
+	env := &params.EnvironmentValue{
+		StartBlock:    common.Big0,
+		StartEpoch:    common.Big1,
+		EpochPeriod:   epochPeriod,
+		JailThreshold: big.NewInt(5),
+	}
+	// validator-0 is down for the whole epoch and validator-1 for a few blocks.
+	downtimes := []Downtime{
+		{Validator: validators[0], From: 40, To: 79},
+		{Validator: validators[1], From: 40, To: 42},
+	}
+	// The seed hash is chosen to reproduce `wantSchedules`.
+	result, err := SimulateSchedule(config, env, 2, validators, stakes, common.BigToHash(big.NewInt(40)), downtimes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.StartBlock != 40 || result.EndBlock != 79 || len(result.Blocks) != 40 {
+		t.Fatalf("unexpected range: start=%d end=%d blocks=%d", result.StartBlock, result.EndBlock, len(result.Blocks))
+	}
+
+	var (
+		expected = map[common.Address]uint64{}
+		missed   = map[common.Address]uint64{}
+		sealed   = map[common.Address]uint64{}
+	)
+	for i, s := range wantSchedules[:40] {
+		block := result.Blocks[i]
+		if block.Number != s.block {
+			t.Fatalf("block number mismatch, got: %d, want: %d", block.Number, s.block)
+		}
+
+		orders := make([]common.Address, len(validators))
+		for j, turn := range s.turns {
+			orders[turn] = validators[j]
+		}
+		if block.Expected != orders[0] {
+			t.Errorf("block %d, expected mismatch, got: %s, want: %s", s.block, names[block.Expected], names[orders[0]])
+		}
+		var want common.Address
+		for _, validator := range orders {
+			if !(validator == validators[0] || (validator == validators[1] && s.block <= 42)) {
+				want = validator
+				break
+			}
+		}
+		if block.Sealer != want {
+			t.Errorf("block %d, sealer mismatch, got: %s, want: %s", s.block, names[block.Sealer], names[want])
+		}
+		if block.Slashed != (want != orders[0]) {
+			t.Errorf("block %d, slashed mismatch, got: %v", s.block, block.Slashed)
+		}
+
+		expected[orders[0]]++
+		sealed[want]++
+		if want != orders[0] {
+			missed[orders[0]]++
+		}
+	}
+
+	for _, v := range result.Validators {
+		if v.Expected != expected[v.Operator] {
+			t.Errorf("%s, expected blocks mismatch, got: %d, want: %d", names[v.Operator], v.Expected, expected[v.Operator])
+		}
+		if v.Sealed != sealed[v.Operator] {
+			t.Errorf("%s, sealed blocks mismatch, got: %d, want: %d", names[v.Operator], v.Sealed, sealed[v.Operator])
+		}
+		if v.Missed != missed[v.Operator] {
+			t.Errorf("%s, missed blocks mismatch, got: %d, want: %d", names[v.Operator], v.Missed, missed[v.Operator])
+		}
+		if want := v.Missed >= 5; v.Jailed != want {
+			t.Errorf("%s, jailed mismatch, got: %v, want: %v", names[v.Operator], v.Jailed, want)
+		}
+	}
+	if !result.Validators[0].Jailed || result.Validators[0].Sealed != 0 {
+		t.Errorf("validator-0 should be jailed without sealing any block")
+	}
+
+	// Slashing is disabled in the first epoch.
+	result, err = SimulateSchedule(config, env, 1, validators, stakes, common.Hash{}, downtimes[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range result.Validators {
+		if v.Missed != 0 || v.Jailed {
+			t.Errorf("%s, unexpected slashing in the first epoch", names[v.Operator])
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package oasys
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// EpochSlots is the in-turn slots of a validator in an epoch, each of which is
+// slashed in `Finalize` if the block is sealed by another validator.
+type EpochSlots struct {
+	Epoch         uint64   `json:"epoch"`
+	StartBlock    uint64   `json:"startBlock"`
+	EndBlock      uint64   `json:"endBlock"`
+	BlockPeriod   uint64   `json:"blockPeriod"`
+	JailThreshold uint64   `json:"jailThreshold"`
+	Slashing      bool     `json:"slashing"` // False in the first epoch, in which no one is slashed
+	Slots         []uint64 `json:"slots"`    // Numbers of the blocks scheduled to the validator
+}
+
+// InTurnSlots returns the in-turn slots of the validator in the epoch of the
+// block following the head.
+func (c *Oasys) InTurnSlots(chain consensus.ChainHeaderReader, head *types.Header, validator common.Address) (*EpochSlots, error) {
+	// The validators of the next block are decided by the head.
+	next := &types.Header{
+		Number:     new(big.Int).Add(head.Number, common.Big1),
+		ParentHash: head.Hash(),
+	}
+	env, scheduler, err := c.schedulerAt(chain, next)
+	if err != nil {
+		return nil, err
+	}
+	number := next.Number.Uint64()
+	slots := &EpochSlots{
+		Epoch:         env.Epoch(number),
+		StartBlock:    env.GetFirstBlock(number),
+		BlockPeriod:   env.BlockPeriod.Uint64(),
+		JailThreshold: env.JailThreshold.Uint64(),
+		Slashing:      env.GetFirstBlock(number) >= c.config.Epoch,
+	}
+	slots.EndBlock = slots.StartBlock + env.EpochPeriod.Uint64() - 1
+	for i, expected := range scheduler.schedules() {
+		if *expected == validator {
+			slots.Slots = append(slots.Slots, slots.StartBlock+uint64(i))
+		}
+	}
+	return slots, nil
+}
+
+// EpochForecast is the forecast of the in-turn slots of a validator in the next
+// epoch. The schedule of an epoch is seeded by the hash of the last block of the
+// previous one, so the slots themselves are unknown until it's sealed, and only
+// their expected number is forecast from the stakes of the next validators.
+type EpochForecast struct {
+	Epoch       uint64 `json:"epoch"`
+	StartBlock  uint64 `json:"startBlock"`
+	EndBlock    uint64 `json:"endBlock"`
+	BlockPeriod uint64 `json:"blockPeriod"`
+	Elected     bool   `json:"elected"`  // Whether the validator is in the next validator set
+	Expected    uint64 `json:"expected"` // Expected number of the slots weighted by the stake
+}
+
+// NextEpochSlots forecasts the in-turn slots of the validator in the epoch after
+// the one of the block following the head, by the validators and environment
+// of the next epoch as of the state of the head. The block following the head
+// should not be the first block of its epoch, whose validators are not decided
+// by the state of the head yet.
+func (c *Oasys) NextEpochSlots(chain consensus.ChainHeaderReader, head *types.Header, validator common.Address) (*EpochForecast, error) {
+	next := &types.Header{
+		Number:     new(big.Int).Add(head.Number, common.Big1),
+		ParentHash: head.Hash(),
+	}
+	snap, err := c.snapshot(chain, head.Number.Uint64(), head.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	env, err := c.environment(chain, next, snap, false)
+	if err != nil {
+		return nil, err
+	}
+	nextEnv, err := getNextEnvironmentValue(c.ethAPI, head.Hash())
+	if err != nil {
+		return nil, err
+	}
+	number := next.Number.Uint64()
+	forecast := &EpochForecast{
+		Epoch:       env.Epoch(number) + 1,
+		StartBlock:  env.GetFirstBlock(number) + env.EpochPeriod.Uint64(),
+		BlockPeriod: nextEnv.BlockPeriod.Uint64(),
+	}
+	forecast.EndBlock = forecast.StartBlock + nextEnv.EpochPeriod.Uint64() - 1
+
+	validators, err := getNextValidators(c.chainConfig, c.ethAPI, head.Hash(), forecast.Epoch, forecast.StartBlock)
+	if err != nil {
+		return nil, err
+	}
+	// Weighted by the stakes in ether, as the scheduler chooses the validators.
+	var (
+		total = new(big.Int)
+		stake = new(big.Int)
+	)
+	for i, operator := range validators.Operators {
+		weight := new(big.Int).Div(validators.Stakes[i], ether)
+		total.Add(total, weight)
+		if operator == validator {
+			forecast.Elected = true
+			stake.Add(stake, weight)
+		}
+	}
+	if total.Sign() > 0 {
+		stake.Mul(stake, nextEnv.EpochPeriod)
+		forecast.Expected = stake.Div(stake, total).Uint64()
+	}
+	return forecast, nil
+}
@@ -28,6 +28,9 @@ const (
 
 	// Address of TransactionBlocker contract.
 	TransactionBlocker = BuiltInContractPrefix2 + "00000000000000000000004F"
+
+	// Address of SlashIndicator contract.
+	SlashIndicatorAddress = BuiltInContractPrefix2 + "000000000000000000000040"
 )
 
 var (
@@ -57,15 +60,9 @@ func Deploy(chainConfig *params.ChainConfig, state StateDB,
 	}
 
 	// Deploy built-in contracts when the block number reaches the specified block height.
-	deploymentMap, ok := deploymentSets[GenesisHash]
-	if !ok {
-		deploymentMap = deploymentSets[defaultGenesisHash]
-	}
-	if deploymentSet, ok := deploymentMap[blockNumber.Uint64()]; ok {
-		for _, deployments := range deploymentSet {
-			for _, d := range deployments {
-				d.deploy(chainConfig, state, blockNumber)
-			}
+	for _, deployments := range deploymentsAt(chainConfig, blockNumber.Uint64()) {
+		for _, d := range deployments {
+			d.deploy(chainConfig, state, blockNumber)
 		}
 	}
 
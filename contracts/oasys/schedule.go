@@ -0,0 +1,339 @@
+package oasys
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Built-in deployment sets by name, which the chain config refers to.
+var namedDeployments = map[string][]*deployment{
+	"deployments0":                           deployments0,
+	"deployments1":                           deployments1,
+	"deployments2":                           deployments2,
+	"deployments3":                           deployments3,
+	"deployments4":                           deployments4,
+	"deployments5":                           deployments5,
+	"deployments6":                           deployments6,
+	"deployments7":                           deployments7,
+	"deployments8":                           deployments8,
+	"deployments9":                           deployments9,
+	"deployments10":                          deployments10,
+	"deployments11":                          deployments11,
+	"deployments12":                          deployments12,
+	"deployments13":                          deployments13,
+	"deployments14":                          deployments14,
+	"deployments14_slash_indicator_mainnet":  deployments14_slash_indicator_mainnet,
+	"deployments14_slash_indicator_testnet":  deployments14_slash_indicator_testnet,
+	"deployments14_slash_indicator_localnet": deployments14_slash_indicator_localnet,
+	"deployments15":                          deployments15,
+	"deployments15_deterministic_deployment_proxy": deployments15_deterministic_deployment_proxy,
+}
+
+// DeploymentSetNames returns the sorted names of the built-in deployment sets.
+func DeploymentSetNames() []string {
+	names := make([]string, 0, len(namedDeployments))
+	for name := range namedDeployments {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// deploymentsAt returns the deployments scheduled at the block. The schedule in
+// the chain config takes precedence over the one hard-coded for the genesis.
+func deploymentsAt(cfg *params.ChainConfig, number uint64) deploymentSet {
+	if cfg.Oasys.Deployments != nil {
+		var set deploymentSet
+		for _, scheduled := range cfg.Oasys.Deployments {
+			if scheduled.Block != number {
+				continue
+			}
+			for _, name := range scheduled.Sets {
+				set = append(set, namedDeployments[name])
+			}
+			for _, c := range scheduled.Contracts {
+				d, err := configDeployment(c)
+				if err != nil {
+					panic(fmt.Errorf("failed to load %s contract: %w", c.Address.Hex(), err))
+				}
+				set = append(set, []*deployment{d})
+			}
+		}
+		return set
+	}
+
+	deploymentMap, ok := deploymentSets[GenesisHash]
+	if !ok {
+		deploymentMap = deploymentSets[defaultGenesisHash]
+	}
+	return deploymentMap[number]
+}
+
+// configDeployment converts the contract defined in the chain config into the
+// deployment, with its storage laid out by the built-in storage builder.
+func configDeployment(c params.OasysContract) (*deployment, error) {
+	if c.Address == (common.Address{}) {
+		return nil, errors.New("no address")
+	}
+	if len(c.Code) == 0 && len(c.Storage) == 0 {
+		return nil, errors.New("neither code nor storage")
+	}
+	name := c.Name
+	if name == "" {
+		name = c.Address.Hex()
+	}
+	d := &deployment{
+		contract: &contract{name: name, address: c.Address.Hex()},
+		storage:  make(storage, len(c.Storage)),
+	}
+	if len(c.Code) > 0 {
+		d.code = c.Code
+	}
+	for slot, val := range c.Storage {
+		if !strings.HasPrefix(slot, hexPrefix) {
+			return nil, fmt.Errorf("storage slot %q is not hex", slot)
+		}
+		parsed, err := configStorageValue(val)
+		if err != nil {
+			return nil, fmt.Errorf("storage slot %s: %w", slot, err)
+		}
+		d.storage[slot] = parsed
+	}
+	return d, nil
+}
+
+// configStorageValue converts the storage value decoded from the JSON chain
+// config into the value of the storage builder.
+func configStorageValue(val interface{}) (interface{}, error) {
+	switch t := val.(type) {
+	case string:
+		return t, nil
+	case map[string]interface{}:
+		if len(t) != 1 {
+			return nil, fmt.Errorf("storage object must have a single member, have %d", len(t))
+		}
+		for kind, member := range t {
+			switch kind {
+			case "array", "struct":
+				values, ok := member.([]interface{})
+				if !ok {
+					return nil, fmt.Errorf("%s value must be a list, have %T", kind, member)
+				}
+				parsed := make([]interface{}, len(values))
+				for i, v := range values {
+					var err error
+					if parsed[i], err = configStorageValue(v); err != nil {
+						return nil, fmt.Errorf("%s[%d]: %w", kind, i, err)
+					}
+				}
+				if kind == "struct" {
+					return structvalue(parsed), nil
+				}
+				arr := &array{values: make(map[int64]interface{}, len(parsed))}
+				for i, v := range parsed {
+					arr.values[int64(i)] = v
+				}
+				return arr, nil
+			case "mapping":
+				values, ok := member.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("mapping value must be an object, have %T", member)
+				}
+				m := &mapping{keyFn: addressKeyFn, values: make(map[string]interface{}, len(values))}
+				for key, v := range values {
+					parsed, err := configStorageValue(v)
+					if err != nil {
+						return nil, fmt.Errorf("mapping[%s]: %w", key, err)
+					}
+					m.values[key] = parsed
+				}
+				return m, nil
+			default:
+				return nil, fmt.Errorf("unknown storage object %q, want array, mapping or struct", kind)
+			}
+		}
+	}
+	return nil, fmt.Errorf("unsupported storage value type %T", val)
+}
+
+// ValidateDeployments checks the deployment schedule in the chain config, so
+// that a misconfigured chain fails at startup instead of at the activation block.
+func ValidateDeployments(cfg *params.ChainConfig) error {
+	if cfg == nil || cfg.Oasys == nil || cfg.Oasys.Deployments == nil {
+		return nil
+	}
+
+	blocks := make(map[uint64]bool)
+	for i, scheduled := range cfg.Oasys.Deployments {
+		if scheduled.Block == 0 {
+			return fmt.Errorf("oasys deployments[%d]: block must be greater than 0", i)
+		}
+		if blocks[scheduled.Block] {
+			return fmt.Errorf("oasys deployments[%d]: block %d is scheduled twice", i, scheduled.Block)
+		}
+		blocks[scheduled.Block] = true
+		if len(scheduled.Sets) == 0 && len(scheduled.Contracts) == 0 {
+			return fmt.Errorf("oasys deployments[%d]: no deployment set or contract", i)
+		}
+
+		// The code deployed last to each address at the block
+		codes := make(map[common.Address][]byte)
+		for _, name := range scheduled.Sets {
+			deployments, ok := namedDeployments[name]
+			if !ok {
+				return fmt.Errorf("oasys deployments[%d]: unknown deployment set %q, available: %v", i, name, DeploymentSetNames())
+			}
+			for _, d := range deployments {
+				if _, err := d.storage.build(cfg); err != nil {
+					return fmt.Errorf("oasys deployments[%d]: failed to build %s contract storage map in %s: %w", i, d.contract.name, name, err)
+				}
+				if d.code != nil {
+					codes[common.HexToAddress(d.contract.address)] = d.code
+				}
+			}
+		}
+		for j, c := range scheduled.Contracts {
+			d, err := configDeployment(c)
+			if err != nil {
+				return fmt.Errorf("oasys deployments[%d]: invalid contracts[%d]: %w", i, j, err)
+			}
+			if _, err := d.storage.build(cfg); err != nil {
+				return fmt.Errorf("oasys deployments[%d]: failed to build %s contract storage map: %w", i, d.contract.name, err)
+			}
+			if d.code != nil {
+				codes[c.Address] = d.code
+			}
+		}
+		for address, want := range scheduled.CodeHashes {
+			code, ok := codes[address]
+			if !ok {
+				return fmt.Errorf("oasys deployments[%d]: no code is deployed to %s", i, address.Hex())
+			}
+			if got := crypto.Keccak256Hash(code); got != want {
+				return fmt.Errorf("oasys deployments[%d]: code hash mismatch for %s: have %s, want %s", i, address.Hex(), got.Hex(), want.Hex())
+			}
+		}
+	}
+	return nil
+}
+
+// DeploymentSchedule returns the effective deployment schedule of the chain,
+// with the hashes of the deployed code. It is the schedule in the chain config
+// if set, otherwise the one hard-coded for the genesis.
+func DeploymentSchedule(cfg *params.ChainConfig, genesisHash common.Hash) ([]params.OasysDeployment, error) {
+	if cfg == nil || cfg.Oasys == nil {
+		return nil, errors.New("not an oasys chain")
+	}
+	if cfg.Oasys.Deployments != nil {
+		return cfg.Oasys.Deployments, nil
+	}
+
+	deploymentMap, ok := deploymentSets[genesisHash]
+	if !ok {
+		deploymentMap = deploymentSets[defaultGenesisHash]
+	}
+	schedule := make([]params.OasysDeployment, 0, len(deploymentMap))
+	for block, set := range deploymentMap {
+		scheduled := params.OasysDeployment{Block: block, CodeHashes: make(map[common.Address]common.Hash)}
+		for _, deployments := range set {
+			name, err := deploymentSetName(deployments)
+			if err != nil {
+				return nil, err
+			}
+			scheduled.Sets = append(scheduled.Sets, name)
+			for _, d := range deployments {
+				if d.code != nil {
+					scheduled.CodeHashes[common.HexToAddress(d.contract.address)] = crypto.Keccak256Hash(d.code)
+				}
+			}
+		}
+		schedule = append(schedule, scheduled)
+	}
+	slices.SortFunc(schedule, func(a, b params.OasysDeployment) int {
+		return cmp.Compare(a.Block, b.Block)
+	})
+	return schedule, nil
+}
+
+func deploymentSetName(deployments []*deployment) (string, error) {
+	for name, named := range namedDeployments {
+		if slices.Equal(named, deployments) {
+			return name, nil
+		}
+	}
+	return "", errors.New("unnamed deployment set")
+}
+
+// CheckDeploymentsCompatible checks whether the deployment schedule of the new
+// chain config deploys the same contracts as the stored one up to the head, and
+// returns the error to rewind the chain to before the first changed block if not.
+// The schedule of a config without one is the one hard-coded for the genesis.
+func CheckDeploymentsCompatible(stored, newcfg *params.ChainConfig, genesisHash common.Hash, head uint64) *params.ConfigCompatError {
+	if stored == nil || stored.Oasys == nil || newcfg == nil || newcfg.Oasys == nil {
+		return nil
+	}
+	var (
+		storedSchedule = scheduleDeployments(stored, genesisHash)
+		newSchedule    = scheduleDeployments(newcfg, genesisHash)
+		changed        = uint64(math.MaxUint64)
+	)
+	for _, schedule := range []map[uint64][]interface{}{storedSchedule, newSchedule} {
+		for block := range schedule {
+			if block < changed && !slices.Equal(storedSchedule[block], newSchedule[block]) {
+				changed = block
+			}
+		}
+	}
+	if changed > head {
+		return nil
+	}
+	number := new(big.Int).SetUint64(changed)
+	err := &params.ConfigCompatError{What: "Oasys deployments", StoredBlock: number, NewBlock: number}
+	if changed > 0 {
+		err.RewindToBlock = changed - 1
+	}
+	return err
+}
+
+// scheduleDeployments returns the deployments of the chain config by block, which
+// are the built-in deployments or the contracts defined in the config.
+func scheduleDeployments(cfg *params.ChainConfig, genesisHash common.Hash) map[uint64][]interface{} {
+	schedule := make(map[uint64][]interface{})
+	if cfg.Oasys.Deployments != nil {
+		for _, scheduled := range cfg.Oasys.Deployments {
+			for _, name := range scheduled.Sets {
+				for _, d := range namedDeployments[name] {
+					schedule[scheduled.Block] = append(schedule[scheduled.Block], d)
+				}
+			}
+			for _, c := range scheduled.Contracts {
+				// The contracts are compared by the encoding, which sorts the storage.
+				enc, _ := json.Marshal(c)
+				schedule[scheduled.Block] = append(schedule[scheduled.Block], string(enc))
+			}
+		}
+		return schedule
+	}
+	deploymentMap, ok := deploymentSets[genesisHash]
+	if !ok {
+		deploymentMap = deploymentSets[defaultGenesisHash]
+	}
+	for block, set := range deploymentMap {
+		for _, deployments := range set {
+			for _, d := range deployments {
+				schedule[block] = append(schedule[block], d)
+			}
+		}
+	}
+	return schedule
+}
@@ -0,0 +1,206 @@
+package oasys
+
+import (
+	"encoding/json"
+	"maps"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestDeploymentSchedule(t *testing.T) {
+	defer func() { GenesisHash = common.Hash{} }()
+
+	for _, tc := range []struct {
+		network     string
+		chainConfig *params.ChainConfig
+		genesisHash common.Hash
+	}{
+		{"mainnet", params.OasysMainnetChainConfig, params.OasysMainnetGenesisHash},
+		{"testnet", params.OasysTestnetChainConfig, params.OasysTestnetGenesisHash},
+		{"others", params.OasysTestChainConfig, common.Hash{}},
+	} {
+		GenesisHash = tc.genesisHash
+
+		schedule, err := DeploymentSchedule(tc.chainConfig, tc.genesisHash)
+		if err != nil {
+			t.Fatalf("%s: failed to get schedule: %v", tc.network, err)
+		}
+		oasys := *tc.chainConfig.Oasys
+		oasys.Deployments = schedule
+		config := *tc.chainConfig
+		config.Oasys = &oasys
+
+		if err := ValidateDeployments(&config); err != nil {
+			t.Fatalf("%s: invalid dumped schedule: %v", tc.network, err)
+		}
+
+		// The dumped schedule deploys exactly the same sets as the hard-coded one
+		wants, ok := deploymentSets[tc.genesisHash]
+		if !ok {
+			wants = deploymentSets[defaultGenesisHash]
+		}
+		if len(schedule) != len(wants) {
+			t.Fatalf("%s: schedule size mismatch, got %d, want %d", tc.network, len(schedule), len(wants))
+		}
+		for block, want := range wants {
+			got := deploymentsAt(&config, block)
+			if len(got) != len(want) {
+				t.Fatalf("%s/block#%d: set count mismatch, got %d, want %d", tc.network, block, len(got), len(want))
+			}
+			for i := range want {
+				if len(got[i]) != len(want[i]) || got[i][0] != want[i][0] {
+					t.Errorf("%s/block#%d: set #%d mismatch", tc.network, block, i)
+				}
+			}
+			if hardcoded := deploymentsAt(tc.chainConfig, block); len(hardcoded) != len(want) {
+				t.Errorf("%s/block#%d: hard-coded set count mismatch, got %d, want %d", tc.network, block, len(hardcoded), len(want))
+			}
+		}
+		if got := deploymentsAt(&config, 3); len(wants[3]) == 0 && len(got) != 0 {
+			t.Errorf("%s/block#3: unexpected deployments", tc.network)
+		}
+	}
+}
+
+func TestValidateDeployments(t *testing.T) {
+	wantHash := common.HexToHash("0x01")
+	woas := common.HexToAddress(wrappedOAS.address)
+
+	for _, tc := range []struct {
+		name        string
+		deployments []params.OasysDeployment
+		wantErr     string
+	}{
+		{"empty", []params.OasysDeployment{}, ""},
+		{"valid", []params.OasysDeployment{{Block: 2, Sets: []string{"deployments0", "deployments1"}}}, ""},
+		{"genesis block", []params.OasysDeployment{{Block: 0, Sets: []string{"deployments0"}}}, "block must be greater than 0"},
+		{"duplicate block", []params.OasysDeployment{{Block: 2, Sets: []string{"deployments0"}}, {Block: 2, Sets: []string{"deployments1"}}}, "scheduled twice"},
+		{"no set", []params.OasysDeployment{{Block: 2}}, "no deployment set or contract"},
+		{"contract", []params.OasysDeployment{{Block: 2, Contracts: []params.OasysContract{{Address: woas, Code: []byte{1}}}, CodeHashes: map[common.Address]common.Hash{woas: crypto.Keccak256Hash([]byte{1})}}}, ""},
+		{"contract without address", []params.OasysDeployment{{Block: 2, Contracts: []params.OasysContract{{Code: []byte{1}}}}}, "no address"},
+		{"empty contract", []params.OasysDeployment{{Block: 2, Contracts: []params.OasysContract{{Address: woas}}}}, "neither code nor storage"},
+		{"invalid storage", []params.OasysDeployment{{Block: 2, Contracts: []params.OasysContract{{Address: woas, Storage: map[string]interface{}{"0x00": map[string]interface{}{"set": []interface{}{}}}}}}}, `unknown storage object "set"`},
+		{"unknown set", []params.OasysDeployment{{Block: 2, Sets: []string{"deployments99"}}}, `unknown deployment set "deployments99"`},
+		{"code hash mismatch", []params.OasysDeployment{{Block: 2, Sets: []string{"deployments0"}, CodeHashes: map[common.Address]common.Hash{woas: wantHash}}}, "code hash mismatch"},
+		{"no code", []params.OasysDeployment{{Block: 2, Sets: []string{"deployments0"}, CodeHashes: map[common.Address]common.Hash{{}: wantHash}}}, "no code is deployed"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			oasys := *params.OasysTestChainConfig.Oasys
+			oasys.Deployments = tc.deployments
+			config := *params.OasysTestChainConfig
+			config.Oasys = &oasys
+
+			err := ValidateDeployments(&config)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("error mismatch, got %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigContractDeployment(t *testing.T) {
+	var deployments []params.OasysDeployment
+	if err := json.Unmarshal([]byte(`[{
+		"block": 5,
+		"contracts": [{
+			"name": "Custom",
+			"address": "0x5200000000000000000000000000000000001234",
+			"code": "0x6001",
+			"storage": {
+				"0x00": "0x5200000000000000000000000000000000000001",
+				"0x01": "token",
+				"0x02": {"mapping": {"0x5200000000000000000000000000000000000002": "0x01"}},
+				"0x03": {"array": ["0x0a", {"struct": ["0x0b", "0x0c"]}]}
+			}
+		}]
+	}]`), &deployments); err != nil {
+		t.Fatal(err)
+	}
+	oasys := *params.OasysTestChainConfig.Oasys
+	oasys.Deployments = deployments
+	config := *params.OasysTestChainConfig
+	config.Oasys = &oasys
+	if err := ValidateDeployments(&config); err != nil {
+		t.Fatalf("invalid deployments: %v", err)
+	}
+
+	state := make(mockStateDB)
+	Deploy(&config, state, big.NewInt(4), 0, 1)
+	if len(state) != 0 {
+		t.Fatalf("deployed before the block: %d contracts", len(state))
+	}
+	Deploy(&config, state, big.NewInt(5), 0, 1)
+
+	address := common.HexToAddress("0x5200000000000000000000000000000000001234")
+	if code := state.GetCode(address); string(code) != "\x60\x01" {
+		t.Errorf("code mismatch: have %x", code)
+	}
+	// The same layout as the built-in contracts
+	want, err := storage{
+		"0x00": "0x5200000000000000000000000000000000000001",
+		"0x01": "token",
+		"0x02": &mapping{keyFn: addressKeyFn, values: map[string]interface{}{"0x5200000000000000000000000000000000000002": "0x01"}},
+		"0x03": &array{values: map[int64]interface{}{0: "0x0a", 1: structvalue{"0x0b", "0x0c"}}},
+	}.build(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have := state.getContract(address).storage; !maps.Equal(have, want) {
+		t.Errorf("storage mismatch:\nhave %v\nwant %v", have, want)
+	}
+}
+
+func TestCheckDeploymentsCompatible(t *testing.T) {
+	schedule, err := DeploymentSchedule(params.OasysTestChainConfig, common.Hash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	withDeployments := func(deployments []params.OasysDeployment) *params.ChainConfig {
+		oasys := *params.OasysTestChainConfig.Oasys
+		oasys.Deployments = deployments
+		config := *params.OasysTestChainConfig
+		config.Oasys = &oasys
+		return &config
+	}
+	contract := params.OasysContract{Address: common.HexToAddress("0x5200000000000000000000000000000000001234"), Code: []byte{1}}
+	extended := append(append([]params.OasysDeployment{}, schedule...), params.OasysDeployment{Block: 10, Contracts: []params.OasysContract{contract}})
+	changed := append([]params.OasysDeployment{}, extended...)
+	changed[len(changed)-1] = params.OasysDeployment{Block: 10, Contracts: []params.OasysContract{{Address: contract.Address, Code: []byte{2}}}}
+
+	for _, tc := range []struct {
+		name       string
+		stored     *params.ChainConfig
+		new        *params.ChainConfig
+		head       uint64
+		wantRewind *uint64
+	}{
+		{"hard-coded", params.OasysTestChainConfig, params.OasysTestChainConfig, 100, nil},
+		{"dumped schedule", params.OasysTestChainConfig, withDeployments(schedule), 100, nil},
+		{"added before head", params.OasysTestChainConfig, withDeployments(extended), 100, newUint64(9)},
+		{"added after head", params.OasysTestChainConfig, withDeployments(extended), 9, nil},
+		{"changed before head", withDeployments(extended), withDeployments(changed), 10, newUint64(9)},
+		{"removed before head", withDeployments(extended), params.OasysTestChainConfig, 10, newUint64(9)},
+		{"all removed", params.OasysTestChainConfig, withDeployments([]params.OasysDeployment{}), 100, newUint64(1)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckDeploymentsCompatible(tc.stored, tc.new, common.Hash{}, tc.head)
+			switch {
+			case tc.wantRewind == nil && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tc.wantRewind != nil && err == nil:
+				t.Fatal("no error")
+			case tc.wantRewind != nil && err.RewindToBlock != *tc.wantRewind:
+				t.Fatalf("rewind mismatch, have %d, want %d", err.RewindToBlock, *tc.wantRewind)
+			}
+		})
+	}
+}
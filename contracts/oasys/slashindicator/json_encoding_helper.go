@@ -1,4 +1,4 @@
-package main
+package slashindicator
 
 import (
 	"encoding/json"
@@ -309,6 +309,9 @@ type BlockStats struct {
 	SendVoteTime         atomic.Int64
 	FirstRecvVoteTime    atomic.Int64
 	RecvMajorityVoteTime atomic.Int64
+
+	JustifiedTime atomic.Int64
+	FinalizedTime atomic.Int64
 }
 
 // BlockChain represents the canonical chain given a database with a genesis
@@ -394,6 +397,8 @@ type BlockChain struct {
 
 	// monitor
 	doubleSignMonitor *monitor.DoubleSignMonitor
+
+	sidecarArchive *rawdb.SidecarArchive // Optional archive of the blob sidecars pruned
 }
 
 // NewBlockChain returns a fully initialised block chain using information
@@ -424,6 +429,9 @@ func NewBlockChain(db ethdb.Database, genesis *Genesis, engine consensus.Engine,
 		return nil, err
 	}
 	contracts.GenesisHash = genesisHash
+	if err := contracts.ValidateDeployments(chainConfig); err != nil {
+		return nil, err
+	}
 	// log.Info("Initialised chain configuration", "config", chainConfig)
 	log.Info("")
 	log.Info(strings.Repeat("-", 153))
@@ -1855,6 +1863,31 @@ func (bc *BlockChain) writeKnownBlock(block *types.Block) error {
 	return nil
 }
 
+// WriteBlobSidecars stores the sidecars of a local block missing them, which
+// are cleaned on importing the blocks out of the data availability window, or
+// pruned. The sidecars are verified against the blob transactions of the block.
+func (bc *BlockChain) WriteBlobSidecars(hash common.Hash, sidecars types.BlobSidecars) error {
+	number := bc.hc.GetBlockNumber(hash)
+	if number == nil {
+		return fmt.Errorf("unknown block %x", hash)
+	}
+	block := bc.GetBlock(hash, *number)
+	if block == nil {
+		return fmt.Errorf("unknown block %x", hash)
+	}
+	if !bc.chainConfig.IsCancun(block.Number(), block.Time()) {
+		return fmt.Errorf("block %d before cancun has no sidecars", *number)
+	}
+	if err := VerifySidecars(bc.chainConfig, block, sidecars); err != nil {
+		return err
+	}
+	// The sidecars of the frozen blocks are kept in the key-value store, as the
+	// ancients are immutable.
+	rawdb.WriteBlobSidecars(bc.db, hash, *number, sidecars)
+	bc.sidecarsCache.Add(hash, sidecars)
+	return nil
+}
+
 // writeBlockWithState writes block, metadata and corresponding state data to the
 // database.
 func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.Receipt, statedb *state.StateDB) error {
@@ -2037,6 +2070,7 @@ func (bc *BlockChain) writeBlockAndSetHead(block *types.Block, receipts []*types
 			if finalizedHeader = pos.GetFinalizedHeader(bc, block.Header()); finalizedHeader != nil {
 				bc.SetFinalized(finalizedHeader)
 			}
+			bc.recordFinalityStats(pos, block.Header(), finalizedHeader)
 		}
 		if sealedBlockSender != nil {
 			bc.chainHeadFeed.Send(ChainHeadEvent{Header: block.Header()})
@@ -3284,6 +3318,27 @@ func EnableDoubleSignChecker(bc *BlockChain) (*BlockChain, error) {
 	return bc, nil
 }
 
+// WithDoubleSignEvidenceStore persists the evidence detected by the double sign
+// checker into the store. It must be applied after EnableDoubleSignChecker.
+func WithDoubleSignEvidenceStore(store *monitor.EvidenceStore) BlockChainOption {
+	return func(bc *BlockChain) (*BlockChain, error) {
+		if bc.doubleSignMonitor == nil {
+			return nil, errors.New("double sign checker is not enabled")
+		}
+		bc.doubleSignMonitor.SetEvidenceStore(store)
+		return bc, nil
+	}
+}
+
+// WithSidecarArchive serves the blob sidecars from the archive once pruned from
+// the database.
+func WithSidecarArchive(archive *rawdb.SidecarArchive) BlockChainOption {
+	return func(bc *BlockChain) (*BlockChain, error) {
+		bc.sidecarArchive = archive
+		return bc, nil
+	}
+}
+
 // InsertHeadersBeforeCutoff inserts the given headers into the ancient store
 // as they are claimed older than the configured chain cutoff point. All the
 // inserted headers are regarded as canonical and chain reorg is not supported.
@@ -3388,6 +3443,25 @@ func (bc *BlockChain) GetBlockStats(hash common.Hash) *BlockStats {
 	return n
 }
 
+// PeekBlockStats returns the stats of the block without creating them, or nil
+// if the block is not recorded.
+func (bc *BlockChain) PeekBlockStats(hash common.Hash) *BlockStats {
+	stats, _ := bc.blockStatsCache.Peek(hash)
+	return stats
+}
+
+// recordFinalityStats records the time when the blocks justified and finalized
+// by the new head are first seen.
+func (bc *BlockChain) recordFinalityStats(pos consensus.PoS, head *types.Header, finalized *types.Header) {
+	now := time.Now().UnixMilli()
+	if number, hash, err := pos.GetJustifiedNumberAndHash(bc, []*types.Header{head}); err == nil && number > 0 {
+		bc.GetBlockStats(hash).JustifiedTime.CompareAndSwap(0, now)
+	}
+	if finalized != nil && finalized.Number.Sign() > 0 {
+		bc.GetBlockStats(finalized.Hash()).FinalizedTime.CompareAndSwap(0, now)
+	}
+}
+
 // PruneBlockHistory prune block history
 func (bc *BlockChain) PruneBlockHistory(blockHistory uint64) error {
 	// if the node try to keep entire chain blocks, just skip
@@ -17,6 +17,7 @@
 package core
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math/big"
@@ -307,6 +308,9 @@ func (bc *BlockChain) GetSidecarsByHash(hash common.Hash) types.BlobSidecars {
 		return nil
 	}
 	sidecars := rawdb.ReadBlobSidecars(bc.db, hash, *number)
+	if len(sidecars) == 0 && bc.sidecarArchive != nil {
+		sidecars = bc.sidecarArchive.ReadSidecars(hash, *number)
+	}
 	if sidecars == nil {
 		return nil
 	}
@@ -332,6 +336,21 @@ func (bc *BlockChain) GetReceiptsRLP(hash common.Hash) rlp.RawValue {
 	return rawdb.ReadReceiptsRLP(bc.db, hash, *number)
 }
 
+// GetSidecarsRLP retrieves the sidecars of a block in RLP encoding.
+func (bc *BlockChain) GetSidecarsRLP(hash common.Hash) rlp.RawValue {
+	number := rawdb.ReadHeaderNumber(bc.db, hash)
+	if number == nil {
+		return nil
+	}
+	data := rawdb.ReadBlobSidecarsRLP(bc.db, hash, *number)
+	if (len(data) == 0 || bytes.Equal(data, rlp.EmptyList)) && bc.sidecarArchive != nil {
+		if archived := bc.sidecarArchive.ReadSidecarsRLP(hash, *number); len(archived) > 0 {
+			return archived
+		}
+	}
+	return data
+}
+
 // GetUnclesInChain retrieves all the uncles from a given block backwards until
 // a specific distance is reached.
 func (bc *BlockChain) GetUnclesInChain(block *types.Block, length int) []*types.Header {
@@ -82,7 +82,12 @@ func IsDataAvailable(chain consensus.ChainHeaderReader, block *types.Block) (err
 	if block.Sidecars() == nil {
 		block.CleanSidecars()
 	}
-	sidecars := block.Sidecars()
+	return VerifySidecars(chain.Config(), block, block.Sidecars())
+}
+
+// VerifySidecars checks that the sidecars carry the blobs of the blob
+// transactions in the block.
+func VerifySidecars(config *params.ChainConfig, block *types.Block, sidecars types.BlobSidecars) error {
 	for _, s := range sidecars {
 		if err := s.SanityCheck(block.Number(), block.Hash()); err != nil {
 			return err
@@ -107,7 +112,7 @@ func IsDataAvailable(chain consensus.ChainHeaderReader, block *types.Block) (err
 	for _, s := range sidecars {
 		blobCnt += len(s.Blobs)
 	}
-	maxBlobPerBlock := eip4844.MaxBlobsPerBlock(chain.Config(), block.Time())
+	maxBlobPerBlock := eip4844.MaxBlobsPerBlock(config, block.Time())
 	if blobCnt > maxBlobPerBlock {
 		return fmt.Errorf("too many blobs in block: have %d, permitted %d", blobCnt, maxBlobPerBlock)
 	}
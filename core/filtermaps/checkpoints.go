@@ -19,8 +19,12 @@ package filtermaps
 import (
 	_ "embed"
 	"encoding/json"
+	"fmt"
+	"io"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
 )
 
 // checkpointList lists checkpoints for finalized epochs of a given chain.
@@ -67,3 +71,50 @@ func decodeCheckpoints(encoded []byte) (result checkpointList) {
 	}
 	return
 }
+
+// ExportCheckpoints writes the checkpoints of the epochs indexed in the database
+// up to the final block, in the format of the embedded files. The log index has
+// to be complete from the genesis. It returns the number of the epochs exported.
+func ExportCheckpoints(db ethdb.KeyValueStore, params Params, finalBlock uint64, w io.Writer) (int, error) {
+	if err := params.sanitize(); err != nil {
+		return 0, err
+	}
+	finalLvPtr, err := rawdb.ReadBlockLvPointer(db, finalBlock+1)
+	if err != nil {
+		return 0, fmt.Errorf("log value pointer of block %d is missing: %v", finalBlock+1, err)
+	}
+	var (
+		epochCount = uint32(finalLvPtr >> (params.logValuesPerMap + params.logMapsPerEpoch))
+		list       = make(checkpointList, 0, epochCount)
+	)
+	for epoch := uint32(0); epoch < epochCount; epoch++ {
+		lastBlock, lastBlockId, err := rawdb.ReadFilterMapLastBlock(db, params.lastEpochMap(epoch))
+		if err != nil {
+			return 0, fmt.Errorf("last block of epoch %d is missing: %v", epoch, err)
+		}
+		lvPtr, err := rawdb.ReadBlockLvPointer(db, lastBlock)
+		if err != nil {
+			return 0, fmt.Errorf("log value pointer of block %d is missing: %v", lastBlock, err)
+		}
+		list = append(list, epochCheckpoint{BlockNumber: lastBlock, BlockId: lastBlockId, FirstIndex: lvPtr})
+	}
+	return len(list), writeCheckpoints(w, list)
+}
+
+// writeCheckpoints writes the checkpoints in the format of the embedded files.
+func writeCheckpoints(w io.Writer, list checkpointList) error {
+	if _, err := io.WriteString(w, "[\n"); err != nil {
+		return err
+	}
+	comma := ","
+	for i, cp := range list {
+		if i == len(list)-1 {
+			comma = ""
+		}
+		if _, err := fmt.Fprintf(w, "{\"blockNumber\": %d, \"blockId\": \"0x%064x\", \"firstIndex\": %d}%s\n", cp.BlockNumber, cp.BlockId, cp.FirstIndex, comma); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "]\n")
+	return err
+}
@@ -0,0 +1,46 @@
+package filtermaps
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExportCheckpoints(t *testing.T) {
+	ts := newTestSetup(t)
+	defer ts.close()
+
+	ts.chain.addBlocks(1000, 10, 3, 4, true)
+	ts.setHistory(0, false)
+	ts.fm.WaitIdle()
+
+	// The checkpoints exported from the database match the ones by the indexer
+	var buf bytes.Buffer
+	epochs, err := ExportCheckpoints(ts.db, ts.params, 998, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if epochs == 0 {
+		t.Fatal("no epochs exported")
+	}
+	list := decodeCheckpoints(buf.Bytes())
+	if len(list) != epochs {
+		t.Fatalf("checkpoints mismatch: have %d, want %d", len(list), epochs)
+	}
+	for i, cp := range list {
+		if ts.chain.GetCanonicalHash(cp.BlockNumber) != cp.BlockId {
+			t.Errorf("checkpoint %d: block %d is not canonical", i, cp.BlockNumber)
+		}
+	}
+	ts.fm.exportFileName = filepath.Join(t.TempDir(), "checkpoints.json")
+	ts.fm.finalBlock = 998
+	ts.fm.exportCheckpoints()
+	exported, err := os.ReadFile(ts.fm.exportFileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(exported, buf.Bytes()) {
+		t.Fatalf("checkpoints mismatch the indexer:\n%s\n%s", exported, buf.Bytes())
+	}
+}
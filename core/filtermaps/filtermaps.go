@@ -867,16 +867,7 @@ func (f *FilterMaps) exportCheckpoints() {
 	if epochCount == f.lastFinalEpoch {
 		return
 	}
-	w, err := os.Create(f.exportFileName)
-	if err != nil {
-		log.Error("Error creating checkpoint export file", "name", f.exportFileName, "error", err)
-		return
-	}
-	defer w.Close()
-
-	log.Info("Exporting log index checkpoints", "epochs", epochCount, "file", f.exportFileName)
-	w.WriteString("[\n")
-	comma := ","
+	list := make(checkpointList, 0, epochCount)
 	for epoch := uint32(0); epoch < epochCount; epoch++ {
 		lastBlock, lastBlockId, err := f.getLastBlockOfMap(f.lastEpochMap(epoch))
 		if err != nil {
@@ -888,11 +879,19 @@ func (f *FilterMaps) exportCheckpoints() {
 			log.Error("Error fetching log value pointer of last block", "block", lastBlock, "error", err)
 			return
 		}
-		if epoch == epochCount-1 {
-			comma = ""
-		}
-		w.WriteString(fmt.Sprintf("{\"blockNumber\": %d, \"blockId\": \"0x%064x\", \"firstIndex\": %d}%s\n", lastBlock, lastBlockId, lvPtr, comma))
+		list = append(list, epochCheckpoint{BlockNumber: lastBlock, BlockId: lastBlockId, FirstIndex: lvPtr})
+	}
+	w, err := os.Create(f.exportFileName)
+	if err != nil {
+		log.Error("Error creating checkpoint export file", "name", f.exportFileName, "error", err)
+		return
+	}
+	defer w.Close()
+
+	log.Info("Exporting log index checkpoints", "epochs", epochCount, "file", f.exportFileName)
+	if err := writeCheckpoints(w, list); err != nil {
+		log.Error("Error writing checkpoint export file", "name", f.exportFileName, "error", err)
+		return
 	}
-	w.WriteString("]\n")
 	f.lastFinalEpoch = epochCount
 }
@@ -0,0 +1,160 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// MaliciousVoteReporterAPI provides an API to inspect the evidence submitted
+// by the malicious vote reporter.
+type MaliciousVoteReporterAPI struct {
+	reporter *MaliciousVoteReporter
+}
+
+// NewMaliciousVoteReporterAPI creates a new API for the given reporter.
+func NewMaliciousVoteReporterAPI(reporter *MaliciousVoteReporter) *MaliciousVoteReporterAPI {
+	return &MaliciousVoteReporterAPI{reporter: reporter}
+}
+
+// MaliciousVoteReporterStatus is the status of the malicious vote reporter.
+type MaliciousVoteReporterStatus struct {
+	Account common.Address         `json:"account"`
+	Pending int                    `json:"pending"`
+	Reports []*MaliciousVoteReport `json:"reports"`
+}
+
+// MaliciousVoteReports returns the account, the number of pending evidence and
+// the recent reports of the malicious vote reporter.
+func (api *MaliciousVoteReporterAPI) MaliciousVoteReports() *MaliciousVoteReporterStatus {
+	return &MaliciousVoteReporterStatus{
+		Account: api.reporter.Account(),
+		Pending: api.reporter.Pending(),
+		Reports: api.reporter.Reports(),
+	}
+}
+
+const maxEvidencePageSize = 100
+
+// EvidenceAPI provides an API to query and subscribe the evidence of validator
+// misbehavior detected by the monitors.
+type EvidenceAPI struct {
+	store *EvidenceStore
+}
+
+// NewEvidenceAPI creates a new API for the given evidence store.
+func NewEvidenceAPI(store *EvidenceStore) *EvidenceAPI {
+	return &EvidenceAPI{store: store}
+}
+
+// EvidenceFilter is the criteria of the evidence query. The block range is
+// inclusive, and the offset is the number of matched evidence to skip.
+type EvidenceFilter struct {
+	FromBlock   *hexutil.Uint64     `json:"fromBlock"`
+	ToBlock     *hexutil.Uint64     `json:"toBlock"`
+	Kind        *EvidenceKind       `json:"kind"`
+	Validator   *common.Address     `json:"validator"`
+	VoteAddress *types.BLSPublicKey `json:"voteAddress"`
+	Offset      hexutil.Uint64      `json:"offset"`
+	Limit       hexutil.Uint64      `json:"limit"`
+}
+
+func (f *EvidenceFilter) matches(evidence *Evidence) bool {
+	if f.Kind != nil && *f.Kind != evidence.Kind {
+		return false
+	}
+	if f.Validator != nil && (evidence.Kind != DoubleSignEvidence || *f.Validator != evidence.Validator) {
+		return false
+	}
+	if f.VoteAddress != nil && (evidence.Kind != MaliciousVoteEvidence || *f.VoteAddress != evidence.VoteAddress) {
+		return false
+	}
+	return true
+}
+
+// RPCEvidence is the evidence with its identifier.
+type RPCEvidence struct {
+	ID common.Hash `json:"id"`
+	*Evidence
+}
+
+// EvidencePage is a page of the evidence query result.
+type EvidencePage struct {
+	Evidences []*RPCEvidence  `json:"evidences"`
+	Next      *hexutil.Uint64 `json:"next,omitempty"` // Offset of the next page, absent on the last page
+}
+
+// GetEvidence returns the stored evidence matching the filter in ascending order
+// of the block number.
+func (api *EvidenceAPI) GetEvidence(filter EvidenceFilter) (*EvidencePage, error) {
+	from, to := uint64(0), uint64(math.MaxUint64)
+	if filter.FromBlock != nil {
+		from = uint64(*filter.FromBlock)
+	}
+	if filter.ToBlock != nil {
+		to = uint64(*filter.ToBlock)
+	}
+	if from > to {
+		return nil, fmt.Errorf("invalid block range: %d > %d", from, to)
+	}
+	limit := uint64(filter.Limit)
+	if limit == 0 || limit > maxEvidencePageSize {
+		limit = maxEvidencePageSize
+	}
+
+	var (
+		page    = &EvidencePage{Evidences: []*RPCEvidence{}}
+		matched uint64
+	)
+	api.store.Iterate(from, to, func(evidence *Evidence) bool {
+		if !filter.matches(evidence) {
+			return true
+		}
+		matched++
+		if matched <= uint64(filter.Offset) {
+			return true
+		}
+		if uint64(len(page.Evidences)) == limit {
+			next := hexutil.Uint64(matched - 1)
+			page.Next = &next
+			return false
+		}
+		page.Evidences = append(page.Evidences, &RPCEvidence{ID: evidence.ID(), Evidence: evidence})
+		return true
+	})
+	return page, nil
+}
+
+// Evidence creates a subscription that is triggered each time new evidence is stored.
+func (api *EvidenceAPI) Evidence(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		evidences := make(chan *Evidence, 16)
+		evidencesSub := api.store.SubscribeEvidence(evidences)
+		defer evidencesSub.Unsubscribe()
+
+		for {
+			select {
+			case evidence := <-evidences:
+				notifier.Notify(rpcSub.ID, &RPCEvidence{ID: evidence.ID(), Evidence: evidence})
+			case <-evidencesSub.Err():
+				return
+			case <-rpcSub.Err():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
@@ -24,6 +24,12 @@ func NewDoubleSignMonitor() *DoubleSignMonitor {
 type DoubleSignMonitor struct {
 	headerNumbers *prque.Prque[int64, *types.Header]
 	headers       map[uint64]*types.Header
+	store         *EvidenceStore
+}
+
+// SetEvidenceStore sets the store to persist the evidence of double signing.
+func (m *DoubleSignMonitor) SetEvidenceStore(store *EvidenceStore) {
+	m.store = store
 }
 
 func (m *DoubleSignMonitor) isDoubleSignHeaders(h1, h2 *types.Header) (bool, error) {
@@ -98,5 +104,8 @@ func (m *DoubleSignMonitor) Verify(h *types.Header) {
 		log.Warn("double sign header content",
 			"header1", hexutil.Encode(h1Bytes),
 			"header2", hexutil.Encode(h2Bytes))
+		if m.store != nil {
+			m.store.Add(NewDoubleSignEvidence(h, h2))
+		}
 	}
 }
@@ -0,0 +1,181 @@
+package monitor
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// EvidenceKind is the kind of validator misbehavior.
+type EvidenceKind uint8
+
+const (
+	DoubleSignEvidence    EvidenceKind = iota + 1 // Two different headers signed at the same height
+	MaliciousVoteEvidence                         // Two votes violating the fast finality rules
+)
+
+var evidenceStoredCounter = metrics.NewRegisteredCounter("monitor/evidence/stored", nil)
+
+func (k EvidenceKind) String() string {
+	switch k {
+	case DoubleSignEvidence:
+		return "doubleSign"
+	case MaliciousVoteEvidence:
+		return "maliciousVote"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(k))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (k EvidenceKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (k *EvidenceKind) UnmarshalText(input []byte) error {
+	switch string(input) {
+	case DoubleSignEvidence.String():
+		*k = DoubleSignEvidence
+	case MaliciousVoteEvidence.String():
+		*k = MaliciousVoteEvidence
+	default:
+		return fmt.Errorf("unknown evidence kind: %s", input)
+	}
+	return nil
+}
+
+// Evidence is the proof of validator misbehavior detected by the monitors.
+type Evidence struct {
+	Kind        EvidenceKind          `json:"kind"`
+	Number      uint64                `json:"number"`            // Number of the double signed block, or target number of the vote
+	Validator   common.Address        `json:"validator"`         // Signer of the double signed headers
+	VoteAddress types.BLSPublicKey    `json:"voteAddress"`       // Voter of the malicious votes
+	Headers     []*types.Header       `json:"headers,omitempty"` // Set if the kind is DoubleSignEvidence
+	Votes       []*types.VoteEnvelope `json:"votes,omitempty"`   // Set if the kind is MaliciousVoteEvidence
+	DetectedAt  uint64                `json:"detectedAt"`        // Unix time in seconds
+}
+
+// NewDoubleSignEvidence creates an evidence from two headers signed at the same height.
+func NewDoubleSignEvidence(h1, h2 *types.Header) *Evidence {
+	if bytes.Compare(h1.Hash().Bytes(), h2.Hash().Bytes()) > 0 {
+		h1, h2 = h2, h1
+	}
+	return &Evidence{
+		Kind:       DoubleSignEvidence,
+		Number:     h1.Number.Uint64(),
+		Validator:  h1.Coinbase,
+		Headers:    []*types.Header{h1, h2},
+		DetectedAt: uint64(time.Now().Unix()),
+	}
+}
+
+// NewMaliciousVoteEvidence creates an evidence from two conflicting votes.
+func NewMaliciousVoteEvidence(vote1, vote2 *types.VoteEnvelope) *Evidence {
+	hash1, hash2 := vote1.Data.Hash(), vote2.Data.Hash()
+	if bytes.Compare(hash1[:], hash2[:]) > 0 {
+		vote1, vote2 = vote2, vote1
+	}
+	return &Evidence{
+		Kind:        MaliciousVoteEvidence,
+		Number:      max(vote1.Data.TargetNumber, vote2.Data.TargetNumber),
+		VoteAddress: vote1.VoteAddress,
+		Votes:       []*types.VoteEnvelope{vote1, vote2},
+		DetectedAt:  uint64(time.Now().Unix()),
+	}
+}
+
+// ID returns the identifier of the evidence, which does not depend on
+// the order in which the misbehavior was detected.
+func (e *Evidence) ID() common.Hash {
+	switch e.Kind {
+	case DoubleSignEvidence:
+		return crypto.Keccak256Hash([]byte{byte(e.Kind)}, e.Headers[0].Hash().Bytes(), e.Headers[1].Hash().Bytes())
+	default:
+		hash1, hash2 := e.Votes[0].Data.Hash(), e.Votes[1].Data.Hash()
+		return crypto.Keccak256Hash(e.VoteAddress[:], hash1[:], hash2[:])
+	}
+}
+
+// EvidenceStore persists the evidence into the database and notifies the subscribers.
+type EvidenceStore struct {
+	db    ethdb.KeyValueStore
+	lock  sync.Mutex // Serializes the existence check and the write of Add
+	feed  event.Feed
+	scope event.SubscriptionScope
+}
+
+// NewEvidenceStore creates an evidence store backed by the given database.
+func NewEvidenceStore(db ethdb.KeyValueStore) *EvidenceStore {
+	return &EvidenceStore{db: db}
+}
+
+// Add stores the evidence and notifies the subscribers. It returns false if the
+// evidence has already been stored.
+func (s *EvidenceStore) Add(evidence *Evidence) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	id := evidence.ID()
+	if rawdb.HasEvidence(s.db, evidence.Number, id) {
+		return false
+	}
+	data, err := rlp.EncodeToBytes(evidence)
+	if err != nil {
+		log.Error("Failed to encode evidence", "kind", evidence.Kind, "id", id, "err", err)
+		return false
+	}
+	rawdb.WriteEvidenceRLP(s.db, evidence.Number, id, data)
+	evidenceStoredCounter.Inc(1)
+
+	s.feed.Send(evidence)
+	return true
+}
+
+// Get retrieves the evidence with the given id at the block number.
+func (s *EvidenceStore) Get(number uint64, id common.Hash) *Evidence {
+	data := rawdb.ReadEvidenceRLP(s.db, number, id)
+	if len(data) == 0 {
+		return nil
+	}
+	evidence := new(Evidence)
+	if err := rlp.DecodeBytes(data, evidence); err != nil {
+		log.Error("Invalid evidence RLP", "number", number, "id", id, "err", err)
+		return nil
+	}
+	return evidence
+}
+
+// Iterate calls the callback for each evidence between the given block numbers
+// (inclusive) in ascending order, until it returns false.
+func (s *EvidenceStore) Iterate(from, to uint64, callback func(evidence *Evidence) bool) {
+	rawdb.IterateEvidenceRLP(s.db, from, to, func(number uint64, id common.Hash, data rlp.RawValue) bool {
+		evidence := new(Evidence)
+		if err := rlp.DecodeBytes(data, evidence); err != nil {
+			log.Error("Invalid evidence RLP", "number", number, "id", id, "err", err)
+			return true
+		}
+		return callback(evidence)
+	})
+}
+
+// SubscribeEvidence registers a subscription for the newly stored evidence.
+func (s *EvidenceStore) SubscribeEvidence(ch chan<- *Evidence) event.Subscription {
+	return s.scope.Track(s.feed.Subscribe(ch))
+}
+
+// Close terminates all the subscriptions.
+func (s *EvidenceStore) Close() {
+	s.scope.Close()
+}
@@ -0,0 +1,117 @@
+package monitor
+
+import (
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestVote(voteAddress types.BLSPublicKey, target uint64, targetHash common.Hash) *types.VoteEnvelope {
+	return &types.VoteEnvelope{
+		VoteAddress: voteAddress,
+		Data: &types.VoteData{
+			SourceNumber: target - 1,
+			SourceHash:   common.Hash{0x01},
+			TargetNumber: target,
+			TargetHash:   targetHash,
+		},
+	}
+}
+
+func TestEvidenceStore(t *testing.T) {
+	store := NewEvidenceStore(rawdb.NewMemoryDatabase())
+	defer store.Close()
+
+	ch := make(chan *Evidence, 16)
+	sub := store.SubscribeEvidence(ch)
+	defer sub.Unsubscribe()
+
+	validator := common.Address{0x01}
+	h1 := &types.Header{Number: big.NewInt(100), Coinbase: validator, Extra: []byte{0x01}}
+	h2 := &types.Header{Number: big.NewInt(100), Coinbase: validator, Extra: []byte{0x02}}
+	doubleSign := NewDoubleSignEvidence(h1, h2)
+	assert.True(t, store.Add(doubleSign))
+	// The evidence detected in reverse order must be deduplicated.
+	assert.False(t, store.Add(NewDoubleSignEvidence(h2, h1)))
+
+	voteAddress := types.BLSPublicKey{0x01}
+	for i := uint64(0); i < 5; i++ {
+		vote1 := newTestVote(voteAddress, 200+i, common.Hash{0x02})
+		vote2 := newTestVote(voteAddress, 200+i, common.Hash{0x03})
+		assert.True(t, store.Add(NewMaliciousVoteEvidence(vote1, vote2)))
+	}
+	assert.Equal(t, 6, len(ch))
+
+	got := store.Get(100, doubleSign.ID())
+	if assert.NotNil(t, got) {
+		assert.Equal(t, DoubleSignEvidence, got.Kind)
+		assert.Equal(t, validator, got.Validator)
+		assert.Equal(t, doubleSign.ID(), got.ID())
+	}
+
+	api := NewEvidenceAPI(store)
+	uint64p := func(n uint64) *hexutil.Uint64 { v := hexutil.Uint64(n); return &v }
+
+	// Filter by validator
+	page, err := api.GetEvidence(EvidenceFilter{Validator: &validator})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(page.Evidences))
+	assert.Equal(t, doubleSign.ID(), page.Evidences[0].ID)
+
+	// Filter by block range
+	page, err = api.GetEvidence(EvidenceFilter{FromBlock: uint64p(201), ToBlock: uint64p(203)})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(page.Evidences))
+	for i, evidence := range page.Evidences {
+		assert.Equal(t, MaliciousVoteEvidence, evidence.Kind)
+		assert.Equal(t, uint64(201+i), evidence.Number)
+	}
+
+	// Pagination
+	kind := MaliciousVoteEvidence
+	page, err = api.GetEvidence(EvidenceFilter{Kind: &kind, VoteAddress: &voteAddress, Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(page.Evidences))
+	assert.Equal(t, uint64p(2), page.Next)
+
+	page, err = api.GetEvidence(EvidenceFilter{Kind: &kind, Offset: 4, Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(page.Evidences))
+	assert.Equal(t, uint64(204), page.Evidences[0].Number)
+	assert.Nil(t, page.Next)
+
+	_, err = api.GetEvidence(EvidenceFilter{FromBlock: uint64p(2), ToBlock: uint64p(1)})
+	assert.Error(t, err)
+}
+
+func TestEvidenceStoreConcurrentAdd(t *testing.T) {
+	store := NewEvidenceStore(rawdb.NewMemoryDatabase())
+	defer store.Close()
+
+	h1 := &types.Header{Number: big.NewInt(100), Extra: []byte{0x01}}
+	h2 := &types.Header{Number: big.NewInt(100), Extra: []byte{0x02}}
+
+	// The same evidence detected by the monitors at once is stored only once
+	var (
+		wg    sync.WaitGroup
+		added atomic.Int32
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if store.Add(NewDoubleSignEvidence(h1, h2)) {
+				added.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), added.Load())
+}
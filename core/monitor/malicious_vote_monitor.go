@@ -23,9 +23,11 @@ var (
 
 // two purposes
 // 1. monitor whether there are bugs in the voting mechanism, so add metrics to observe it.
-// 2. do malicious vote slashing. TODO
+// 2. do malicious vote slashing, if the reporter is set.
 type MaliciousVoteMonitor struct {
 	curVotes map[types.BLSPublicKey]*lru.Cache[uint64, *types.VoteEnvelope]
+	reporter *MaliciousVoteReporter
+	store    *EvidenceStore
 }
 
 func NewMaliciousVoteMonitor() *MaliciousVoteMonitor {
@@ -34,6 +36,16 @@ func NewMaliciousVoteMonitor() *MaliciousVoteMonitor {
 	}
 }
 
+// SetReporter sets the reporter to submit the evidence of detected malicious votes.
+func (m *MaliciousVoteMonitor) SetReporter(reporter *MaliciousVoteReporter) {
+	m.reporter = reporter
+}
+
+// SetEvidenceStore sets the store to persist the evidence of detected malicious votes.
+func (m *MaliciousVoteMonitor) SetEvidenceStore(store *EvidenceStore) {
+	m.store = store
+}
+
 func (m *MaliciousVoteMonitor) ConflictDetect(newVote *types.VoteEnvelope, pendingBlockNumber uint64) bool {
 	// get votes for specified VoteAddress
 	if _, ok := m.curVotes[newVote.VoteAddress]; !ok {
@@ -81,6 +93,12 @@ func (m *MaliciousVoteMonitor) ConflictDetect(newVote *types.VoteEnvelope, pendi
 				} else {
 					log.Warn("MaliciousVote, construct evidence failed")
 				}
+				if m.store != nil {
+					m.store.Add(NewMaliciousVoteEvidence(voteEnvelope, newVote))
+				}
+				if m.reporter != nil {
+					m.reporter.Report(voteEnvelope, newVote)
+				}
 				return true
 			}
 		}
@@ -0,0 +1,256 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/contracts/oasys"
+	"github.com/ethereum/go-ethereum/contracts/oasys/slashindicator"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	maxQueuedEvidences = 64
+	maxRecentReports   = 256
+	submitTimeout      = 2 * time.Minute
+)
+
+// Status of the evidence submitted by the MaliciousVoteReporter.
+const (
+	ReportQueued    = "queued"
+	ReportSubmitted = "submitted"
+	ReportConfirmed = "confirmed"
+	ReportFailed    = "failed"
+)
+
+var (
+	reportQueuedCounter    = metrics.NewRegisteredCounter("monitor/maliciousVote/report/queued", nil)
+	reportConfirmedCounter = metrics.NewRegisteredCounter("monitor/maliciousVote/report/confirmed", nil)
+	reportFailedCounter    = metrics.NewRegisteredCounter("monitor/maliciousVote/report/failed", nil)
+
+	errReverted = errors.New("evidence transaction reverted")
+)
+
+func newFinalityVoteData(vote *types.VoteEnvelope) slashindicator.SlashIndicatorVoteData {
+	return slashindicator.SlashIndicatorVoteData{
+		SrcNum:  new(big.Int).SetUint64(vote.Data.SourceNumber),
+		SrcHash: vote.Data.SourceHash,
+		TarNum:  new(big.Int).SetUint64(vote.Data.TargetNumber),
+		TarHash: vote.Data.TargetHash,
+		Sig:     common.CopyBytes(vote.Signature[:]),
+	}
+}
+
+// MaliciousVoteReport is the submission status of an evidence.
+type MaliciousVoteReport struct {
+	ID          common.Hash        `json:"id"`
+	VoteAddress types.BLSPublicKey `json:"voteAddress"`
+	VoteA       *types.VoteData    `json:"voteA"`
+	VoteB       *types.VoteData    `json:"voteB"`
+	Status      string             `json:"status"`
+	TxHash      *common.Hash       `json:"txHash,omitempty"`
+	Error       string             `json:"error,omitempty"`
+	DetectedAt  time.Time          `json:"detectedAt"`
+	UpdatedAt   time.Time          `json:"updatedAt"`
+
+	evidence *slashindicator.SlashIndicatorFinalityEvidence
+}
+
+// ReporterBackend is the backend used by the MaliciousVoteReporter to send the
+// evidence transactions and wait for them to be mined.
+type ReporterBackend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+}
+
+// MaliciousVoteReporter queues the malicious votes detected by the MaliciousVoteMonitor
+// and submits them to the SlashIndicator contract as finality violation evidence.
+type MaliciousVoteReporter struct {
+	backend  ReporterBackend
+	contract *slashindicator.SlashIndicator
+	from     common.Address
+	signer   bind.SignerFn
+
+	mu      sync.RWMutex
+	reports map[common.Hash]*MaliciousVoteReport
+	recents []common.Hash // Report IDs in order of detection
+
+	queue chan *MaliciousVoteReport
+	quit  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewMaliciousVoteReporter creates a reporter which signs the evidence
+// transactions by the given account.
+func NewMaliciousVoteReporter(backend ReporterBackend, from common.Address, signer bind.SignerFn) (*MaliciousVoteReporter, error) {
+	contract, err := slashindicator.NewSlashIndicator(common.HexToAddress(oasys.SlashIndicatorAddress), backend)
+	if err != nil {
+		return nil, err
+	}
+	return &MaliciousVoteReporter{
+		backend:  backend,
+		contract: contract,
+		from:     from,
+		signer:   signer,
+		reports:  make(map[common.Hash]*MaliciousVoteReport),
+		queue:    make(chan *MaliciousVoteReport, maxQueuedEvidences),
+		quit:     make(chan struct{}),
+	}, nil
+}
+
+// Start starts the background loop submitting the queued evidence.
+func (r *MaliciousVoteReporter) Start() {
+	r.wg.Add(1)
+	go r.loop()
+}
+
+// Stop terminates the background loop, queued evidence is discarded.
+func (r *MaliciousVoteReporter) Stop() {
+	close(r.quit)
+	r.wg.Wait()
+}
+
+// Report queues the pair of conflicting votes to be submitted. It returns false
+// if the same pair has already been reported or the queue is full.
+func (r *MaliciousVoteReporter) Report(voteA, voteB *types.VoteEnvelope) bool {
+	if voteA.Data == nil || voteB.Data == nil || voteA.VoteAddress != voteB.VoteAddress {
+		return false
+	}
+	// The contract expects the votes in no particular order, so they are sorted
+	// to deduplicate the pairs reported in reverse order.
+	sorted := NewMaliciousVoteEvidence(voteA, voteB)
+	id := sorted.ID()
+	voteA, voteB = sorted.Votes[0], sorted.Votes[1]
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.reports[id]; ok {
+		return false
+	}
+	now := time.Now()
+	report := &MaliciousVoteReport{
+		ID:          id,
+		VoteAddress: voteA.VoteAddress,
+		VoteA:       voteA.Data,
+		VoteB:       voteB.Data,
+		Status:      ReportQueued,
+		DetectedAt:  now,
+		UpdatedAt:   now,
+		evidence: &slashindicator.SlashIndicatorFinalityEvidence{
+			VoteA:    newFinalityVoteData(voteA),
+			VoteB:    newFinalityVoteData(voteB),
+			VoteAddr: common.CopyBytes(voteA.VoteAddress[:]),
+		},
+	}
+	select {
+	case r.queue <- report:
+	default:
+		log.Warn("Malicious vote report queue is full, dropping evidence", "id", id, "voteAddress", voteA.VoteAddress)
+		return false
+	}
+
+	r.reports[id] = report
+	r.recents = append(r.recents, id)
+	if len(r.recents) > maxRecentReports {
+		delete(r.reports, r.recents[0])
+		r.recents = r.recents[1:]
+	}
+	reportQueuedCounter.Inc(1)
+	log.Info("Queued malicious vote evidence", "id", id, "voteAddress", voteA.VoteAddress)
+	return true
+}
+
+// Reports returns a copy of the recent reports in order of detection.
+func (r *MaliciousVoteReporter) Reports() []*MaliciousVoteReport {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reports := make([]*MaliciousVoteReport, 0, len(r.recents))
+	for _, id := range r.recents {
+		cpy := *r.reports[id]
+		reports = append(reports, &cpy)
+	}
+	return reports
+}
+
+// Account returns the account submitting the evidence.
+func (r *MaliciousVoteReporter) Account() common.Address {
+	return r.from
+}
+
+// Pending returns the number of evidence waiting to be submitted.
+func (r *MaliciousVoteReporter) Pending() int {
+	return len(r.queue)
+}
+
+func (r *MaliciousVoteReporter) loop() {
+	defer r.wg.Done()
+
+	for {
+		select {
+		case report := <-r.queue:
+			r.submit(report)
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+func (r *MaliciousVoteReporter) submit(report *MaliciousVoteReport) {
+	ctx, cancel := context.WithTimeout(context.Background(), submitTimeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-r.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	opts := &bind.TransactOpts{From: r.from, Signer: r.signer, Context: ctx}
+	tx, err := r.contract.SubmitFinalityViolationEvidence(opts, *report.evidence)
+	if err != nil {
+		r.update(report, ReportFailed, nil, err)
+		return
+	}
+	hash := tx.Hash()
+	r.update(report, ReportSubmitted, &hash, nil)
+
+	receipt, err := bind.WaitMined(ctx, r.backend, tx)
+	if err == nil && receipt.Status != types.ReceiptStatusSuccessful {
+		err = errReverted
+	}
+	if err != nil {
+		r.update(report, ReportFailed, &hash, err)
+		return
+	}
+	r.update(report, ReportConfirmed, &hash, nil)
+}
+
+func (r *MaliciousVoteReporter) update(report *MaliciousVoteReport, status string, txHash *common.Hash, err error) {
+	r.mu.Lock()
+	report.Status = status
+	report.TxHash = txHash
+	report.UpdatedAt = time.Now()
+	if err != nil {
+		report.Error = err.Error()
+	}
+	r.mu.Unlock()
+
+	switch status {
+	case ReportConfirmed:
+		reportConfirmedCounter.Inc(1)
+		log.Info("Submitted malicious vote evidence", "id", report.ID, "voteAddress", report.VoteAddress, "tx", txHash)
+	case ReportFailed:
+		reportFailedCounter.Inc(1)
+		log.Error("Failed to submit malicious vote evidence", "id", report.ID, "voteAddress", report.VoteAddress, "err", err)
+	}
+}
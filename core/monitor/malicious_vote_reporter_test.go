@@ -0,0 +1,126 @@
+package monitor_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/contracts/oasys"
+	"github.com/ethereum/go-ethereum/core/monitor"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+)
+
+var (
+	reporterKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	reporterAddr   = crypto.PubkeyToAddress(reporterKey.PublicKey)
+)
+
+func waitReport(t *testing.T, reporter *monitor.MaliciousVoteReporter, status string, commit func()) *monitor.MaliciousVoteReport {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		if reports := reporter.Reports(); len(reports) > 0 {
+			if reports[0].Status == status {
+				return reports[0]
+			}
+			if reports[0].Status == monitor.ReportFailed {
+				t.Fatalf("failed to submit evidence: %s", reports[0].Error)
+			}
+		}
+		if commit != nil {
+			commit()
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("timeout waiting for report status %s", status)
+	return nil
+}
+
+func TestMaliciousVoteReporter(t *testing.T) {
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		reporterAddr: {Balance: new(big.Int).Mul(big.NewInt(100), big.NewInt(1e18))},
+		// The stub contract stops immediately to accept any evidence.
+		common.HexToAddress(oasys.SlashIndicatorAddress): {Code: []byte{0x00}, Balance: common.Big0},
+	})
+	defer backend.Close()
+
+	client := backend.Client()
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := bind.NewKeyedTransactorWithChainID(reporterKey, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reporter, err := monitor.NewMaliciousVoteReporter(client, reporterAddr, opts.Signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reporter.Start()
+	defer reporter.Stop()
+
+	maliciousVoteMonitor := monitor.NewMaliciousVoteMonitor()
+	maliciousVoteMonitor.SetReporter(reporter)
+
+	pendingBlockNumber := uint64(1000)
+	voteAddress := types.BLSPublicKey{0x01}
+	vote1 := &types.VoteEnvelope{
+		VoteAddress: voteAddress,
+		Signature:   types.BLSSignature{0x01},
+		Data: &types.VoteData{
+			SourceNumber: pendingBlockNumber - 2,
+			SourceHash:   common.Hash{0x01},
+			TargetNumber: pendingBlockNumber - 1,
+			TargetHash:   common.Hash{0x02},
+		},
+	}
+	vote2 := &types.VoteEnvelope{
+		VoteAddress: voteAddress,
+		Signature:   types.BLSSignature{0x02},
+		Data: &types.VoteData{
+			SourceNumber: pendingBlockNumber - 2,
+			SourceHash:   common.Hash{0x01},
+			TargetNumber: pendingBlockNumber - 1,
+			TargetHash:   common.Hash{0x03},
+		},
+	}
+	if maliciousVoteMonitor.ConflictDetect(vote1, pendingBlockNumber) {
+		t.Fatal("unexpected conflict")
+	}
+	if !maliciousVoteMonitor.ConflictDetect(vote2, pendingBlockNumber) {
+		t.Fatal("conflict not detected")
+	}
+
+	// The same pair of votes must not be reported twice, regardless of the order.
+	if reporter.Report(vote2, vote1) {
+		t.Fatal("duplicated evidence is queued")
+	}
+
+	submitted := waitReport(t, reporter, monitor.ReportSubmitted, nil)
+	if submitted.TxHash == nil {
+		t.Fatal("transaction hash is missing")
+	}
+	confirmed := waitReport(t, reporter, monitor.ReportConfirmed, func() { backend.Commit() })
+	if confirmed.VoteAddress != voteAddress {
+		t.Errorf("vote address mismatch, got %s, want %s", confirmed.VoteAddress, voteAddress)
+	}
+
+	tx, _, err := client.TransactionByHash(context.Background(), *confirmed.TxHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *tx.To() != common.HexToAddress(oasys.SlashIndicatorAddress) {
+		t.Errorf("destination mismatch, got %s", tx.To())
+	}
+	if len(reporter.Reports()) != 1 {
+		t.Errorf("reports mismatch, got %d, want 1", len(reporter.Reports()))
+	}
+	if pending := reporter.Pending(); pending != 0 {
+		t.Errorf("pending mismatch, got %d, want 0", pending)
+	}
+}
@@ -865,6 +865,13 @@ func ReadBlobSidecarsRLP(db ethdb.Reader, hash common.Hash, number uint64) rlp.R
 		// Check if the data is in ancients
 		if isCanon(reader, number, hash) {
 			data, _ = reader.Ancient(ChainFreezerBlobSidecarTable, number)
+			if len(data) > 0 && !bytes.Equal(data, rlp.EmptyList) {
+				return nil
+			}
+			// The sidecars missing in ancients may be backfilled into leveldb
+			if backfilled, _ := db.Get(blockBlobSidecarsKey(number, hash)); len(backfilled) > 0 {
+				data = backfilled
+			}
 			return nil
 		}
 		// If not, try reading from leveldb
@@ -908,6 +915,31 @@ func DeleteBlobSidecars(db ethdb.KeyValueWriter, hash common.Hash, number uint64
 	}
 }
 
+// DeleteBlobSidecarsBelow removes the blob sidecars of all the blocks below the
+// given number from leveldb, which are only there for the frozen blocks whose
+// sidecars are backfilled.
+func DeleteBlobSidecarsBelow(db ethdb.KeyValueStore, number uint64) {
+	it := db.NewIterator(BlockBlobSidecarsPrefix, nil)
+	defer it.Release()
+
+	batch := db.NewBatch()
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(BlockBlobSidecarsPrefix)+8+common.HashLength {
+			continue
+		}
+		if binary.BigEndian.Uint64(key[len(BlockBlobSidecarsPrefix):]) >= number {
+			break
+		}
+		if err := batch.Delete(key); err != nil {
+			log.Crit("Failed to delete block blobs", "err", err)
+		}
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to delete block blobs", "err", err)
+	}
+}
+
 func writeAncientBlock(op ethdb.AncientWriteOp, block *types.Block, header *types.Header, receipts rlp.RawValue, td *big.Int) error {
 	num := block.NumberU64()
 	if err := op.AppendRaw(ChainFreezerHashTable, num, block.Hash().Bytes()); err != nil {
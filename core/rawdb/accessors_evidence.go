@@ -0,0 +1,57 @@
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// HasEvidence checks if the evidence with the given id exists at the block number.
+func HasEvidence(db ethdb.KeyValueReader, number uint64, id common.Hash) bool {
+	has, _ := db.Has(evidenceKey(number, id))
+	return has
+}
+
+// ReadEvidenceRLP retrieves the evidence with the given id in RLP encoding.
+func ReadEvidenceRLP(db ethdb.KeyValueReader, number uint64, id common.Hash) rlp.RawValue {
+	data, _ := db.Get(evidenceKey(number, id))
+	return data
+}
+
+// WriteEvidenceRLP stores the RLP encoded evidence of validator misbehavior.
+func WriteEvidenceRLP(db ethdb.KeyValueWriter, number uint64, id common.Hash, data rlp.RawValue) {
+	if err := db.Put(evidenceKey(number, id), data); err != nil {
+		log.Crit("Failed to store evidence", "err", err)
+	}
+}
+
+// DeleteEvidence removes the evidence with the given id.
+func DeleteEvidence(db ethdb.KeyValueWriter, number uint64, id common.Hash) {
+	if err := db.Delete(evidenceKey(number, id)); err != nil {
+		log.Crit("Failed to delete evidence", "err", err)
+	}
+}
+
+// IterateEvidenceRLP calls the callback for each RLP encoded evidence between
+// the given block numbers (inclusive) in ascending order, until it returns false.
+func IterateEvidenceRLP(db ethdb.Iteratee, from, to uint64, callback func(number uint64, id common.Hash, data rlp.RawValue) bool) {
+	it := db.NewIterator(EvidencePrefix, encodeBlockNumber(from))
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(EvidencePrefix)+8+common.HashLength {
+			continue
+		}
+		number := binary.BigEndian.Uint64(key[len(EvidencePrefix):])
+		if number > to {
+			return
+		}
+		if !callback(number, common.BytesToHash(key[len(EvidencePrefix)+8:]), common.CopyBytes(it.Value())) {
+			return
+		}
+	}
+}
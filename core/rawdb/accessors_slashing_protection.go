@@ -0,0 +1,74 @@
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ReadSlashingProtectionKeyRLP retrieves the vote watermarks of the BLS key in RLP encoding.
+func ReadSlashingProtectionKeyRLP(db ethdb.KeyValueReader, pubKey []byte) rlp.RawValue {
+	data, _ := db.Get(slashingProtectionKeyKey(pubKey))
+	return data
+}
+
+// WriteSlashingProtectionKeyRLP stores the RLP encoded vote watermarks of the BLS key.
+func WriteSlashingProtectionKeyRLP(db ethdb.KeyValueWriter, pubKey []byte, data rlp.RawValue) {
+	if err := db.Put(slashingProtectionKeyKey(pubKey), data); err != nil {
+		log.Crit("Failed to store slashing protection key", "err", err)
+	}
+}
+
+// IterateSlashingProtectionKeys calls the callback for each BLS key with the
+// RLP encoded vote watermarks, until it returns false.
+func IterateSlashingProtectionKeys(db ethdb.Iteratee, callback func(pubKey []byte, data rlp.RawValue) bool) {
+	it := db.NewIterator(SlashingProtectionKeyPrefix, nil)
+	defer it.Release()
+
+	for it.Next() {
+		if !callback(common.CopyBytes(it.Key()[len(SlashingProtectionKeyPrefix):]), common.CopyBytes(it.Value())) {
+			return
+		}
+	}
+}
+
+// ReadSlashingProtectionVoteRLP retrieves the vote signed by the BLS key at the target in RLP encoding.
+func ReadSlashingProtectionVoteRLP(db ethdb.KeyValueReader, pubKey []byte, target uint64) rlp.RawValue {
+	data, _ := db.Get(slashingProtectionVoteKey(pubKey, target))
+	return data
+}
+
+// WriteSlashingProtectionVoteRLP stores the RLP encoded vote signed by the BLS key at the target.
+func WriteSlashingProtectionVoteRLP(db ethdb.KeyValueWriter, pubKey []byte, target uint64, data rlp.RawValue) {
+	if err := db.Put(slashingProtectionVoteKey(pubKey, target), data); err != nil {
+		log.Crit("Failed to store slashing protection vote", "err", err)
+	}
+}
+
+// DeleteSlashingProtectionVote removes the vote signed by the BLS key at the target.
+func DeleteSlashingProtectionVote(db ethdb.KeyValueWriter, pubKey []byte, target uint64) {
+	if err := db.Delete(slashingProtectionVoteKey(pubKey, target)); err != nil {
+		log.Crit("Failed to delete slashing protection vote", "err", err)
+	}
+}
+
+// IterateSlashingProtectionVotesRLP calls the callback for each RLP encoded vote
+// signed by the BLS key in ascending order of the target, until it returns false.
+func IterateSlashingProtectionVotesRLP(db ethdb.Iteratee, pubKey []byte, callback func(target uint64, data rlp.RawValue) bool) {
+	prefix := append(append([]byte{}, SlashingProtectionVotePrefix...), pubKey...)
+	it := db.NewIterator(prefix, nil)
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(prefix)+8 {
+			continue
+		}
+		if !callback(binary.BigEndian.Uint64(key[len(prefix):]), common.CopyBytes(it.Value())) {
+			return
+		}
+	}
+}
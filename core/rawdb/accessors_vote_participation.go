@@ -0,0 +1,64 @@
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ReadVoteParticipationRLP retrieves the vote participation of the epoch in RLP encoding.
+func ReadVoteParticipationRLP(db ethdb.KeyValueReader, epoch uint64) rlp.RawValue {
+	data, _ := db.Get(voteParticipationKey(epoch))
+	return data
+}
+
+// WriteVoteParticipationRLP stores the RLP encoded vote participation of the epoch.
+func WriteVoteParticipationRLP(db ethdb.KeyValueWriter, epoch uint64, data rlp.RawValue) {
+	if err := db.Put(voteParticipationKey(epoch), data); err != nil {
+		log.Crit("Failed to store vote participation", "err", err)
+	}
+}
+
+// IterateVoteParticipationRLP calls the callback for each RLP encoded vote
+// participation between the given epochs (inclusive) in ascending order, until
+// it returns false.
+func IterateVoteParticipationRLP(db ethdb.Iteratee, from, to uint64, callback func(epoch uint64, data rlp.RawValue) bool) {
+	it := db.NewIterator(VoteParticipationPrefix, encodeBlockNumber(from))
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(VoteParticipationPrefix)+8 {
+			continue
+		}
+		epoch := binary.BigEndian.Uint64(key[len(VoteParticipationPrefix):])
+		if epoch > to {
+			return
+		}
+		if !callback(epoch, common.CopyBytes(it.Value())) {
+			return
+		}
+	}
+}
+
+// ReadVoteParticipationHead retrieves the number of the last block indexed for
+// the vote participation.
+func ReadVoteParticipationHead(db ethdb.KeyValueReader) *uint64 {
+	data, _ := db.Get(voteParticipationHeadKey)
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteVoteParticipationHead stores the number of the last block indexed for
+// the vote participation.
+func WriteVoteParticipationHead(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Put(voteParticipationHeadKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store vote participation head", "err", err)
+	}
+}
@@ -17,6 +17,7 @@
 package rawdb
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math/big"
@@ -425,7 +426,7 @@ func (f *chainFreezer) freeze(db ethdb.KeyValueStore, continueFreeze bool) {
 		env, _ := f.freezeEnv.Load().(*ethdb.FreezerEnv)
 		// try prune blob data after cancun fork
 		if isCancun(env, head.Number, head.Time) {
-			f.tryPruneBlobAncientTable(env, *number)
+			f.tryPruneBlobAncientTable(db, env, *number)
 		}
 		f.tryPruneHistoryBlock(*number)
 
@@ -442,7 +443,7 @@ func (f *chainFreezer) freeze(db ethdb.KeyValueStore, continueFreeze bool) {
 	}
 }
 
-func (f *chainFreezer) tryPruneBlobAncientTable(env *ethdb.FreezerEnv, num uint64) {
+func (f *chainFreezer) tryPruneBlobAncientTable(db ethdb.KeyValueStore, env *ethdb.FreezerEnv, num uint64) {
 	extraReserve := getBlobExtraReserveFromEnv(env)
 	// It means that there is no need for pruning
 	if extraReserve == 0 {
@@ -466,13 +467,64 @@ func (f *chainFreezer) tryPruneBlobAncientTable(env *ethdb.FreezerEnv, num uint6
 	}
 
 	start := time.Now()
-	if _, err := f.TruncateTableTail(ChainFreezerBlobSidecarTable, expectTail); err != nil {
+	if env != nil && env.SidecarArchive != nil {
+		f.archiveSidecars(db, env.SidecarArchive, expectTail)
+	}
+	old, err := f.TruncateTableTail(ChainFreezerBlobSidecarTable, expectTail)
+	if err != nil {
 		log.Error("Cannot prune blob ancient", "block", num, "expectTail", expectTail, "err", err)
 		return
 	}
+	// Prune the sidecars backfilled into leveldb after frozen as well
+	if old < expectTail {
+		DeleteBlobSidecarsBelow(db, expectTail)
+	}
 	log.Debug("Chain freezer prune useless blobs, now ancient data is", "from", expectTail, "to", num, "cost", common.PrettyDuration(time.Since(start)))
 }
 
+// archiveSidecars copies the sidecars of the frozen blocks below the limit into
+// the archive before they are pruned, from the last archived block or the tail
+// of the blob table.
+func (f *chainFreezer) archiveSidecars(db ethdb.KeyValueStore, archive ethdb.SidecarArchiver, limit uint64) {
+	freezer, ok := f.ancients.(*Freezer)
+	if !ok {
+		log.Error("Cannot archive blob sidecars of the memory freezer")
+		return
+	}
+	tail, err := freezer.TableTail(ChainFreezerBlobSidecarTable)
+	if err != nil {
+		log.Error("Cannot get blob ancient tail", "err", err)
+		return
+	}
+	var (
+		from     = max(tail, archive.SidecarsArchived())
+		archived int
+	)
+	for number := from; number < limit; number++ {
+		hash, err := f.Ancient(ChainFreezerHashTable, number)
+		if err != nil {
+			log.Error("Cannot archive blob sidecars without block hash", "number", number, "err", err)
+			return
+		}
+		sidecars, _ := f.Ancient(ChainFreezerBlobSidecarTable, number)
+		if len(sidecars) == 0 || bytes.Equal(sidecars, rlp.EmptyList) {
+			// The sidecars missing in ancients may be backfilled into leveldb
+			sidecars, _ = db.Get(blockBlobSidecarsKey(number, common.BytesToHash(hash)))
+		}
+		if len(sidecars) == 0 || bytes.Equal(sidecars, rlp.EmptyList) {
+			continue
+		}
+		if err := archive.ArchiveSidecarsRLP(number, common.BytesToHash(hash), sidecars); err != nil {
+			log.Error("Cannot archive blob sidecars", "number", number, "err", err)
+			return
+		}
+		archived++
+	}
+	if archived > 0 {
+		log.Debug("Archived blob sidecars before pruned", "from", from, "to", limit, "blocks", archived)
+	}
+}
+
 func getBlobExtraReserveFromEnv(env *ethdb.FreezerEnv) uint64 {
 	if env == nil {
 		return params.DefaultExtraReserveForBlobRequests
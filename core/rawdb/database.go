@@ -688,6 +688,8 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		preimages          stat
 		cliqueSnaps        stat
 		oasysSnaps         stat
+		evidences          stat
+		voteParticipations stat
 		bloomBits          stat
 		filterMapRows      stat
 		filterMapLastBlock stat
@@ -760,6 +762,10 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 			cliqueSnaps.Add(size)
 		case bytes.HasPrefix(key, OasysSnapshotPrefix) && len(key) == 7+common.HashLength:
 			oasysSnaps.Add(size)
+		case bytes.HasPrefix(key, EvidencePrefix) && len(key) == len(EvidencePrefix)+8+common.HashLength:
+			evidences.Add(size)
+		case bytes.HasPrefix(key, VoteParticipationPrefix) && len(key) == len(VoteParticipationPrefix)+8:
+			voteParticipations.Add(size)
 
 		// new log index
 		case bytes.HasPrefix(key, filterMapRowPrefix) && len(key) <= len(filterMapRowPrefix)+9:
@@ -886,6 +892,8 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		{"Key-Value store", "Storage snapshot", storageSnaps.Size(), storageSnaps.Count()},
 		{"Key-Value store", "Clique snapshots", cliqueSnaps.Size(), cliqueSnaps.Count()},
 		{"Key-Value store", "Oasys snapshots", oasysSnaps.Size(), oasysSnaps.Count()},
+		{"Key-Value store", "Misbehavior evidences", evidences.Size(), evidences.Count()},
+		{"Key-Value store", "Vote participations", voteParticipations.Size(), voteParticipations.Count()},
 		{"Key-Value store", "Singleton metadata", metadata.Size(), metadata.Count()},
 	}
 	// Inspect all registered append-only file store then.
@@ -1004,7 +1012,7 @@ var knownMetadataKeys = [][]byte{
 	snapshotGeneratorKey, snapshotRecoveryKey, txIndexTailKey, fastTxLookupLimitKey,
 	uncleanShutdownKey, badBlockKey, transitionStatusKey, skeletonSyncStatusKey,
 	persistentStateIDKey, trieJournalKey, snapshotSyncStatusKey, snapSyncStatusFlagKey,
-	filterMapsRangeKey, headStateHistoryIndexKey,
+	filterMapsRangeKey, headStateHistoryIndexKey, voteParticipationHeadKey,
 }
 
 // printChainMetadata prints out chain metadata to stderr.
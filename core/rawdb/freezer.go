@@ -236,6 +236,19 @@ func (f *Freezer) TableAncients(kind string) (uint64, error) {
 	return f.tables[kind].items.Load(), nil
 }
 
+// TableTail returns the number of the first item stored in the table, which may
+// be truncated independently beyond the tail of the freezer.
+func (f *Freezer) TableTail(kind string) (uint64, error) {
+	f.writeLock.RLock()
+	defer f.writeLock.RUnlock()
+
+	t, exist := f.tables[kind]
+	if !exist {
+		return 0, errUnknownTable
+	}
+	return t.itemHidden.Load(), nil
+}
+
 // ItemAmountInAncient returns the actual length of current ancientDB.
 func (f *Freezer) ItemAmountInAncient() (uint64, error) {
 	return f.frozen.Load(), nil
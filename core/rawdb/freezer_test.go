@@ -407,10 +407,13 @@ func TestFreezer_AdditionTables(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, item, actual)
 
-	// check additional table head
+	// check additional table head and tail
 	ancients, err := f.TableAncients("a1")
 	require.NoError(t, err)
 	require.Equal(t, uint64(5), ancients)
+	tail, err := f.TableTail("a1")
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), tail)
 	require.NoError(t, f.Close())
 
 	// reopen and read
@@ -426,6 +429,9 @@ func TestFreezer_AdditionTables(t *testing.T) {
 	ancients, err = f.TableAncients("a1")
 	require.NoError(t, err)
 	require.Equal(t, uint64(5), ancients)
+	tail, err = f.TableTail("a1")
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), tail)
 	require.NoError(t, f.Close())
 }
 
@@ -46,6 +46,9 @@ var (
 	// persistentStateIDKey tracks the id of latest stored state(for path-based only).
 	persistentStateIDKey = []byte("LastStateID")
 
+	// voteParticipationHeadKey tracks the last block indexed for the vote participation.
+	voteParticipationHeadKey = []byte("LastVoteParticipation")
+
 	// lastPivotKey tracks the last pivot block used by fast sync (to reenable on sethead).
 	lastPivotKey = []byte("LastPivot")
 
@@ -146,6 +149,15 @@ var (
 
 	BlockBlobSidecarsPrefix = []byte("blobs")
 
+	EvidencePrefix = []byte("evidence-") // EvidencePrefix + num (uint64 big endian) + id -> evidence of validator misbehavior
+
+	SlashingProtectionKeyPrefix  = []byte("sp-k") // SlashingProtectionKeyPrefix + BLS public key -> vote watermarks of the key
+	SlashingProtectionVotePrefix = []byte("sp-v") // SlashingProtectionVotePrefix + BLS public key + target (uint64 big endian) -> signed vote
+
+	// VoteParticipationPrefix + epoch (uint64 big endian) -> vote participation of the validators.
+	// It must not start with "v", which is taken by the VerklePrefix.
+	VoteParticipationPrefix = []byte("participation-")
+
 	// new log index
 	filterMapsPrefix         = "fm-"
 	filterMapsRangeKey       = []byte(filterMapsPrefix + "R")
@@ -216,6 +228,26 @@ func blockBlobSidecarsKey(number uint64, hash common.Hash) []byte {
 	return append(append(BlockBlobSidecarsPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
 }
 
+// evidenceKey = EvidencePrefix + num (uint64 big endian) + id
+func evidenceKey(number uint64, id common.Hash) []byte {
+	return append(append(EvidencePrefix, encodeBlockNumber(number)...), id.Bytes()...)
+}
+
+// voteParticipationKey = VoteParticipationPrefix + epoch (uint64 big endian)
+func voteParticipationKey(epoch uint64) []byte {
+	return append(append([]byte{}, VoteParticipationPrefix...), encodeBlockNumber(epoch)...)
+}
+
+// slashingProtectionKeyKey = SlashingProtectionKeyPrefix + pubKey
+func slashingProtectionKeyKey(pubKey []byte) []byte {
+	return append(append([]byte{}, SlashingProtectionKeyPrefix...), pubKey...)
+}
+
+// slashingProtectionVoteKey = SlashingProtectionVotePrefix + pubKey + target (uint64 big endian)
+func slashingProtectionVoteKey(pubKey []byte, target uint64) []byte {
+	return append(append(append([]byte{}, SlashingProtectionVotePrefix...), pubKey...), encodeBlockNumber(target)...)
+}
+
 // txLookupKey = txLookupPrefix + hash
 func txLookupKey(hash common.Hash) []byte {
 	return append(txLookupPrefix, hash.Bytes()...)
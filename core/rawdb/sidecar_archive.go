@@ -0,0 +1,141 @@
+package rawdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// sidecarArchiveTable is the only table of the sidecar archive, which has an
+// item for every block from the first archived one, empty if without blobs.
+const sidecarArchiveTable = "sidecars"
+
+// sidecarArchiveTableConfigs configures the table of the sidecar archive.
+var sidecarArchiveTableConfigs = map[string]freezerTableConfig{
+	sidecarArchiveTable: {noSnappy: false, prunable: true},
+}
+
+// errSidecarArchiveOrder is returned if the sidecars are archived out of order.
+var errSidecarArchiveOrder = errors.New("sidecars archived out of order")
+
+// sidecarArchiveEntry is an item of the sidecar archive, which keeps the block
+// hash to tell the sidecars of the reorged blocks.
+type sidecarArchiveEntry struct {
+	Hash     common.Hash
+	Sidecars rlp.RawValue
+}
+
+// SidecarArchive is an append-only store of the blob sidecars in flat files,
+// indexed by the block number, which retains them beyond the pruning of the
+// ancient store. Only the blocks deep enough not to be reorged are archived.
+type SidecarArchive struct {
+	freezer *Freezer
+}
+
+// NewSidecarArchive opens the sidecar archive in the directory, creating it if
+// not exists.
+func NewSidecarArchive(dir string, readonly bool) (*SidecarArchive, error) {
+	freezer, err := NewFreezer(dir, "eth/db/sidecars/", readonly, freezerTableSize, sidecarArchiveTableConfigs)
+	if err != nil {
+		return nil, err
+	}
+	return &SidecarArchive{freezer: freezer}, nil
+}
+
+// Close closes the archive.
+func (a *SidecarArchive) Close() error {
+	return a.freezer.Close()
+}
+
+// Range returns the number of the first block archived and the one following
+// the last archived. The archive is empty if they are equal.
+func (a *SidecarArchive) Range() (uint64, uint64) {
+	first, _ := a.freezer.Tail()
+	next, _ := a.freezer.Ancients()
+	return first, next
+}
+
+// SidecarsArchived implements ethdb.SidecarArchiver, returning the number of
+// the block following the last archived one.
+func (a *SidecarArchive) SidecarsArchived() uint64 {
+	_, next := a.Range()
+	return next
+}
+
+// ArchiveSidecarsRLP implements ethdb.SidecarArchiver, appending the encoded
+// sidecars of the block. The blocks skipped since the last archived one are
+// recorded as without blobs.
+func (a *SidecarArchive) ArchiveSidecarsRLP(number uint64, hash common.Hash, sidecars []byte) error {
+	if len(sidecars) == 0 || bytes.Equal(sidecars, rlp.EmptyList) {
+		return nil
+	}
+	first, next := a.Range()
+	if first == next && number != next {
+		// Start the empty archive at the block, rather than from genesis
+		if err := a.freezer.ResetTable(sidecarArchiveTable, number, true); err != nil {
+			return err
+		}
+		next = number
+	}
+	if number < next {
+		return fmt.Errorf("%w: block %d, next %d", errSidecarArchiveOrder, number, next)
+	}
+	entry, err := rlp.EncodeToBytes(&sidecarArchiveEntry{Hash: hash, Sidecars: sidecars})
+	if err != nil {
+		return err
+	}
+	_, err = a.freezer.ModifyAncients(func(op ethdb.AncientWriteOp) error {
+		for ; next < number; next++ {
+			if err := op.AppendRaw(sidecarArchiveTable, next, nil); err != nil {
+				return err
+			}
+		}
+		return op.AppendRaw(sidecarArchiveTable, number, entry)
+	})
+	return err
+}
+
+// ReadEntry returns the hash and the encoded sidecars of the archived block, or
+// the empty ones if the block has no blobs or is not archived.
+func (a *SidecarArchive) ReadEntry(number uint64) (common.Hash, rlp.RawValue) {
+	data, err := a.freezer.Ancient(sidecarArchiveTable, number)
+	if err != nil || len(data) == 0 {
+		return common.Hash{}, nil
+	}
+	var entry sidecarArchiveEntry
+	if err := rlp.DecodeBytes(data, &entry); err != nil {
+		log.Error("Invalid archived sidecars RLP", "number", number, "err", err)
+		return common.Hash{}, nil
+	}
+	return entry.Hash, entry.Sidecars
+}
+
+// ReadSidecarsRLP returns the encoded sidecars of the block, or nil if not
+// archived.
+func (a *SidecarArchive) ReadSidecarsRLP(hash common.Hash, number uint64) rlp.RawValue {
+	archived, sidecars := a.ReadEntry(number)
+	if archived != hash {
+		return nil
+	}
+	return sidecars
+}
+
+// ReadSidecars returns the sidecars of the block, or nil if not archived.
+func (a *SidecarArchive) ReadSidecars(hash common.Hash, number uint64) types.BlobSidecars {
+	data := a.ReadSidecarsRLP(hash, number)
+	if len(data) == 0 {
+		return nil
+	}
+	var sidecars types.BlobSidecars
+	if err := rlp.DecodeBytes(data, &sidecars); err != nil {
+		log.Error("Invalid archived sidecars RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return sidecars
+}
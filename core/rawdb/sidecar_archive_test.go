@@ -0,0 +1,72 @@
+package rawdb
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func testArchivedSidecars(number uint64) types.BlobSidecars {
+	return types.BlobSidecars{{
+		BlobTxSidecar: types.BlobTxSidecar{
+			Blobs:       []kzg4844.Blob{{byte(number)}},
+			Commitments: []kzg4844.Commitment{{}},
+			Proofs:      []kzg4844.Proof{{}},
+		},
+		BlockNumber: new(big.Int).SetUint64(number),
+		BlockHash:   common.Hash{byte(number)},
+	}}
+}
+
+func TestSidecarArchive(t *testing.T) {
+	dir := t.TempDir()
+	archive, err := NewSidecarArchive(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The archive starts at the first block archived, skipping the empty ones
+	for _, number := range []uint64{100, 101, 105} {
+		enc, _ := rlp.EncodeToBytes(testArchivedSidecars(number))
+		if err := archive.ArchiveSidecarsRLP(number, common.Hash{byte(number)}, enc); err != nil {
+			t.Fatalf("failed to archive block %d: %v", number, err)
+		}
+	}
+	if err := archive.ArchiveSidecarsRLP(106, common.Hash{106}, rlp.EmptyList); err != nil {
+		t.Fatal(err)
+	}
+	if first, next := archive.Range(); first != 100 || next != 106 {
+		t.Fatalf("range mismatch: have [%d, %d), want [100, 106)", first, next)
+	}
+	enc, _ := rlp.EncodeToBytes(testArchivedSidecars(103))
+	if err := archive.ArchiveSidecarsRLP(103, common.Hash{103}, enc); !errors.Is(err, errSidecarArchiveOrder) {
+		t.Fatalf("archived out of order: %v", err)
+	}
+	archive.Close()
+
+	// The sidecars are retained after reopened, and looked up by the hash
+	if archive, err = NewSidecarArchive(dir, true); err != nil {
+		t.Fatal(err)
+	}
+	defer archive.Close()
+	for number := uint64(99); number < 107; number++ {
+		sidecars := archive.ReadSidecars(common.Hash{byte(number)}, number)
+		switch number {
+		case 100, 101, 105:
+			if len(sidecars) != 1 || sidecars[0].Blobs[0][0] != byte(number) || sidecars[0].BlockNumber.Uint64() != number {
+				t.Errorf("block %d: archived sidecars mismatch: %v", number, sidecars)
+			}
+		default:
+			if sidecars != nil {
+				t.Errorf("block %d: unexpected sidecars", number)
+			}
+		}
+	}
+	if sidecars := archive.ReadSidecars(common.Hash{0xff}, 100); sidecars != nil {
+		t.Error("sidecars of the reorged block are returned")
+	}
+}
@@ -0,0 +1,96 @@
+package standby
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// fileLockRetryDelay is the interval to retry locking the lease file.
+const fileLockRetryDelay = 50 * time.Millisecond
+
+// FileBackend coordinates the lease through a file shared by the nodes, which
+// is guarded by a file lock while being read and updated.
+type FileBackend struct {
+	path string
+	lock *flock.Flock
+}
+
+// NewFileBackend creates a lease backend storing the lease in the file.
+func NewFileBackend(path string) *FileBackend {
+	return &FileBackend{
+		path: path,
+		lock: flock.New(path + ".lock"),
+	}
+}
+
+// Acquire implements Backend.
+func (b *FileBackend) Acquire(ctx context.Context, holder string, ttl time.Duration) error {
+	return b.update(ctx, func(lease *Lease) (bool, error) {
+		return true, lease.grant(holder, ttl, time.Now())
+	})
+}
+
+// Release implements Backend.
+func (b *FileBackend) Release(ctx context.Context, holder string) error {
+	return b.update(ctx, func(lease *Lease) (bool, error) {
+		if lease.Holder != holder {
+			return false, nil
+		}
+		*lease = Lease{}
+		return true, nil
+	})
+}
+
+// String implements Backend.
+func (b *FileBackend) String() string {
+	return "file:" + b.path
+}
+
+// update applies the function to the lease under the file lock, and writes the
+// lease back if modified.
+func (b *FileBackend) update(ctx context.Context, fn func(lease *Lease) (bool, error)) error {
+	if err := os.MkdirAll(filepath.Dir(b.path), 0700); err != nil {
+		return err
+	}
+	locked, err := b.lock.TryLockContext(ctx, fileLockRetryDelay)
+	if err != nil {
+		return err
+	}
+	if !locked {
+		return fmt.Errorf("failed to lock %s", b.lock.Path())
+	}
+	defer b.lock.Unlock()
+
+	var lease Lease
+	data, err := os.ReadFile(b.path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return err
+	case len(data) > 0:
+		if err := json.Unmarshal(data, &lease); err != nil {
+			return fmt.Errorf("corrupted lease file %s: %v", b.path, err)
+		}
+	}
+	modified, err := fn(&lease)
+	if err != nil || !modified {
+		return err
+	}
+	if data, err = json.Marshal(&lease); err != nil {
+		return err
+	}
+	// Replace the file atomically not to leave a torn lease on a crash.
+	tmp := b.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, b.path)
+}
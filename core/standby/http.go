@@ -0,0 +1,156 @@
+package standby
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxLeaseRequestSize is the maximum size of a request to the lease server.
+	maxLeaseRequestSize = 1024
+
+	// maxLeaseTTL is the maximum ttl granted by the lease server.
+	maxLeaseTTL = time.Hour
+)
+
+// leaseRequest is the request body of the lease server.
+type leaseRequest struct {
+	Holder string `json:"holder"`
+	TTL    string `json:"ttl,omitempty"`
+}
+
+// leaseResponse is the response body of the lease server.
+type leaseResponse struct {
+	Lease
+	Error string `json:"error,omitempty"`
+}
+
+// HTTPBackend coordinates the lease through a lease server.
+type HTTPBackend struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPBackend creates a lease backend requesting the lease server at the url.
+func NewHTTPBackend(url string) *HTTPBackend {
+	return &HTTPBackend{
+		url:    strings.TrimSuffix(url, "/"),
+		client: new(http.Client),
+	}
+}
+
+// Acquire implements Backend.
+func (b *HTTPBackend) Acquire(ctx context.Context, holder string, ttl time.Duration) error {
+	return b.post(ctx, "/acquire", &leaseRequest{Holder: holder, TTL: ttl.String()})
+}
+
+// Release implements Backend.
+func (b *HTTPBackend) Release(ctx context.Context, holder string) error {
+	return b.post(ctx, "/release", &leaseRequest{Holder: holder})
+}
+
+// String implements Backend.
+func (b *HTTPBackend) String() string {
+	return b.url
+}
+
+func (b *HTTPBackend) post(ctx context.Context, path string, request *leaseRequest) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.url+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	var response leaseResponse
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxLeaseRequestSize)).Decode(&response); err != nil {
+		return fmt.Errorf("invalid response from the lease server, status %d: %v", res.StatusCode, err)
+	}
+	switch res.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusConflict:
+		return fmt.Errorf("%w: %s until %v", ErrLeaseHeld, response.Holder, response.Expiry.Format(time.RFC3339))
+	default:
+		return fmt.Errorf("lease server error, status %d: %s", res.StatusCode, response.Error)
+	}
+}
+
+// Server is a minimal lease server holding the lease in memory, for the nodes
+// using the HTTPBackend. The lease is lost on a restart of the server, so the
+// ttl bounds the time for which two nodes can believe holding the lease.
+type Server struct {
+	lease Lease
+	lock  sync.Mutex
+}
+
+// NewServer creates a lease server.
+func NewServer() *Server {
+	return new(Server)
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet && r.URL.Path == "/" {
+		s.lock.Lock()
+		lease := s.lease
+		s.lock.Unlock()
+		writeLeaseResponse(w, http.StatusOK, &leaseResponse{Lease: lease})
+		return
+	}
+	if r.Method != http.MethodPost || (r.URL.Path != "/acquire" && r.URL.Path != "/release") {
+		writeLeaseResponse(w, http.StatusNotFound, &leaseResponse{Error: "not found"})
+		return
+	}
+	var req leaseRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxLeaseRequestSize)).Decode(&req); err != nil {
+		writeLeaseResponse(w, http.StatusBadRequest, &leaseResponse{Error: err.Error()})
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if r.URL.Path == "/release" {
+		if s.lease.Holder == req.Holder {
+			s.lease = Lease{}
+		}
+		writeLeaseResponse(w, http.StatusOK, &leaseResponse{Lease: s.lease})
+		return
+	}
+	ttl, err := time.ParseDuration(req.TTL)
+	if err != nil || ttl <= 0 || ttl > maxLeaseTTL {
+		writeLeaseResponse(w, http.StatusBadRequest, &leaseResponse{Error: fmt.Sprintf("invalid ttl %q", req.TTL)})
+		return
+	}
+	if err := s.lease.grant(req.Holder, ttl, time.Now()); err != nil {
+		status := http.StatusConflict
+		if err == ErrInvalidHolder {
+			status = http.StatusBadRequest
+		}
+		writeLeaseResponse(w, status, &leaseResponse{Lease: s.lease, Error: err.Error()})
+		return
+	}
+	writeLeaseResponse(w, http.StatusOK, &leaseResponse{Lease: s.lease})
+}
+
+func writeLeaseResponse(w http.ResponseWriter, status int, response *leaseResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,69 @@
+// Package standby implements the hot standby of a validator, which keeps the
+// signing keys loaded but refuses to seal and vote until it holds the lease
+// coordinated with the other nodes of the same validator.
+package standby
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrLeaseHeld is returned if the lease is held by another node.
+	ErrLeaseHeld = errors.New("lease held by another node")
+
+	// ErrInvalidHolder is returned if the holder name is empty.
+	ErrInvalidHolder = errors.New("invalid lease holder")
+)
+
+// Lease is the validator lease granted to a holder until the expiry.
+type Lease struct {
+	Holder string    `json:"holder"`
+	Expiry time.Time `json:"expiry"`
+}
+
+// expired returns whether the lease is free to be acquired by anyone.
+func (l *Lease) expired(now time.Time) bool {
+	return l.Holder == "" || !now.Before(l.Expiry)
+}
+
+// grant acquires or renews the lease for the holder, and returns ErrLeaseHeld
+// if another holder's lease is not expired yet.
+func (l *Lease) grant(holder string, ttl time.Duration, now time.Time) error {
+	if holder == "" {
+		return ErrInvalidHolder
+	}
+	if l.Holder != holder && !l.expired(now) {
+		return fmt.Errorf("%w: %s until %v", ErrLeaseHeld, l.Holder, l.Expiry.Format(time.RFC3339))
+	}
+	l.Holder, l.Expiry = holder, now.Add(ttl)
+	return nil
+}
+
+// Backend coordinates the lease between the nodes of a validator.
+type Backend interface {
+	// Acquire acquires the lease for the holder, or renews it if already held,
+	// for the ttl. ErrLeaseHeld is returned if another node holds the lease.
+	Acquire(ctx context.Context, holder string, ttl time.Duration) error
+
+	// Release gives up the lease if held by the holder.
+	Release(ctx context.Context, holder string) error
+
+	// String returns the location of the lease for logging.
+	String() string
+}
+
+// NewBackend creates the lease backend from the location, which is either the
+// URL of a lease server or the path of a lease file.
+func NewBackend(location string) (Backend, error) {
+	if location == "" {
+		return nil, errors.New("empty lease location")
+	}
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		return NewHTTPBackend(location), nil
+	}
+	return NewFileBackend(location), nil
+}
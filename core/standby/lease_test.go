@@ -0,0 +1,92 @@
+package standby
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testBackend(t *testing.T, backend Backend) {
+	ctx := context.Background()
+
+	if err := backend.Acquire(ctx, "", time.Minute); err == nil {
+		t.Fatal("acquired the lease without a holder")
+	}
+	if err := backend.Acquire(ctx, "a", time.Minute); err != nil {
+		t.Fatalf("failed to acquire the free lease: %v", err)
+	}
+	if err := backend.Acquire(ctx, "a", time.Minute); err != nil {
+		t.Fatalf("failed to renew the lease: %v", err)
+	}
+	if err := backend.Acquire(ctx, "b", time.Minute); !errors.Is(err, ErrLeaseHeld) {
+		t.Fatalf("acquired the held lease, err: %v", err)
+	}
+	// Releasing by another holder is a noop
+	if err := backend.Release(ctx, "b"); err != nil {
+		t.Fatalf("failed to release: %v", err)
+	}
+	if err := backend.Acquire(ctx, "b", time.Minute); !errors.Is(err, ErrLeaseHeld) {
+		t.Fatalf("acquired the held lease after released by another holder, err: %v", err)
+	}
+	if err := backend.Release(ctx, "a"); err != nil {
+		t.Fatalf("failed to release: %v", err)
+	}
+	if err := backend.Acquire(ctx, "b", 100*time.Millisecond); err != nil {
+		t.Fatalf("failed to acquire the released lease: %v", err)
+	}
+	if err := backend.Acquire(ctx, "a", time.Minute); !errors.Is(err, ErrLeaseHeld) {
+		t.Fatalf("acquired the held lease, err: %v", err)
+	}
+	time.Sleep(150 * time.Millisecond)
+	if err := backend.Acquire(ctx, "a", time.Minute); err != nil {
+		t.Fatalf("failed to acquire the expired lease: %v", err)
+	}
+}
+
+func TestFileBackend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "standby", "lease")
+	testBackend(t, NewFileBackend(path))
+
+	// The lease is shared with another backend of the same file
+	if err := NewFileBackend(path).Acquire(context.Background(), "b", time.Minute); !errors.Is(err, ErrLeaseHeld) {
+		t.Fatalf("acquired the lease held through another backend, err: %v", err)
+	}
+}
+
+func TestHTTPBackend(t *testing.T) {
+	server := httptest.NewServer(NewServer())
+	defer server.Close()
+
+	testBackend(t, NewHTTPBackend(server.URL))
+
+	if err := NewHTTPBackend(server.URL).Acquire(context.Background(), "b", 2*maxLeaseTTL); err == nil || errors.Is(err, ErrLeaseHeld) {
+		t.Fatalf("unexpected error for too long ttl: %v", err)
+	}
+}
+
+func TestNewBackend(t *testing.T) {
+	tests := []struct {
+		location string
+		http     bool
+	}{
+		{"http://127.0.0.1:8555", true},
+		{"https://lease.example.com/", true},
+		{"/var/lib/geth/lease", false},
+		{"lease", false},
+	}
+	for _, tt := range tests {
+		backend, err := NewBackend(tt.location)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.location, err)
+		}
+		if _, ok := backend.(*HTTPBackend); ok != tt.http {
+			t.Errorf("%s: backend mismatch, have %T", tt.location, backend)
+		}
+	}
+	if _, err := NewBackend(""); err == nil {
+		t.Error("created a backend of the empty location")
+	}
+}
@@ -0,0 +1,186 @@
+package standby
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// DefaultLeaseTTL is the default time for which the lease is acquired, which
+// is the longest downtime of the validator on a crash of the active node.
+const DefaultLeaseTTL = 18 * time.Second
+
+// releaseTimeout is the timeout to release the lease on the shutdown.
+const releaseTimeout = 5 * time.Second
+
+var activeGauge = metrics.NewRegisteredGauge("standby/active", nil)
+
+// ErrTakeoverRefused is returned if the takeover check failed before acquiring
+// the lease.
+var ErrTakeoverRefused = errors.New("takeover refused")
+
+// Status is the standby state of the node.
+type Status struct {
+	Holder      string     `json:"holder"`
+	Lease       string     `json:"lease"`
+	Active      bool       `json:"active"`
+	ActiveUntil *time.Time `json:"activeUntil,omitempty"`
+	LastError   string     `json:"lastError,omitempty"`
+}
+
+// Manager keeps acquiring the lease of the validator, and reports whether the
+// node is active, i.e. allowed to seal and vote. The node turns inactive a third
+// of the ttl before the lease expires unless renewed, so it stops signing before
+// another node can take over even if the backend becomes unreachable.
+type Manager struct {
+	backend  Backend
+	holder   string
+	ttl      time.Duration
+	takeover func() error // Check run before becoming active, refusing to if failed
+
+	activeUntil atomic.Int64 // Unix nanoseconds until which the node is active
+	lastErr     atomic.Pointer[string]
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewManager creates a standby manager acquiring the lease for the holder.
+func NewManager(backend Backend, holder string, ttl time.Duration) (*Manager, error) {
+	if holder == "" {
+		return nil, ErrInvalidHolder
+	}
+	if ttl <= 0 {
+		ttl = DefaultLeaseTTL
+	}
+	return &Manager{
+		backend: backend,
+		holder:  holder,
+		ttl:     ttl,
+		quit:    make(chan struct{}),
+	}, nil
+}
+
+// SetTakeoverCheck sets the check run every time before the node acquires the
+// lease to become active. The lease is not acquired and retried later if the
+// check fails. It must be called before Start.
+func (m *Manager) SetTakeoverCheck(check func() error) {
+	m.takeover = check
+}
+
+// Active returns whether the node holds the lease and is allowed to sign.
+func (m *Manager) Active() bool {
+	return time.Now().UnixNano() < m.activeUntil.Load()
+}
+
+// Status returns the standby state of the node.
+func (m *Manager) Status() *Status {
+	status := &Status{
+		Holder: m.holder,
+		Lease:  m.backend.String(),
+		Active: m.Active(),
+	}
+	if status.Active {
+		until := time.Unix(0, m.activeUntil.Load())
+		status.ActiveUntil = &until
+	}
+	if err := m.lastErr.Load(); err != nil {
+		status.LastError = *err
+	}
+	return status
+}
+
+// Start starts acquiring and renewing the lease in the background.
+func (m *Manager) Start() {
+	log.Info("Starting validator standby", "holder", m.holder, "lease", m.backend, "ttl", m.ttl)
+	m.wg.Add(1)
+	go m.loop()
+}
+
+// Stop stops renewing the lease and releases it, so that another node can take
+// over without waiting for the lease to expire.
+func (m *Manager) Stop() {
+	close(m.quit)
+	m.wg.Wait()
+
+	m.deactivate()
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+	if err := m.backend.Release(ctx, m.holder); err != nil {
+		log.Warn("Failed to release validator lease", "lease", m.backend, "err", err)
+	}
+}
+
+func (m *Manager) loop() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		m.renew()
+		select {
+		case <-ticker.C:
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// renew acquires or renews the lease. If the node is not active yet, the
+// takeover check is run before acquiring the lease, so that a node unable to
+// take over never holds the lease even for a moment.
+func (m *Manager) renew() {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), m.ttl/3)
+	defer cancel()
+
+	var err error
+	if !m.Active() && m.takeover != nil {
+		if err = m.takeover(); err != nil {
+			err = fmt.Errorf("%w: %v", ErrTakeoverRefused, err)
+		}
+	}
+	if err == nil {
+		err = m.backend.Acquire(ctx, m.holder, m.ttl)
+	}
+	if err != nil {
+		msg := err.Error()
+		m.lastErr.Store(&msg)
+		switch {
+		case errors.Is(err, ErrTakeoverRefused):
+			log.Error("Refused to take over the validator", "err", err)
+		case errors.Is(err, ErrLeaseHeld):
+			log.Debug("Validator lease held by another node", "err", err)
+		default:
+			log.Warn("Failed to acquire validator lease", "lease", m.backend, "err", err)
+		}
+		if m.Active() {
+			log.Warn("Validator lease is not renewed, will stop signing", "until", time.Unix(0, m.activeUntil.Load()))
+		} else {
+			activeGauge.Update(0)
+		}
+		return
+	}
+	m.lastErr.Store(nil)
+	if !m.Active() {
+		log.Info("Acquired validator lease, start signing", "holder", m.holder, "lease", m.backend)
+		activeGauge.Update(1)
+	}
+	// Deactivate earlier than the lease expires, by the time elapsed before
+	// the backend granted the lease too.
+	m.activeUntil.Store(start.Add(m.ttl - m.ttl/3).UnixNano())
+}
+
+func (m *Manager) deactivate() {
+	if m.activeUntil.Swap(0) > time.Now().UnixNano() {
+		log.Info("Stopped signing as validator standby", "holder", m.holder)
+	}
+	activeGauge.Update(0)
+}
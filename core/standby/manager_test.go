@@ -0,0 +1,105 @@
+package standby
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func waitActive(t *testing.T, m *Manager, active bool) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		if m.Active() == active {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("%s: active mismatch, want %v", m.holder, active)
+}
+
+func TestManagerFailover(t *testing.T) {
+	backend := NewFileBackend(filepath.Join(t.TempDir(), "lease"))
+
+	primary, _ := NewManager(backend, "primary", 300*time.Millisecond)
+	primary.Start()
+	waitActive(t, primary, true)
+
+	standby, _ := NewManager(backend, "standby", 300*time.Millisecond)
+	var takeovers atomic.Int32
+	standby.SetTakeoverCheck(func() error {
+		takeovers.Add(1)
+		return nil
+	})
+	standby.Start()
+	defer standby.Stop()
+
+	time.Sleep(200 * time.Millisecond)
+	if standby.Active() {
+		t.Fatal("standby is active while the primary holds the lease")
+	}
+	if status := standby.Status(); status.LastError == "" {
+		t.Error("lease error is not reported")
+	}
+	// The lease is released on the shutdown of the primary
+	primary.Stop()
+	if primary.Active() {
+		t.Fatal("primary is active after stopped")
+	}
+	waitActive(t, standby, true)
+
+	// The takeover check is not run anymore while the lease is renewed
+	checked := takeovers.Load()
+	if checked == 0 {
+		t.Fatal("takeover check not run")
+	}
+	time.Sleep(250 * time.Millisecond)
+	if have := takeovers.Load(); have != checked {
+		t.Fatalf("takeover check run while active, have %d, want %d", have, checked)
+	}
+	if status := standby.Status(); status.ActiveUntil == nil || status.LastError != "" {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+}
+
+func TestManagerTakeoverRefused(t *testing.T) {
+	backend := NewFileBackend(filepath.Join(t.TempDir(), "lease"))
+
+	m, _ := NewManager(backend, "standby", 300*time.Millisecond)
+	m.SetTakeoverCheck(func() error { return errors.New("journal unavailable") })
+	m.Start()
+	defer m.Stop()
+
+	time.Sleep(200 * time.Millisecond)
+	if m.Active() {
+		t.Fatal("active although the takeover check failed")
+	}
+	if status := m.Status(); !strings.Contains(status.LastError, ErrTakeoverRefused.Error()) {
+		t.Errorf("refused takeover is not reported: %+v", status)
+	}
+	// The lease is never acquired, so another node can take it any time
+	if err := backend.Acquire(context.Background(), "other", time.Minute); err != nil {
+		t.Fatalf("lease is held by the refused node: %v", err)
+	}
+}
+
+func TestManagerExpiry(t *testing.T) {
+	backend := NewFileBackend(filepath.Join(t.TempDir(), "lease"))
+
+	m, _ := NewManager(backend, "primary", 300*time.Millisecond)
+	m.renew()
+	if !m.Active() {
+		t.Fatal("not active after acquired the lease")
+	}
+	// Deactivated before the lease expires unless renewed
+	time.Sleep(220 * time.Millisecond)
+	if m.Active() {
+		t.Fatal("active without renewing the lease")
+	}
+	if err := backend.Acquire(context.Background(), "other", time.Minute); !errors.Is(err, ErrLeaseHeld) {
+		t.Fatalf("lease expired before the node is deactivated, err: %v", err)
+	}
+}
@@ -227,6 +227,8 @@ func ApplyTransactionWithEVM(msg *Message, gp *GasPool, statedb *state.StateDB,
 		if filterErr != nil {
 			log.Warn("Suspicious txfilter failed", "error", filterErr)
 			// return nil, filterErr // Don't block execution by any error from the plugin
+		} else if isBlocked && txfilter.IsShadow() {
+			log.Info("Suspicious txfilter blocked in shadow mode", "txhash", tx.Hash().Hex(), "reason", reason)
 		} else if isBlocked {
 			// No need to revert — tx that throw errors will be reverted by worker.applyTransaction.
 			log.Warn("Suspicious txfilter blocked", "txhash", tx.Hash().Hex(), "reason", reason)
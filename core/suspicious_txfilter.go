@@ -1,6 +1,7 @@
 package core
 
 import (
+	"bytes"
 	"crypto"
 	"crypto/ecdsa"
 	"encoding/hex"
@@ -80,32 +81,89 @@ type SuspiciousTxfilterPluginMetadata struct {
 }
 
 type SuspiciousTxfilter struct {
-	datadir string
-	config  *params.ChainConfig
-	exitCh  chan struct{}
+	datadir        string
+	config         *params.ChainConfig
+	txfilterConfig *SuspiciousTxfilterConfig
+	exitCh         chan struct{}
 
-	plugin   atomic.Pointer[plugin.Plugin]
-	metadata atomic.Pointer[SuspiciousTxfilterPluginMetadata]
+	plugin       atomic.Pointer[plugin.Plugin]
+	metadata     atomic.Pointer[SuspiciousTxfilterPluginMetadata]
+	metadataData atomic.Pointer[[]byte] // Raw metadata, cached once the artifact is verified
+
+	builtin      SuspiciousTxfilterPlugin // Set if the backend is builtin
+	sidecar      *sidecarTxfilter         // Set if the backend is sidecar
+	sidecarReady atomic.Bool              // Whether the sidecar config has been verified
+
+	journal *txfilterJournal // Records every decision, nil in tests
 
 	verifier *sigverify.Verifier
 	client   *http.Client
 }
 
-func NewSuspiciousTxfilter(config *params.ChainConfig, datadir string, exitCh chan struct{}) (*SuspiciousTxfilter, error) {
+func NewSuspiciousTxfilter(config *params.ChainConfig, txfilterConfig *SuspiciousTxfilterConfig, datadir string, exitCh chan struct{}) (*SuspiciousTxfilter, error) {
 	if config.Oasys == nil {
 		return nil, fmt.Errorf("suspicious tx filter is only supported on oasys chain")
 	}
 
 	s := &SuspiciousTxfilter{
-		datadir: datadir,
-		config:  config,
-		exitCh:  exitCh,
-		client:  &http.Client{Timeout: 30 * time.Second},
+		datadir:        datadir,
+		config:         config,
+		txfilterConfig: txfilterConfig,
+		exitCh:         exitCh,
+		client:         &http.Client{Timeout: 30 * time.Second},
 	}
 
-	log.Info("Fetching suspicious txfilter plugin metadata", "url", s.pluginMetadataDownloadURL())
+	// Record the decisions regardless of the backend
+	s.journal = newTxfilterJournal(datadir, exitCh)
+	if txfilterConfig != nil && txfilterConfig.Shadow {
+		log.Warn("Suspicious txfilter is running in shadow mode, blocked transactions are not dropped")
+	}
+
+	switch backend := txfilterConfig.backend(); backend {
+	case TxfilterBackendPlugin:
+	case TxfilterBackendBuiltin:
+		// The builtin filter is a part of the binary, so it does not depend on the metadata
+		// to start, but still obeys the kill switch in it once the metadata is loaded.
+		impl, err := newBuiltinTxfilter(txfilterConfig.Builtin)
+		if err != nil {
+			return nil, err
+		}
+		s.builtin = impl
+		log.Info("Suspicious txfilter builtin loaded", "name", txfilterConfig.Builtin, "version", impl.Version())
+
+		go func() {
+			defer s.startReloadLoop(DefaultPluginReloadInterval)
+
+			if _, err := s.fetchPluginMetadata(); err != nil {
+				if cacheErr := s.loadCachedMetadata(); cacheErr != nil {
+					log.Warn("Failed to load suspicious txfilter metadata, the builtin filter stays enabled", "err", err)
+					return
+				}
+			}
+			s.saveCachedMetadata()
+			log.Info("Suspicious txfilter metadata loaded", "disable", s.metadata.Load().Disable)
+		}()
+		return s, nil
+	case TxfilterBackendSidecar:
+		if txfilterConfig.Sidecar == "" {
+			return nil, fmt.Errorf("sidecar endpoint is required for the %s backend", backend)
+		}
+		sidecar, err := dialSidecarTxfilter(txfilterConfig.Sidecar)
+		if err != nil {
+			return nil, err
+		}
+		s.sidecar = sidecar
+	default:
+		return nil, fmt.Errorf("unknown suspicious txfilter backend: %s", backend)
+	}
+
+	log.Info("Fetching suspicious txfilter plugin metadata", "sources", s.sources())
 	if _, err := s.fetchPluginMetadata(); err != nil {
-		return nil, fmt.Errorf("failed to download plugin metadata: %w", err)
+		// Fall back to the last known good metadata, so that the node can start offline
+		log.Warn("Failed to download plugin metadata, using the cached one", "err", err)
+		if cacheErr := s.loadCachedMetadata(); cacheErr != nil {
+			return nil, fmt.Errorf("failed to download plugin metadata: %w, %w", err, cacheErr)
+		}
 	}
 	log.Info("Suspicious txfilter plugin metadata loaded",
 		"version", s.metadata.Load().Version, "disable", s.metadata.Load().Disable)
@@ -115,6 +173,13 @@ func NewSuspiciousTxfilter(config *params.ChainConfig, datadir string, exitCh ch
 		// Start the reload loop even if the plugin is not loaded successfully.
 		defer s.startReloadLoop(DefaultPluginReloadInterval)
 
+		if s.sidecar != nil {
+			if err := s.loadSidecar(); err != nil {
+				log.Error("Failed to load suspicious txfilter sidecar", "endpoint", txfilterConfig.Sidecar, "err", err)
+			}
+			return
+		}
+
 		// Try to load existing plugin, fetch if missing or invalid
 		pluginPath := s.pluginPath()
 		if _, err := os.Stat(pluginPath); os.IsNotExist(err) || s.loadPlugin() != nil {
@@ -133,10 +198,49 @@ func NewSuspiciousTxfilter(config *params.ChainConfig, datadir string, exitCh ch
 }
 
 func (s *SuspiciousTxfilter) IsReady() bool {
-	if metadata := s.metadata.Load(); metadata == nil || metadata.Disable || s.plugin.Load() == nil {
+	if s.disabled() {
 		return false
 	}
-	return true
+	if s.builtin != nil {
+		return true
+	}
+	if s.sidecar != nil {
+		return s.sidecarReady.Load()
+	}
+	return s.plugin.Load() != nil
+}
+
+// disabled reports whether the filter is turned off by the metadata. The plugin
+// and the sidecar are off until the metadata is loaded, while the builtin filter
+// is only turned off by the kill switch.
+func (s *SuspiciousTxfilter) disabled() bool {
+	metadata := s.metadata.Load()
+	if metadata == nil {
+		return s.builtin == nil
+	}
+	return metadata.Disable
+}
+
+// implementation returns the filter implementation of the configured backend.
+func (s *SuspiciousTxfilter) implementation() (SuspiciousTxfilterPlugin, error) {
+	switch {
+	case s.builtin != nil:
+		return s.builtin, nil
+	case s.sidecar != nil:
+		if !s.sidecarReady.Load() {
+			return nil, fmt.Errorf("sidecar not verified")
+		}
+		return s.sidecar, nil
+	}
+	plugin := s.plugin.Load()
+	if plugin == nil {
+		return nil, fmt.Errorf("plugin not loaded")
+	}
+	impl, err := loadPluginImpl(plugin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load plugin implementation: %w", err)
+	}
+	return impl, nil
 }
 
 // verifyPluginBuild checks that the plugin was built with the same go.mod,
@@ -180,18 +284,14 @@ func (s *SuspiciousTxfilter) verifyPluginVersion(p *plugin.Plugin) error {
 
 func (s *SuspiciousTxfilter) FilterTransaction(txhash common.Hash, msg *Message, logs []*types.Log) (isBlocked bool, reason string, err error) {
 	// Don't filter if the plugin is disabled
-	if metadata := s.metadata.Load(); metadata == nil || metadata.Disable {
+	if s.disabled() {
 		return false, "", nil
 	}
 
 	// Skip filtering if the plugin is not loaded
-	plugin := s.plugin.Load()
-	if plugin == nil {
-		return false, "", fmt.Errorf("plugin not loaded")
-	}
-	impl, err := loadPluginImpl(plugin)
+	impl, err := s.implementation()
 	if err != nil {
-		return false, "", fmt.Errorf("failed to load plugin implementation: %w", err)
+		return false, "", err
 	}
 
 	// Copy data to call the plugin function
@@ -215,57 +315,91 @@ func (s *SuspiciousTxfilter) FilterTransaction(txhash common.Hash, msg *Message,
 		copy(copiedLogs[i].Data, log.Data)
 	}
 
-	return impl.FilterTransaction(txhash, from, to, value, copiedLogs)
+	isBlocked, reason, amount, err := filterTransactionWithAmount(impl, txhash, from, to, value, copiedLogs)
+	if s.journal != nil {
+		decision := newTxfilterDecision(txhash, msg, isBlocked, isBlocked && err == nil && !s.IsShadow(), reason, err)
+		decision.AmountYen = amount
+		decision.Version = s.version(impl)
+		decision.Backend = s.txfilterConfig.backend()
+		s.journal.record(decision)
+	}
+	return isBlocked, reason, err
+}
+
+// version returns the version of the filter without calling the sidecar,
+// as the version of the plugin and the sidecar are verified against the metadata.
+func (s *SuspiciousTxfilter) version(impl SuspiciousTxfilterPlugin) string {
+	if metadata := s.metadata.Load(); s.builtin == nil && metadata != nil {
+		return metadata.Version
+	}
+	return impl.Version()
+}
+
+// IsShadow reports whether the filter is evaluated without dropping the blocked transactions.
+func (s *SuspiciousTxfilter) IsShadow() bool {
+	return s.txfilterConfig != nil && s.txfilterConfig.Shadow
 }
 
+// fetchPlugin downloads the plugin beside the current one, and replaces it only
+// after the download is verified, so that the last known good plugin is kept.
 func (s *SuspiciousTxfilter) fetchPlugin() error {
-	url := s.pluginDownloadURL()
-	log.Info("Downloading suspicious txfilter plugin", "url", url)
-	body, err := s.fetch(url)
+	body, source, err := s.openAny(PluginFileName)
 	if err != nil {
 		return fmt.Errorf("failed to download plugin: %w", err)
 	}
 	defer body.Close()
+	log.Info("Downloading suspicious txfilter plugin", "source", source)
 
-	pluginPath := s.pluginPath()
-	file, err := os.OpenFile(pluginPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644) // Overwrite the plugin file
+	var (
+		pluginPath   = s.pluginPath()
+		downloadPath = pluginPath + ".download"
+	)
+	file, err := os.OpenFile(downloadPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
 	if err != nil {
 		return fmt.Errorf("failed to create plugin file: %w", err)
 	}
+	// Clean up the download, it's renamed if verified
+	defer os.Remove(downloadPath)
 
-	// Clean up the plugin file if the copy fails
-	failCopy := false
-	defer func() {
-		if err := file.Close(); err != nil {
-			log.Error("failed to close plugin file", "path", pluginPath, "err", err)
-			failCopy = true
-		}
-		if failCopy {
-			os.Remove(pluginPath)
-		}
-	}()
-
-	if _, err = io.Copy(file, body); err != nil {
-		failCopy = true
+	_, err = io.Copy(file, body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
 		return fmt.Errorf("failed to copy plugin body to file: %w", err)
 	}
-	return nil
-}
 
-func (s *SuspiciousTxfilter) fetchPluginMetadata() (version string, err error) {
 	metadata := s.metadata.Load()
-	if metadata == nil {
-		// Initialize with default values
-		metadata = &SuspiciousTxfilterPluginMetadata{}
+	bundle, err := s.decodeBundle(metadata)
+	if err != nil {
+		return err
+	}
+	log.Info("Verifying downloaded plugin integrity", "path", downloadPath)
+	if err = s.verifyPluginIntegrity(downloadPath, *bundle, metadata); err != nil {
+		return err
 	}
+	if err = os.Rename(downloadPath, pluginPath); err != nil {
+		return fmt.Errorf("failed to replace plugin file: %w", err)
+	}
+	return nil
+}
 
-	body, err := s.fetch(s.pluginMetadataDownloadURL())
+func (s *SuspiciousTxfilter) fetchPluginMetadata() (version string, err error) {
+	data, err := s.fetchMetadataData()
 	if err != nil {
 		return "", fmt.Errorf("failed to download plugin metadata: %w", err)
 	}
-	defer body.Close()
+	return s.storeMetadata(data)
+}
 
-	if err = json.NewDecoder(body).Decode(metadata); err != nil {
+// storeMetadata validates the raw metadata and makes it the current one.
+func (s *SuspiciousTxfilter) storeMetadata(data []byte) (version string, err error) {
+	metadata := new(SuspiciousTxfilterPluginMetadata)
+	if current := s.metadata.Load(); current != nil {
+		// Keep the fields missing in the new metadata
+		*metadata = *current
+	}
+	if err = json.Unmarshal(data, metadata); err != nil {
 		return "", fmt.Errorf("failed to unmarshal plugin metadata: %w", err)
 	}
 
@@ -283,6 +417,7 @@ func (s *SuspiciousTxfilter) fetchPluginMetadata() (version string, err error) {
 	}
 
 	s.metadata.Store(metadata)
+	s.metadataData.Store(&data)
 	return metadata.Version, nil
 }
 
@@ -304,32 +439,44 @@ func (s *SuspiciousTxfilter) startReloadLoop(reloadInterval time.Duration) {
 			timer.Reset(reloadInterval)
 		case <-s.exitCh:
 			log.Info("Stop suspicious txfilter reload loop", "exitCh", s.exitCh)
+			if s.sidecar != nil {
+				s.sidecar.close()
+			}
 			return
 		}
 	}
 }
 
-func (s *SuspiciousTxfilter) loadPlugin() error {
-	pluginPath := s.pluginPath()
-	metadata := s.metadata.Load()
-
+// decodeBundle decodes the sigstore bundle in the metadata and prepares the verifier.
+func (s *SuspiciousTxfilter) decodeBundle(metadata *SuspiciousTxfilterPluginMetadata) (*bundle.Bundle, error) {
 	bundleData, err := hex.DecodeString(metadata.BundleHex)
 	if err != nil {
-		return fmt.Errorf("failed to decode bundle hex: %w", err)
+		return nil, fmt.Errorf("failed to decode bundle hex: %w", err)
 	}
 
 	var bundle bundle.Bundle
 	if err = bundle.UnmarshalJSON(bundleData); err != nil {
-		return fmt.Errorf("failed to unmarshal bundle: %w", err)
+		return nil, fmt.Errorf("failed to unmarshal bundle: %w", err)
 	}
 
 	// It's ok to update the verifier every time, as loading new plugin is not frequent.
 	if err = s.updateVerifier(metadata); err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+func (s *SuspiciousTxfilter) loadPlugin() error {
+	pluginPath := s.pluginPath()
+	metadata := s.metadata.Load()
+
+	bundle, err := s.decodeBundle(metadata)
+	if err != nil {
 		return err
 	}
 
 	log.Info("Verifying plugin integrity", "path", pluginPath)
-	if err = s.verifyPluginIntegrity(bundle, metadata); err != nil {
+	if err = s.verifyPluginIntegrity(pluginPath, *bundle, metadata); err != nil {
 		return err
 	}
 
@@ -350,6 +497,7 @@ func (s *SuspiciousTxfilter) loadPlugin() error {
 	}
 
 	log.Info("Plugin loaded successfully", "version", metadata.Version)
+	s.saveCachedMetadata()
 
 	oldPlugin := s.plugin.Load()
 
@@ -368,6 +516,43 @@ func (s *SuspiciousTxfilter) loadPlugin() error {
 	return nil
 }
 
+// loadSidecar verifies the config the sidecar runs with against the sigstore
+// bundle in the metadata, in the same way as the plugin file is verified.
+func (s *SuspiciousTxfilter) loadSidecar() error {
+	metadata := s.metadata.Load()
+
+	// Don't serve the sidecar until it's verified again
+	s.sidecarReady.Store(false)
+
+	bundle, err := s.decodeBundle(metadata)
+	if err != nil {
+		return err
+	}
+	config, err := s.sidecar.config()
+	if err != nil {
+		return fmt.Errorf("failed to get sidecar config: %w", err)
+	}
+
+	log.Info("Verifying sidecar config integrity", "endpoint", s.txfilterConfig.Sidecar)
+	if err = s.verifyArtifact(bytes.NewReader(config), *bundle, metadata); err != nil {
+		return err
+	}
+
+	log.Info("Verifying sidecar version", "expected", metadata.Version)
+	version, err := s.sidecar.version()
+	if err != nil {
+		return fmt.Errorf("failed to get sidecar version: %w", err)
+	}
+	if version != metadata.Version {
+		return fmt.Errorf("sidecar version mismatch: %s != %s", version, metadata.Version)
+	}
+
+	s.sidecarReady.Store(true)
+	log.Info("Sidecar loaded successfully", "version", metadata.Version)
+	s.saveCachedMetadata()
+	return nil
+}
+
 func (s *SuspiciousTxfilter) updateVerifier(metadata *SuspiciousTxfilterPluginMetadata) error {
 	if metadata.IsKeyless {
 		// Get the public TUF root from Sigstore
@@ -411,19 +596,23 @@ func (s *SuspiciousTxfilter) updateVerifier(metadata *SuspiciousTxfilterPluginMe
 
 // verifyPluginIntegrity verifies that the plugin .so file has not been tampered
 // with by checking its Cosign signature bundle against the expected identity.
-func (s *SuspiciousTxfilter) verifyPluginIntegrity(bundle bundle.Bundle, metadata *SuspiciousTxfilterPluginMetadata) error {
-	if s.verifier == nil {
-		return fmt.Errorf("verifier not initialized")
-	}
-
-	pluginPath := s.pluginPath()
+func (s *SuspiciousTxfilter) verifyPluginIntegrity(pluginPath string, bundle bundle.Bundle, metadata *SuspiciousTxfilterPluginMetadata) error {
 	file, err := os.Open(pluginPath)
 	if err != nil {
 		return fmt.Errorf("failed to open plugin file: %w", err)
 	}
 	defer file.Close()
 
-	artifactPolicy := sigverify.WithArtifact(file)
+	return s.verifyArtifact(file, bundle, metadata)
+}
+
+// verifyArtifact checks the Cosign signature bundle of the artifact against the expected identity.
+func (s *SuspiciousTxfilter) verifyArtifact(artifact io.Reader, bundle bundle.Bundle, metadata *SuspiciousTxfilterPluginMetadata) error {
+	if s.verifier == nil {
+		return fmt.Errorf("verifier not initialized")
+	}
+
+	artifactPolicy := sigverify.WithArtifact(artifact)
 
 	if metadata.IsKeyless {
 		// Execute the verification
@@ -459,6 +648,23 @@ func (s *SuspiciousTxfilter) reloadPlugin() (reloaded bool, err error) {
 		return
 	}
 
+	if s.builtin != nil {
+		// Only the kill switch applies to the builtin filter
+		s.saveCachedMetadata()
+		return false, nil
+	}
+	if s.sidecar != nil {
+		// Skip reloading if the verified sidecar version matches the metadata version
+		if version, err := s.sidecar.version(); err == nil && version == metadataVersion && s.sidecarReady.Load() {
+			return false, nil
+		}
+		if err = s.loadSidecar(); err != nil {
+			err = fmt.Errorf("failed to load sidecar: %w", err)
+			return
+		}
+		return true, nil
+	}
+
 	// Skip reloading if the plugin version matches the metadata version
 	if pluginVersion, err := pluginVersion(s.plugin.Load()); err == nil && pluginVersion == metadataVersion {
 		return false, nil
@@ -516,26 +722,6 @@ func (s *SuspiciousTxfilter) buildPluginURL(filename string) string {
 	return fmt.Sprintf("http://%s:%s/%s", ip, port, filename)
 }
 
-func (s *SuspiciousTxfilter) pluginDownloadURL() string {
-	return s.buildPluginURL(PluginFileName)
-}
-
-func (s *SuspiciousTxfilter) pluginMetadataDownloadURL() string {
-	return s.buildPluginURL(PluginMetadataFileName)
-}
-
-func (s *SuspiciousTxfilter) fetch(url string) (io.ReadCloser, error) {
-	resp, err := s.client.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to download file: url: %s, err: %w", url, err)
-	}
-	if resp.StatusCode != http.StatusOK {
-		resp.Body.Close()
-		return nil, fmt.Errorf("download error: status %d, url: %s", resp.StatusCode, url)
-	}
-	return resp.Body, nil
-}
-
 func pluginVersion(p *plugin.Plugin) (string, error) {
 	if p == nil {
 		return "", fmt.Errorf("plugin is nil")
@@ -0,0 +1,204 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	txfilterlog "github.com/ethereum/go-ethereum/txfilter/log"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	// The suspicious txfilter decision journal file name, the rotated files are kept beside it.
+	JournalFileName = "suspicious_txfilter_decisions.jsonl"
+
+	journalMaxSize     = 100 // Megabytes of the journal file before it gets rotated
+	journalMaxBackups  = 10  // Number of rotated journal files to keep
+	journalQueueSize   = 1024
+	maxRecentDecisions = 1024
+)
+
+var (
+	decisionAllowedCounter = metrics.NewRegisteredCounter("txfilter/decision/allowed", nil)
+	decisionBlockedCounter = metrics.NewRegisteredCounter("txfilter/decision/blocked", nil)
+	decisionShadowCounter  = metrics.NewRegisteredCounter("txfilter/decision/shadow", nil) // Blocked but not enforced
+	decisionErrorCounter   = metrics.NewRegisteredCounter("txfilter/decision/error", nil)
+	journalDroppedCounter  = metrics.NewRegisteredCounter("txfilter/journal/dropped", nil)
+
+	errTxfilterNotRunning = errors.New("suspicious txfilter is not running")
+)
+
+// SuspiciousTxfilterAmountReporter is optionally implemented by the filter to report
+// the JPY-equivalent amount of the transaction along with the decision, which is
+// recorded in the decision journal. The amount is nil if unknown.
+type SuspiciousTxfilterAmountReporter interface {
+	FilterTransactionWithAmount(txhash common.Hash, from, to common.Address, value [32]byte, logs []txfilterlog.Log) (isBlocked bool, reason string, amount *uint64, err error)
+}
+
+// TxfilterDecision is a decision made by the suspicious txfilter.
+type TxfilterDecision struct {
+	Time      uint64          `json:"time"` // Unix time in seconds
+	TxHash    common.Hash     `json:"txHash"`
+	From      common.Address  `json:"from"`
+	To        *common.Address `json:"to"`
+	Value     *hexutil.Big    `json:"value"`
+	AmountYen *uint64         `json:"amountYen,omitempty"` // Set if the filter reports it
+	Blocked   bool            `json:"blocked"`
+	Enforced  bool            `json:"enforced"` // Whether the blocked transaction was dropped, false in shadow mode
+	Reason    string          `json:"reason,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	Version   string          `json:"version"`
+	Backend   string          `json:"backend"`
+}
+
+// txfilterJournal writes the decisions to a rotating on-disk journal in the
+// background, and keeps the most recent ones in memory.
+type txfilterJournal struct {
+	logger *lumberjack.Logger
+	queue  chan *TxfilterDecision
+
+	mu      sync.RWMutex
+	recents []*TxfilterDecision // Ring buffer of the recent decisions
+	head    int                 // Index of the next slot to be used
+}
+
+func newTxfilterJournal(datadir string, exitCh chan struct{}) *txfilterJournal {
+	j := &txfilterJournal{
+		logger: &lumberjack.Logger{
+			Filename:   filepath.Join(datadir, JournalFileName),
+			MaxSize:    journalMaxSize,
+			MaxBackups: journalMaxBackups,
+		},
+		queue:   make(chan *TxfilterDecision, journalQueueSize),
+		recents: make([]*TxfilterDecision, 0, maxRecentDecisions),
+	}
+	go j.loop(exitCh)
+	return j
+}
+
+func (j *txfilterJournal) loop(exitCh chan struct{}) {
+	defer j.logger.Close()
+
+	for {
+		select {
+		case decision := <-j.queue:
+			data, err := json.Marshal(decision)
+			if err != nil {
+				log.Error("Failed to encode txfilter decision", "txhash", decision.TxHash, "err", err)
+				continue
+			}
+			if _, err := j.logger.Write(append(data, '\n')); err != nil {
+				log.Error("Failed to write txfilter decision", "txhash", decision.TxHash, "err", err)
+			}
+		case <-exitCh:
+			return
+		}
+	}
+}
+
+// record keeps the decision in memory and queues it to be written, so that the
+// block execution is not blocked by the disk.
+func (j *txfilterJournal) record(decision *TxfilterDecision) {
+	switch {
+	case decision.Error != "":
+		decisionErrorCounter.Inc(1)
+	case decision.Blocked && decision.Enforced:
+		decisionBlockedCounter.Inc(1)
+	case decision.Blocked:
+		decisionShadowCounter.Inc(1)
+	default:
+		decisionAllowedCounter.Inc(1)
+	}
+
+	j.mu.Lock()
+	if len(j.recents) < maxRecentDecisions {
+		j.recents = append(j.recents, decision)
+	} else {
+		j.recents[j.head] = decision
+	}
+	j.head = (j.head + 1) % maxRecentDecisions
+	j.mu.Unlock()
+
+	select {
+	case j.queue <- decision:
+	default:
+		journalDroppedCounter.Inc(1)
+	}
+}
+
+// recentDecisions returns up to limit decisions, the newest first.
+func (j *txfilterJournal) recentDecisions(limit int, blockedOnly bool) []*TxfilterDecision {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	decisions := make([]*TxfilterDecision, 0, min(limit, len(j.recents)))
+	for i := 1; i <= len(j.recents) && len(decisions) < limit; i++ {
+		decision := j.recents[(j.head-i+len(j.recents))%len(j.recents)]
+		if blockedOnly && !decision.Blocked {
+			continue
+		}
+		decisions = append(decisions, decision)
+	}
+	return decisions
+}
+
+// SuspiciousTxfilterAPI provides the decisions of the suspicious txfilter.
+type SuspiciousTxfilterAPI struct{}
+
+// NewSuspiciousTxfilterAPI creates the API of the global suspicious txfilter,
+// which is created when the miner starts.
+func NewSuspiciousTxfilterAPI() *SuspiciousTxfilterAPI {
+	return &SuspiciousTxfilterAPI{}
+}
+
+// RecentDecisions returns the recent decisions of the suspicious txfilter, the newest first.
+func (api *SuspiciousTxfilterAPI) RecentDecisions(limit *int, blockedOnly *bool) ([]*TxfilterDecision, error) {
+	filter := SuspiciousTxfilterGlobal
+	if filter == nil || filter.journal == nil {
+		return nil, errTxfilterNotRunning
+	}
+	n := 100
+	if limit != nil {
+		if *limit <= 0 {
+			return nil, errors.New("limit must be positive")
+		}
+		n = min(*limit, maxRecentDecisions)
+	}
+	return filter.journal.recentDecisions(n, blockedOnly != nil && *blockedOnly), nil
+}
+
+// filterTransactionWithAmount calls the filter, along with the amount if it
+// implements SuspiciousTxfilterAmountReporter.
+func filterTransactionWithAmount(impl SuspiciousTxfilterPlugin, txhash common.Hash, from, to common.Address, value [32]byte, logs []txfilterlog.Log) (isBlocked bool, reason string, amount *uint64, err error) {
+	if reporter, ok := impl.(SuspiciousTxfilterAmountReporter); ok {
+		return reporter.FilterTransactionWithAmount(txhash, from, to, value, logs)
+	}
+	isBlocked, reason, err = impl.FilterTransaction(txhash, from, to, value, logs)
+	return isBlocked, reason, nil, err
+}
+
+func newTxfilterDecision(txhash common.Hash, msg *Message, blocked, enforced bool, reason string, err error) *TxfilterDecision {
+	decision := &TxfilterDecision{
+		Time:     uint64(time.Now().Unix()),
+		TxHash:   txhash,
+		From:     msg.From,
+		To:       msg.To,
+		Blocked:  blocked,
+		Enforced: enforced,
+		Reason:   reason,
+	}
+	if msg.Value != nil {
+		decision.Value = (*hexutil.Big)(msg.Value)
+	}
+	if err != nil {
+		decision.Error = err.Error()
+	}
+	return decision
+}
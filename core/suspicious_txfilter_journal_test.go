@@ -0,0 +1,138 @@
+package core
+
+import (
+	"bufio"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestSuspiciousTxfilter_Journal(t *testing.T) {
+	var (
+		datadir = t.TempDir()
+		exitCh  = make(chan struct{})
+		config  = &SuspiciousTxfilterConfig{Backend: TxfilterBackendBuiltin, Builtin: "test-builtin", Shadow: true}
+	)
+	filter, err := NewSuspiciousTxfilter(testConfig, config, datadir, exitCh)
+	if err != nil {
+		t.Fatalf("Failed to create SuspiciousTxfilter: %v", err)
+	}
+	if !filter.IsShadow() {
+		t.Fatal("Filter is not in shadow mode")
+	}
+
+	api := NewSuspiciousTxfilterAPI()
+	if _, err := api.RecentDecisions(nil, nil); err != errTxfilterNotRunning {
+		t.Fatalf("Expected error %v, got %v", errTxfilterNotRunning, err)
+	}
+	SuspiciousTxfilterGlobal = filter
+	defer func() { SuspiciousTxfilterGlobal = nil }()
+
+	allowed := common.HexToAddress("0x02")
+	for i := 0; i < 5; i++ {
+		to := allowed
+		if i%2 == 1 {
+			to = testBlockedAddress
+		}
+		msg := &Message{From: common.HexToAddress("0x01"), To: &to, Value: big.NewInt(int64(i))}
+		isBlocked, _, err := filter.FilterTransaction(common.Hash{byte(i)}, msg, nil)
+		if err != nil || isBlocked != (i%2 == 1) {
+			t.Fatalf("Unexpected result: isBlocked=%v, err=%v", isBlocked, err)
+		}
+	}
+	// The amount is reported along with the decision, without filtering twice
+	if filtered := filter.builtin.(*testTxfilter).filtered; filtered != 5 {
+		t.Errorf("Filter calls mismatch, got: %d, want: 5", filtered)
+	}
+
+	limit, blockedOnly := 10, true
+	decisions, err := api.RecentDecisions(&limit, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decisions) != 5 {
+		t.Fatalf("Decisions mismatch, got: %d, want: 5", len(decisions))
+	}
+	for i, decision := range decisions {
+		// The newest first
+		want := 4 - i
+		if decision.TxHash != (common.Hash{byte(want)}) {
+			t.Errorf("Decision %d, txhash mismatch: %s", i, decision.TxHash)
+		}
+		if decision.Blocked != (want%2 == 1) || decision.Enforced {
+			t.Errorf("Decision %d, unexpected blocked=%v, enforced=%v", i, decision.Blocked, decision.Enforced)
+		}
+		if decision.AmountYen == nil || *decision.AmountYen != uint64(want*100) {
+			t.Errorf("Decision %d, amount mismatch: %v", i, decision.AmountYen)
+		}
+		if decision.Version != "1.0.0" || decision.Backend != TxfilterBackendBuiltin {
+			t.Errorf("Decision %d, unexpected version=%s, backend=%s", i, decision.Version, decision.Backend)
+		}
+	}
+
+	decisions, err = api.RecentDecisions(nil, &blockedOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decisions) != 2 || decisions[0].TxHash != (common.Hash{3}) || decisions[1].TxHash != (common.Hash{1}) {
+		t.Errorf("Unexpected blocked decisions: %v", decisions)
+	}
+
+	// Wait for the decisions to be written to the journal
+	path := filepath.Join(datadir, JournalFileName)
+	var lines []*TxfilterDecision
+	for i := 0; i < 100 && len(lines) < 5; i++ {
+		time.Sleep(20 * time.Millisecond)
+		lines = lines[:0]
+		file, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			decision := new(TxfilterDecision)
+			if err := json.Unmarshal(scanner.Bytes(), decision); err != nil {
+				t.Fatalf("Invalid journal line: %v", err)
+			}
+			lines = append(lines, decision)
+		}
+		file.Close()
+	}
+	close(exitCh)
+	if len(lines) != 5 {
+		t.Fatalf("Journal lines mismatch, got: %d, want: 5", len(lines))
+	}
+	if lines[0].TxHash != (common.Hash{0}) || !lines[1].Blocked {
+		t.Errorf("Unexpected journal: %v", lines)
+	}
+}
+
+func TestTxfilterJournal_recentDecisions(t *testing.T) {
+	j := &txfilterJournal{
+		queue:   make(chan *TxfilterDecision, 1),
+		recents: make([]*TxfilterDecision, 0, maxRecentDecisions),
+	}
+	total := maxRecentDecisions + 10
+	for i := 0; i < total; i++ {
+		j.record(&TxfilterDecision{Time: uint64(i), Blocked: i%3 == 0})
+	}
+	decisions := j.recentDecisions(maxRecentDecisions+100, false)
+	if len(decisions) != maxRecentDecisions {
+		t.Fatalf("Decisions mismatch, got: %d, want: %d", len(decisions), maxRecentDecisions)
+	}
+	for i, decision := range decisions {
+		if want := uint64(total - 1 - i); decision.Time != want {
+			t.Fatalf("Decision %d, time mismatch, got: %d, want: %d", i, decision.Time, want)
+		}
+	}
+	for _, decision := range j.recentDecisions(10, true) {
+		if !decision.Blocked {
+			t.Fatal("Allowed decision is returned")
+		}
+	}
+}
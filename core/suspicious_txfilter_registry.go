@@ -0,0 +1,235 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	txfilterlog "github.com/ethereum/go-ethereum/txfilter/log"
+)
+
+// Backends providing the suspicious txfilter implementation.
+const (
+	// The Go plugin downloaded from the CDN, this is the default.
+	TxfilterBackendPlugin = "plugin"
+	// The filter compiled into the binary and registered by `RegisterSuspiciousTxfilter`.
+	TxfilterBackendBuiltin = "builtin"
+	// The filter served by a local JSON-RPC sidecar process.
+	TxfilterBackendSidecar = "sidecar"
+
+	// The suspicious txfilter sidecar metadata file name
+	SidecarMetadataFileName = "suspicious_txfilter_sidecar.json"
+
+	// Timeout of a single call to the sidecar. The filter runs in the block
+	// execution, so a stalled sidecar must not stall the miner.
+	sidecarCallTimeout = 500 * time.Millisecond
+)
+
+// SuspiciousTxfilterConfig selects the implementation of the suspicious txfilter.
+type SuspiciousTxfilterConfig struct {
+	Backend string // One of the TxfilterBackend*, empty means the plugin
+	Builtin string // Name of the builtin filter
+	Sidecar string // Endpoint of the sidecar (http, ws or ipc)
+	Shadow  bool   // Evaluate the filter and journal the decisions without dropping transactions
+
+	// Sources of the plugin and metadata files tried in order, each is a local
+	// directory, an HTTP mirror base URL or "cdn". Empty means the default CDN.
+	Sources []string
+	// Hex encoded sha256 hash of the metadata file, the metadata is rejected unless it matches
+	MetadataHash string
+}
+
+func (c *SuspiciousTxfilterConfig) backend() string {
+	if c == nil || c.Backend == "" {
+		return TxfilterBackendPlugin
+	}
+	return c.Backend
+}
+
+// SuspiciousTxfilterFactory creates a builtin filter implementation.
+type SuspiciousTxfilterFactory func() (SuspiciousTxfilterPlugin, error)
+
+var (
+	builtinTxfiltersMu sync.RWMutex
+	builtinTxfilters   = make(map[string]SuspiciousTxfilterFactory)
+)
+
+// RegisterSuspiciousTxfilter makes a filter implementation available as the
+// builtin backend under the given name. It is intended to be called from the
+// init function of the package implementing the filter, and panics if the name
+// is registered twice.
+func RegisterSuspiciousTxfilter(name string, factory SuspiciousTxfilterFactory) {
+	builtinTxfiltersMu.Lock()
+	defer builtinTxfiltersMu.Unlock()
+
+	if factory == nil {
+		panic("suspicious txfilter factory is nil")
+	}
+	if _, ok := builtinTxfilters[name]; ok {
+		panic(fmt.Sprintf("suspicious txfilter %q is already registered", name))
+	}
+	builtinTxfilters[name] = factory
+}
+
+// SuspiciousTxfilters returns the sorted names of the registered builtin filters.
+func SuspiciousTxfilters() []string {
+	builtinTxfiltersMu.RLock()
+	defer builtinTxfiltersMu.RUnlock()
+
+	names := make([]string, 0, len(builtinTxfilters))
+	for name := range builtinTxfilters {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func newBuiltinTxfilter(name string) (SuspiciousTxfilterPlugin, error) {
+	builtinTxfiltersMu.RLock()
+	factory, ok := builtinTxfilters[name]
+	builtinTxfiltersMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown builtin suspicious txfilter: %q, available: %v", name, SuspiciousTxfilters())
+	}
+	return factory()
+}
+
+// SidecarFilterArgs is the argument of `txfilter_filterTransaction` served by the sidecar.
+type SidecarFilterArgs struct {
+	TxHash common.Hash       `json:"txHash"`
+	From   common.Address    `json:"from"`
+	To     common.Address    `json:"to"`
+	Value  hexutil.Bytes     `json:"value"`
+	Logs   []txfilterlog.Log `json:"logs"`
+}
+
+// SidecarFilterResult is the result of `txfilter_filterTransaction` served by the sidecar.
+// The filter may return both the decision and an error, so the error is not
+// returned as the JSON-RPC error. The amount is reported along with the decision
+// to journal it without another round trip to the sidecar.
+type SidecarFilterResult struct {
+	Blocked   bool            `json:"blocked"`
+	Reason    string          `json:"reason,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	AmountYen *hexutil.Uint64 `json:"amountYen,omitempty"` // Nil if the filter doesn't report it
+}
+
+// SuspiciousTxfilterSidecarAPI serves a filter implementation over JSON-RPC
+// under the "txfilter" namespace, so that it can run as a sidecar process of
+// the node. The config is the raw config the filter runs with, which the node
+// verifies against the sigstore bundle in the sidecar metadata.
+type SuspiciousTxfilterSidecarAPI struct {
+	impl   SuspiciousTxfilterPlugin
+	config []byte
+}
+
+// NewSuspiciousTxfilterSidecarAPI creates the sidecar API serving the given filter.
+func NewSuspiciousTxfilterSidecarAPI(impl SuspiciousTxfilterPlugin, config []byte) *SuspiciousTxfilterSidecarAPI {
+	return &SuspiciousTxfilterSidecarAPI{impl: impl, config: config}
+}
+
+// Version returns the version of the filter.
+func (api *SuspiciousTxfilterSidecarAPI) Version() string {
+	return api.impl.Version()
+}
+
+// Config returns the raw config the filter runs with.
+func (api *SuspiciousTxfilterSidecarAPI) Config() hexutil.Bytes {
+	return api.config
+}
+
+// Clear drops the runtime state of the filter.
+func (api *SuspiciousTxfilterSidecarAPI) Clear() error {
+	return api.impl.Clear()
+}
+
+// FilterTransaction decides whether to block the transaction, and reports the
+// JPY-equivalent amount of it if the filter implements SuspiciousTxfilterAmountReporter.
+func (api *SuspiciousTxfilterSidecarAPI) FilterTransaction(args SidecarFilterArgs) (*SidecarFilterResult, error) {
+	if len(args.Value) > 32 {
+		return nil, errors.New("value exceeds 32 bytes")
+	}
+	var value [32]byte
+	copy(value[32-len(args.Value):], args.Value)
+
+	blocked, reason, amount, err := filterTransactionWithAmount(api.impl, args.TxHash, args.From, args.To, value, args.Logs)
+	result := &SidecarFilterResult{Blocked: blocked, Reason: reason, AmountYen: (*hexutil.Uint64)(amount)}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result, nil
+}
+
+// sidecarTxfilter is the client of the SuspiciousTxfilterSidecarAPI, which
+// implements the same contract as the plugin.
+type sidecarTxfilter struct {
+	client *rpc.Client
+}
+
+func dialSidecarTxfilter(endpoint string) (*sidecarTxfilter, error) {
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial sidecar: %w", err)
+	}
+	return &sidecarTxfilter{client: client}, nil
+}
+
+func (s *sidecarTxfilter) call(result interface{}, method string, args ...interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), sidecarCallTimeout)
+	defer cancel()
+	return s.client.CallContext(ctx, result, method, args...)
+}
+
+func (s *sidecarTxfilter) version() (string, error) {
+	var version string
+	err := s.call(&version, "txfilter_version")
+	return version, err
+}
+
+func (s *sidecarTxfilter) config() ([]byte, error) {
+	var config hexutil.Bytes
+	err := s.call(&config, "txfilter_config")
+	return config, err
+}
+
+// Version implements SuspiciousTxfilterPlugin, it returns an empty string if the sidecar is unreachable.
+func (s *sidecarTxfilter) Version() string {
+	version, _ := s.version()
+	return version
+}
+
+// Clear implements SuspiciousTxfilterPlugin.
+func (s *sidecarTxfilter) Clear() error {
+	return s.call(nil, "txfilter_clear")
+}
+
+// FilterTransaction implements SuspiciousTxfilterPlugin.
+func (s *sidecarTxfilter) FilterTransaction(txhash common.Hash, from, to common.Address, value [32]byte, logs []txfilterlog.Log) (isBlocked bool, reason string, err error) {
+	isBlocked, reason, _, err = s.FilterTransactionWithAmount(txhash, from, to, value, logs)
+	return isBlocked, reason, err
+}
+
+// FilterTransactionWithAmount implements SuspiciousTxfilterAmountReporter, the
+// amount is nil if not reported by the sidecar.
+func (s *sidecarTxfilter) FilterTransactionWithAmount(txhash common.Hash, from, to common.Address, value [32]byte, logs []txfilterlog.Log) (isBlocked bool, reason string, amount *uint64, err error) {
+	var result SidecarFilterResult
+	args := SidecarFilterArgs{TxHash: txhash, From: from, To: to, Value: value[:], Logs: logs}
+	if err := s.call(&result, "txfilter_filterTransaction", args); err != nil {
+		return false, "", nil, fmt.Errorf("failed to call sidecar: %w", err)
+	}
+	if result.Error != "" {
+		err = errors.New(result.Error)
+	}
+	return result.Blocked, result.Reason, (*uint64)(result.AmountYen), err
+}
+
+func (s *sidecarTxfilter) close() {
+	s.client.Close()
+}
@@ -0,0 +1,317 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	txfilterlog "github.com/ethereum/go-ethereum/txfilter/log"
+	"github.com/sigstore/sigstore-go/pkg/bundle"
+	"github.com/sigstore/sigstore-go/pkg/sign"
+)
+
+var testBlockedAddress = common.HexToAddress("0x1234567890123456789012345678901234567890")
+
+func init() {
+	RegisterSuspiciousTxfilter("test-builtin", func() (SuspiciousTxfilterPlugin, error) {
+		return &testTxfilter{blocked: testBlockedAddress}, nil
+	})
+}
+
+// testTxfilter blocks the transactions sent to the blocked address.
+type testTxfilter struct {
+	blocked  common.Address
+	cleared  bool
+	filtered int // Number of the calls to filter
+}
+
+func (f *testTxfilter) Version() string { return "1.0.0" }
+
+func (f *testTxfilter) Clear() error {
+	f.cleared = true
+	return nil
+}
+
+func (f *testTxfilter) FilterTransaction(txhash common.Hash, from, to common.Address, value [32]byte, logs []txfilterlog.Log) (bool, string, error) {
+	isBlocked, reason, _, err := f.FilterTransactionWithAmount(txhash, from, to, value, logs)
+	return isBlocked, reason, err
+}
+
+func (f *testTxfilter) FilterTransactionWithAmount(txhash common.Hash, from, to common.Address, value [32]byte, logs []txfilterlog.Log) (bool, string, *uint64, error) {
+	f.filtered++
+	amount := new(big.Int).SetBytes(value[:]).Uint64() * 100
+	if to != f.blocked {
+		return false, "", &amount, nil
+	}
+	if len(logs) > 0 {
+		return true, "blocked with logs", &amount, errors.New("logs are not expected")
+	}
+	return true, "blocked address", &amount, nil
+}
+
+func TestSuspiciousTxfilter_Builtin(t *testing.T) {
+	blocked := testBlockedAddress
+
+	if _, err := NewSuspiciousTxfilter(testConfig, &SuspiciousTxfilterConfig{Backend: TxfilterBackendBuiltin, Builtin: "unknown"}, t.TempDir(), nil); err == nil {
+		t.Fatal("Expected error for unknown builtin filter")
+	}
+	if _, err := NewSuspiciousTxfilter(testConfig, &SuspiciousTxfilterConfig{Backend: "unknown"}, t.TempDir(), nil); err == nil {
+		t.Fatal("Expected error for unknown backend")
+	}
+
+	// The builtin filter does not depend on the metadata, so no server is needed.
+	exitCh := make(chan struct{})
+	defer close(exitCh)
+	config := &SuspiciousTxfilterConfig{Backend: TxfilterBackendBuiltin, Builtin: "test-builtin", Sources: []string{t.TempDir()}}
+	filter, err := NewSuspiciousTxfilter(testConfig, config, t.TempDir(), exitCh)
+	if err != nil {
+		t.Fatalf("Failed to create SuspiciousTxfilter: %v", err)
+	}
+	if !filter.IsReady() {
+		t.Fatal("Builtin filter is not ready")
+	}
+
+	msg := &Message{From: common.HexToAddress("0x01"), To: &blocked, Value: big.NewInt(1)}
+	isBlocked, reason, err := filter.FilterTransaction(common.Hash{}, msg, nil)
+	if err != nil || !isBlocked || reason != "blocked address" {
+		t.Errorf("Unexpected result: isBlocked=%v, reason=%s, err=%v", isBlocked, reason, err)
+	}
+	other := common.HexToAddress("0x02")
+	msg.To = &other
+	if isBlocked, _, err := filter.FilterTransaction(common.Hash{}, msg, nil); err != nil || isBlocked {
+		t.Errorf("Unexpected result: isBlocked=%v, err=%v", isBlocked, err)
+	}
+
+	found := false
+	for _, name := range SuspiciousTxfilters() {
+		found = found || name == "test-builtin"
+	}
+	if !found {
+		t.Error("Registered filter is not listed")
+	}
+}
+
+func TestSuspiciousTxfilter_Sidecar(t *testing.T) {
+	blocked := testBlockedAddress
+	impl := &testTxfilter{blocked: blocked}
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("txfilter", NewSuspiciousTxfilterSidecarAPI(impl, []byte(`{"version":1}`))); err != nil {
+		t.Fatal(err)
+	}
+	sidecar := &sidecarTxfilter{client: rpc.DialInProc(server)}
+	defer sidecar.close()
+
+	filter := &SuspiciousTxfilter{
+		config:         testConfig,
+		txfilterConfig: &SuspiciousTxfilterConfig{Backend: TxfilterBackendSidecar, Sidecar: "inproc"},
+		sidecar:        sidecar,
+	}
+	filter.metadata.Store(&SuspiciousTxfilterPluginMetadata{Version: "1.0.0"})
+
+	// The sidecar must not be used until its config is verified.
+	msg := &Message{From: common.HexToAddress("0x01"), To: &blocked, Value: big.NewInt(1)}
+	if filter.IsReady() {
+		t.Fatal("Unverified sidecar is ready")
+	}
+	if _, _, err := filter.FilterTransaction(common.Hash{}, msg, nil); err == nil {
+		t.Fatal("Expected error from unverified sidecar")
+	}
+	filter.sidecarReady.Store(true)
+	if !filter.IsReady() {
+		t.Fatal("Verified sidecar is not ready")
+	}
+
+	isBlocked, reason, err := filter.FilterTransaction(common.Hash{}, msg, nil)
+	if err != nil || !isBlocked || reason != "blocked address" {
+		t.Errorf("Unexpected result: isBlocked=%v, reason=%s, err=%v", isBlocked, reason, err)
+	}
+
+	// The amount is reported along with the decision.
+	_, _, amount, err := sidecar.FilterTransactionWithAmount(common.Hash{}, msg.From, blocked, [32]byte{31: 3}, nil)
+	if err != nil || amount == nil || *amount != 300 {
+		t.Errorf("Unexpected amount: %v, err=%v", amount, err)
+	}
+
+	// Both the decision and the error of the filter are passed through.
+	logs := []*types.Log{{Address: blocked, Topics: []common.Hash{{0x01}}, Data: []byte{0x02}}}
+	isBlocked, reason, err = filter.FilterTransaction(common.Hash{}, msg, logs)
+	if err == nil || err.Error() != "logs are not expected" || !isBlocked || reason != "blocked with logs" {
+		t.Errorf("Unexpected result: isBlocked=%v, reason=%s, err=%v", isBlocked, reason, err)
+	}
+
+	if version, err := sidecar.version(); err != nil || version != "1.0.0" {
+		t.Errorf("Unexpected version: %s, err=%v", version, err)
+	}
+	if config, err := sidecar.config(); err != nil || string(config) != `{"version":1}` {
+		t.Errorf("Unexpected config: %s, err=%v", config, err)
+	}
+	if err := sidecar.Clear(); err != nil || !impl.cleared {
+		t.Errorf("Failed to clear sidecar: %v", err)
+	}
+
+	// The sidecar is gated by the metadata as well as the plugin.
+	filter.metadata.Store(&SuspiciousTxfilterPluginMetadata{Version: "1.0.0", Disable: true})
+	if filter.IsReady() {
+		t.Error("Disabled sidecar is ready")
+	}
+	if isBlocked, _, err := filter.FilterTransaction(common.Hash{}, msg, nil); err != nil || isBlocked {
+		t.Errorf("Disabled sidecar filtered the transaction: isBlocked=%v, err=%v", isBlocked, err)
+	}
+}
+
+func TestSuspiciousTxfilter_BuiltinDisable(t *testing.T) {
+	// Serve the metadata with the kill switch from a local source
+	source := t.TempDir()
+	key := "00"
+	metadata, _ := json.Marshal(&SuspiciousTxfilterPluginMetadata{Version: "1.0.0", BundlePublicKeyHex: &key, Disable: true})
+	if err := os.WriteFile(filepath.Join(source, PluginMetadataFileName), metadata, 0644); err != nil {
+		t.Fatal(err)
+	}
+	exitCh := make(chan struct{})
+	defer close(exitCh)
+
+	datadir := t.TempDir()
+	config := &SuspiciousTxfilterConfig{Backend: TxfilterBackendBuiltin, Builtin: "test-builtin", Sources: []string{source}}
+	filter, err := NewSuspiciousTxfilter(testConfig, config, datadir, exitCh)
+	if err != nil {
+		t.Fatalf("Failed to create SuspiciousTxfilter: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for filter.IsReady() {
+		if time.Now().After(deadline) {
+			t.Fatal("Builtin filter is not disabled by the metadata")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	blocked := testBlockedAddress
+	msg := &Message{From: common.HexToAddress("0x01"), To: &blocked, Value: big.NewInt(1)}
+	if isBlocked, _, err := filter.FilterTransaction(common.Hash{}, msg, nil); err != nil || isBlocked {
+		t.Errorf("Disabled builtin filter filtered the transaction: isBlocked=%v, err=%v", isBlocked, err)
+	}
+
+	// The metadata is cached as the last known good one
+	deadline = time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(filepath.Join(datadir, PluginMetadataFileName)); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Metadata is not cached")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// Re-enable the filter with the new metadata
+	metadata, _ = json.Marshal(&SuspiciousTxfilterPluginMetadata{Version: "1.0.0", BundlePublicKeyHex: &key})
+	if err := os.WriteFile(filepath.Join(source, PluginMetadataFileName), metadata, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if reloaded, err := filter.reloadPlugin(); err != nil || reloaded {
+		t.Fatalf("Unexpected reload: reloaded=%v, err=%v", reloaded, err)
+	}
+	if !filter.IsReady() {
+		t.Error("Builtin filter is not enabled again by the metadata")
+	}
+}
+
+func TestSuspiciousTxfilter_SidecarVerify(t *testing.T) {
+	config := []byte(`{"version":1}`)
+
+	// Sign the sidecar config with an ephemeral key, as cosign does with a key pair
+	keypair, err := sign.NewEphemeralKeypair(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pb, err := sign.Bundle(&sign.PlainData{Data: config}, keypair, sign.BundleOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := bundle.NewBundle(pb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundleJSON, err := b.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey, err := keypair.GetPublicKeyPem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKeyHex := hex.EncodeToString([]byte(pubKey))
+
+	newFilter := func(config []byte, version string) *SuspiciousTxfilter {
+		server := rpc.NewServer()
+		t.Cleanup(server.Stop)
+		if err := server.RegisterName("txfilter", NewSuspiciousTxfilterSidecarAPI(&testTxfilter{blocked: testBlockedAddress}, config)); err != nil {
+			t.Fatal(err)
+		}
+		sidecar := &sidecarTxfilter{client: rpc.DialInProc(server)}
+		t.Cleanup(sidecar.close)
+
+		filter := &SuspiciousTxfilter{
+			datadir:        t.TempDir(),
+			config:         testConfig,
+			txfilterConfig: &SuspiciousTxfilterConfig{Backend: TxfilterBackendSidecar, Sidecar: "inproc"},
+			sidecar:        sidecar,
+		}
+		filter.metadata.Store(&SuspiciousTxfilterPluginMetadata{
+			Version:            version,
+			BundleHex:          hex.EncodeToString(bundleJSON),
+			BundlePublicKeyHex: &pubKeyHex,
+		})
+		return filter
+	}
+
+	// The signed config is verified and the sidecar is served
+	filter := newFilter(config, "1.0.0")
+	if err := filter.loadSidecar(); err != nil {
+		t.Fatalf("Failed to load sidecar: %v", err)
+	}
+	if !filter.IsReady() {
+		t.Fatal("Verified sidecar is not ready")
+	}
+
+	// The tampered config is rejected
+	filter = newFilter([]byte(`{"version":2}`), "1.0.0")
+	if err := filter.loadSidecar(); err == nil {
+		t.Fatal("Expected error for tampered sidecar config")
+	}
+	if filter.IsReady() {
+		t.Fatal("Tampered sidecar is ready")
+	}
+
+	// The version must match the metadata
+	filter = newFilter(config, "2.0.0")
+	if err := filter.loadSidecar(); err == nil {
+		t.Fatal("Expected error for sidecar version mismatch")
+	}
+	if filter.IsReady() {
+		t.Fatal("Mismatched sidecar is ready")
+	}
+
+	// The config signed by another key is rejected
+	other, err := sign.NewEphemeralKeypair(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := other.GetPublicKeyPem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKeyHex := hex.EncodeToString([]byte(otherKey))
+	filter = newFilter(config, "1.0.0")
+	filter.metadata.Load().BundlePublicKeyHex = &otherKeyHex
+	if err := filter.loadSidecar(); err == nil {
+		t.Fatal("Expected error for sidecar config signed by another key")
+	}
+}
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// The source name of the default CDN, which is used if no source is configured.
+const TxfilterSourceCDN = "cdn"
+
+// Returns the sources of the plugin and metadata files in order of priority.
+// A source is either a local directory, an HTTP base URL or the default CDN.
+func (s *SuspiciousTxfilter) sources() []string {
+	if s.txfilterConfig != nil && len(s.txfilterConfig.Sources) > 0 {
+		return s.txfilterConfig.Sources
+	}
+	return []string{TxfilterSourceCDN}
+}
+
+// open opens the file served by the source.
+func (s *SuspiciousTxfilter) open(source, filename string) (io.ReadCloser, error) {
+	switch {
+	case source == TxfilterSourceCDN:
+		return s.fetch(s.buildPluginURL(filename))
+	case strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://"):
+		return s.fetch(strings.TrimSuffix(source, "/") + "/" + filename)
+	default:
+		return os.Open(filepath.Join(strings.TrimPrefix(source, "file://"), filename))
+	}
+}
+
+// openAny opens the file from the first source serving it.
+func (s *SuspiciousTxfilter) openAny(filename string) (io.ReadCloser, string, error) {
+	var errs []error
+	for _, source := range s.sources() {
+		body, err := s.open(source, filename)
+		if err == nil {
+			return body, source, nil
+		}
+		log.Warn("Failed to fetch from suspicious txfilter source", "source", source, "file", filename, "err", err)
+		errs = append(errs, err)
+	}
+	return nil, "", errors.Join(errs...)
+}
+
+// fetchMetadataData returns the raw metadata from the first source serving
+// the metadata matching the pinned hash, if any.
+func (s *SuspiciousTxfilter) fetchMetadataData() ([]byte, error) {
+	var (
+		filename = s.metadataFileName()
+		errs     []error
+	)
+	for _, source := range s.sources() {
+		data, err := func() ([]byte, error) {
+			body, err := s.open(source, filename)
+			if err != nil {
+				return nil, err
+			}
+			defer body.Close()
+
+			data, err := io.ReadAll(body)
+			if err != nil {
+				return nil, err
+			}
+			return data, s.verifyMetadataHash(data)
+		}()
+		if err == nil {
+			log.Info("Fetched suspicious txfilter metadata", "source", source, "file", filename)
+			return data, nil
+		}
+		log.Warn("Failed to fetch from suspicious txfilter source", "source", source, "file", filename, "err", err)
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
+
+// verifyMetadataHash checks the metadata against the pinned sha256 hash. As the
+// metadata contains the sigstore bundle, pinning it also pins the plugin.
+func (s *SuspiciousTxfilter) verifyMetadataHash(data []byte) error {
+	if s.txfilterConfig == nil || s.txfilterConfig.MetadataHash == "" {
+		return nil
+	}
+	want := strings.TrimPrefix(strings.ToLower(s.txfilterConfig.MetadataHash), "0x")
+	hash := sha256.Sum256(data)
+	if got := hex.EncodeToString(hash[:]); got != want {
+		return fmt.Errorf("metadata hash mismatch: %s != %s", got, want)
+	}
+	return nil
+}
+
+func (s *SuspiciousTxfilter) metadataFileName() string {
+	if s.txfilterConfig.backend() == TxfilterBackendSidecar {
+		return SidecarMetadataFileName
+	}
+	return PluginMetadataFileName
+}
+
+// The last known good metadata is stored in the datadir, beside the plugin.
+func (s *SuspiciousTxfilter) cachedMetadataPath() string {
+	return filepath.Join(s.datadir, s.metadataFileName())
+}
+
+// loadCachedMetadata restores the metadata with which the plugin or the sidecar
+// was verified last time, so that the node can start without any source.
+func (s *SuspiciousTxfilter) loadCachedMetadata() error {
+	data, err := os.ReadFile(s.cachedMetadataPath())
+	if err != nil {
+		return fmt.Errorf("failed to read cached metadata: %w", err)
+	}
+	if err = s.verifyMetadataHash(data); err != nil {
+		return err
+	}
+	_, err = s.storeMetadata(data)
+	return err
+}
+
+// saveCachedMetadata stores the current metadata as the last known good one.
+func (s *SuspiciousTxfilter) saveCachedMetadata() {
+	data := s.metadataData.Load()
+	if data == nil {
+		return
+	}
+	path := s.cachedMetadataPath()
+	if err := writeFileAtomic(path, *data); err != nil {
+		log.Warn("Failed to cache suspicious txfilter metadata", "path", path, "err", err)
+	}
+}
+
+// writeFileAtomic replaces the file, so that a crash never leaves a partial file behind.
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+func (s *SuspiciousTxfilter) fetch(url string) (io.ReadCloser, error) {
+	resp, err := s.client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: url: %s, err: %w", url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("download error: status %d, url: %s", resp.StatusCode, url)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSuspiciousTxfilter_Sources(t *testing.T) {
+	var (
+		metadata = []byte(`{"version":"1.2.3","bundle_hex":"","is_keyless":false,"bundle_public_key_hex":"00","disable":false}`)
+		hash     = sha256.Sum256(metadata)
+		missing  = filepath.Join(t.TempDir(), "missing")
+		mirror   = t.TempDir()
+	)
+	if err := os.WriteFile(filepath.Join(mirror, PluginMetadataFileName), metadata, 0644); err != nil {
+		t.Fatal(err)
+	}
+	broken := httptest.NewServer(http.NotFoundHandler())
+	defer broken.Close()
+
+	filter := &SuspiciousTxfilter{
+		datadir:        t.TempDir(),
+		config:         testConfig,
+		txfilterConfig: &SuspiciousTxfilterConfig{Sources: []string{missing, broken.URL, "file://" + mirror}},
+		client:         http.DefaultClient,
+	}
+
+	// The unavailable sources are skipped
+	version, err := filter.fetchPluginMetadata()
+	if err != nil || version != "1.2.3" {
+		t.Fatalf("Unexpected result: version=%s, err=%v", version, err)
+	}
+
+	// The metadata not matching the pin is rejected
+	filter.txfilterConfig.MetadataHash = hex.EncodeToString(make([]byte, 32))
+	if _, err := filter.fetchPluginMetadata(); err == nil {
+		t.Fatal("Expected error for the metadata hash mismatch")
+	}
+	filter.txfilterConfig.MetadataHash = "0x" + hex.EncodeToString(hash[:])
+	if _, err := filter.fetchPluginMetadata(); err != nil {
+		t.Fatalf("Failed to fetch the pinned metadata: %v", err)
+	}
+
+	// The verified metadata is cached, and used if no source is available
+	filter.saveCachedMetadata()
+	offline := &SuspiciousTxfilter{
+		datadir:        filter.datadir,
+		config:         testConfig,
+		txfilterConfig: &SuspiciousTxfilterConfig{Sources: []string{missing}, MetadataHash: filter.txfilterConfig.MetadataHash},
+	}
+	if _, err := offline.fetchPluginMetadata(); err == nil {
+		t.Fatal("Expected error without any available source")
+	}
+	if err := offline.loadCachedMetadata(); err != nil {
+		t.Fatalf("Failed to load cached metadata: %v", err)
+	}
+	if version := offline.metadata.Load().Version; version != "1.2.3" {
+		t.Errorf("Unexpected cached version: %s", version)
+	}
+
+	// The cached metadata is checked against the pin as well
+	offline.metadata.Store(nil)
+	offline.txfilterConfig.MetadataHash = hex.EncodeToString(make([]byte, 32))
+	if err := offline.loadCachedMetadata(); err == nil {
+		t.Error("Expected error for the cached metadata hash mismatch")
+	}
+}
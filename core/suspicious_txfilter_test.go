@@ -125,7 +125,7 @@ func TestNewSuspiciousTxfilter(t *testing.T) {
 	defer cleanup()
 
 	// Now create the SuspiciousTxfilter
-	filter, err := NewSuspiciousTxfilter(testConfig, tmpDir, exitCh)
+	filter, err := NewSuspiciousTxfilter(testConfig, nil, tmpDir, exitCh)
 	if err != nil {
 		t.Fatalf("Failed to create SuspiciousTxfilter: %v", err)
 	}
@@ -225,7 +225,7 @@ func TestSuspiciousTxfilter_FilterTransaction(t *testing.T) {
 	defer cleanup()
 
 	// Now create the SuspiciousTxfilter
-	filter, err := NewSuspiciousTxfilter(testConfig, tmpDir, exitCh)
+	filter, err := NewSuspiciousTxfilter(testConfig, nil, tmpDir, exitCh)
 	if err != nil {
 		t.Fatalf("Failed to create SuspiciousTxfilter: %v", err)
 	}
@@ -278,7 +278,7 @@ func TestSuspiciousTxfilter_FilterTransaction_Blocked(t *testing.T) {
 	defer cleanup()
 
 	// Now create the SuspiciousTxfilter
-	filter, err := NewSuspiciousTxfilter(testConfig, tmpDir, exitCh)
+	filter, err := NewSuspiciousTxfilter(testConfig, nil, tmpDir, exitCh)
 	if err != nil {
 		t.Fatalf("Failed to create SuspiciousTxfilter: %v", err)
 	}
@@ -0,0 +1,104 @@
+package txpool
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Lane is a priority lane of the transactions sent from the listed senders or
+// to the listed contracts, such as the ones of the bridge relayers. The lane has
+// a share of the block gas and the pool slots reserved apart from the others.
+type Lane struct {
+	Name      string           // Name of the lane used in the metrics
+	Senders   []common.Address `toml:",omitempty"` // Senders whose transactions are in the lane
+	Contracts []common.Address `toml:",omitempty"` // Destinations whose transactions are in the lane
+	GasShare  uint64           // Percentage of the block gas reserved for the lane
+	Slots     uint64           // Number of the pool slots reserved for the lane beyond the global limits
+}
+
+// LaneSet classifies the transactions into the priority lanes. A transaction is
+// in the first lane listing its sender, or otherwise its destination. The nil
+// set has no lanes.
+type LaneSet struct {
+	lanes     []Lane
+	senders   map[common.Address]int
+	contracts map[common.Address]int
+}
+
+// NewLaneSet creates the set of the lanes in order of priority, or returns nil
+// if no lanes are given. The invalid lanes are fixed up with a warning.
+func NewLaneSet(lanes []Lane) *LaneSet {
+	if len(lanes) == 0 {
+		return nil
+	}
+	set := &LaneSet{
+		lanes:     make([]Lane, len(lanes)),
+		senders:   make(map[common.Address]int),
+		contracts: make(map[common.Address]int),
+	}
+	var share uint64
+	for i, lane := range lanes {
+		if lane.Name == "" {
+			lane.Name = fmt.Sprintf("lane%d", i)
+		}
+		if share+lane.GasShare > 100 {
+			log.Warn("Sanitizing excessive gas share of priority lane", "lane", lane.Name, "provided", lane.GasShare, "updated", 100-share)
+			lane.GasShare = 100 - share
+		}
+		share += lane.GasShare
+
+		for _, addr := range lane.Senders {
+			if _, ok := set.senders[addr]; !ok {
+				set.senders[addr] = i
+			}
+		}
+		for _, addr := range lane.Contracts {
+			if _, ok := set.contracts[addr]; !ok {
+				set.contracts[addr] = i
+			}
+		}
+		set.lanes[i] = lane
+	}
+	return set
+}
+
+// Len returns the number of the lanes.
+func (s *LaneSet) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.lanes)
+}
+
+// Lane returns the lane at the index.
+func (s *LaneSet) Lane(index int) *Lane {
+	return &s.lanes[index]
+}
+
+// SenderLane returns the index of the lane listing the sender, or -1 if none.
+func (s *LaneSet) SenderLane(from common.Address) int {
+	if s == nil {
+		return -1
+	}
+	if index, ok := s.senders[from]; ok {
+		return index
+	}
+	return -1
+}
+
+// Match returns the index of the lane of the transaction from the sender to the
+// destination, or -1 if the transaction is in no lane.
+func (s *LaneSet) Match(from common.Address, to *common.Address) int {
+	if index := s.SenderLane(from); index >= 0 {
+		return index
+	}
+	if s == nil || to == nil {
+		return -1
+	}
+	if index, ok := s.contracts[*to]; ok {
+		return index
+	}
+	return -1
+}
@@ -0,0 +1,46 @@
+package txpool
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestLaneSet(t *testing.T) {
+	var (
+		relayer = common.Address{0x01}
+		bridge  = common.Address{0x02}
+		other   = common.Address{0x03}
+	)
+	set := NewLaneSet([]Lane{
+		{Senders: []common.Address{relayer}, GasShare: 60},
+		{Name: "bridge", Senders: []common.Address{relayer}, Contracts: []common.Address{bridge}, GasShare: 60},
+	})
+	if name := set.Lane(0).Name; name != "lane0" {
+		t.Errorf("default name mismatch: have %s, want lane0", name)
+	}
+	if share := set.Lane(1).GasShare; share != 40 {
+		t.Errorf("gas share not sanitized: have %d, want 40", share)
+	}
+	for i, tt := range []struct {
+		from common.Address
+		to   *common.Address
+		lane int
+	}{
+		{relayer, &bridge, 0}, // the sender is matched first, in the first lane listing it
+		{other, &bridge, 1},
+		{other, &other, -1},
+		{other, nil, -1},
+	} {
+		if lane := set.Match(tt.from, tt.to); lane != tt.lane {
+			t.Errorf("test %d: lane mismatch: have %d, want %d", i, lane, tt.lane)
+		}
+	}
+	var empty *LaneSet
+	if empty.Len() != 0 || empty.Match(relayer, &bridge) != -1 {
+		t.Error("empty lane set matches")
+	}
+	if NewLaneSet(nil) != nil {
+		t.Error("lane set created without lanes")
+	}
+}
@@ -0,0 +1,143 @@
+package legacypool
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	// laneSlotsGaugeName is the name pattern of the slots used by a lane.
+	laneSlotsGaugeName = "txpool/lane/%s/slots"
+
+	// laneReservedMeterName is the name pattern of the transactions of a lane
+	// admitted into the reserved slots.
+	laneReservedMeterName = "txpool/lane/%s/reserved"
+)
+
+// laneTracker accounts the pool slots used by the transactions of each priority
+// lane. The slots up to the quota of the lane are reserved: they are neither
+// counted towards the global limits nor evicted for the better priced ones.
+//
+// The tracker is not thread safe, it's guarded by the lock of the lookup.
+type laneTracker struct {
+	lanes  *txpool.LaneSet
+	signer types.Signer
+
+	slots    []int
+	gauges   []*metrics.Gauge
+	reserved []*metrics.Meter
+}
+
+// newLaneTracker creates the tracker of the lanes, or returns nil if no lanes.
+func newLaneTracker(lanes *txpool.LaneSet, signer types.Signer) *laneTracker {
+	if lanes.Len() == 0 {
+		return nil
+	}
+	t := &laneTracker{
+		lanes:    lanes,
+		signer:   signer,
+		slots:    make([]int, lanes.Len()),
+		gauges:   make([]*metrics.Gauge, lanes.Len()),
+		reserved: make([]*metrics.Meter, lanes.Len()),
+	}
+	for i := range t.slots {
+		name := lanes.Lane(i).Name
+		t.gauges[i] = metrics.GetOrRegisterGauge(fmt.Sprintf(laneSlotsGaugeName, name), nil)
+		t.reserved[i] = metrics.GetOrRegisterMeter(fmt.Sprintf(laneReservedMeterName, name), nil)
+	}
+	return t
+}
+
+// lane returns the index of the lane of the transaction, or -1 if none.
+func (t *laneTracker) lane(tx *types.Transaction) int {
+	if t == nil {
+		return -1
+	}
+	from, _ := types.Sender(t.signer, tx) // already validated
+	return t.lanes.Match(from, tx.To())
+}
+
+// quota returns the number of the slots reserved for the lane.
+func (t *laneTracker) quota(lane int) int {
+	return int(t.lanes.Lane(lane).Slots)
+}
+
+// update adds the slots used by the transaction, or subtracts if negative.
+func (t *laneTracker) update(tx *types.Transaction, slots int) {
+	if lane := t.lane(tx); lane >= 0 {
+		t.slots[lane] += slots
+		t.gauges[lane].Update(int64(t.slots[lane]))
+		if slots > 0 && t.slots[lane] <= t.quota(lane) {
+			t.reserved[lane].Mark(1)
+		}
+	}
+}
+
+// inReserve returns the number of the slots used within the quotas.
+func (t *laneTracker) inReserve() int {
+	if t == nil {
+		return 0
+	}
+	var total int
+	for lane, slots := range t.slots {
+		total += min(slots, t.quota(lane))
+	}
+	return total
+}
+
+// clear resets the slots used by the lanes.
+func (t *laneTracker) clear() {
+	if t == nil {
+		return
+	}
+	for lane := range t.slots {
+		t.slots[lane] = 0
+		t.gauges[lane].Update(0)
+	}
+}
+
+// SharedSlots returns the number of the slots used out of the reserved ones of
+// the priority lanes, which are limited by the global limits.
+func (t *lookup) SharedSlots() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.slots - t.lanes.inReserve()
+}
+
+// FitsReserve reports whether the transaction fits in the reserved slots of its
+// lane.
+func (t *lookup) FitsReserve(tx *types.Transaction) bool {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	lane := t.lanes.lane(tx)
+	return lane >= 0 && t.lanes.slots[lane]+numSlots(tx) <= t.lanes.quota(lane)
+}
+
+// Reserved reports whether the transaction is in a lane not exceeding the
+// quota, which is protected from the eviction.
+func (t *lookup) Reserved(tx *types.Transaction) bool {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	lane := t.lanes.lane(tx)
+	return lane >= 0 && t.lanes.slots[lane] <= t.lanes.quota(lane)
+}
+
+// ReservedSender reports whether the account is a sender of a lane not
+// exceeding the quota, which is exempt from the fairness truncation.
+func (t *lookup) ReservedSender(addr common.Address) bool {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.lanes == nil {
+		return false
+	}
+	lane := t.lanes.lanes.SenderLane(addr)
+	return lane >= 0 && t.lanes.slots[lane] <= t.lanes.quota(lane)
+}
@@ -0,0 +1,79 @@
+package legacypool
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the transactions of a priority lane are admitted into the reserved
+// slots of the full pool, and are not evicted for the better priced ones.
+func TestLaneReservedSlots(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	blockchain := newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+
+	keys := make([]*ecdsa.PrivateKey, 4)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	relayer := crypto.PubkeyToAddress(keys[0].PublicKey)
+
+	config := testTxPoolConfig
+	config.GlobalSlots = 2
+	config.GlobalQueue = 2
+	config.OverflowPoolSlots = 0
+	config.Lanes = []txpool.Lane{{Name: "relayer", Senders: []common.Address{relayer}, Slots: 2}}
+
+	pool := New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), newReserver())
+	defer pool.Close()
+
+	for _, key := range keys {
+		testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(10000000))
+	}
+	// Fill up the shared slots with the transactions out of the lane
+	for i := uint64(0); i < 2; i++ {
+		for _, key := range keys[1:3] {
+			if err := pool.addRemoteSync(pricedTransaction(i, 100000, big.NewInt(2), key)); err != nil {
+				t.Fatalf("failed to add transaction: %v", err)
+			}
+		}
+	}
+	if err := pool.addRemoteSync(pricedTransaction(0, 100000, big.NewInt(1), keys[3])); !errors.Is(err, txpool.ErrUnderpriced) {
+		t.Fatalf("adding underpriced transaction error mismatch: have %v, want %v", err, txpool.ErrUnderpriced)
+	}
+	// The cheapest transactions of the lane are admitted into the reserved slots
+	for i := uint64(0); i < 2; i++ {
+		if err := pool.addRemoteSync(pricedTransaction(i, 100000, big.NewInt(1), keys[0])); err != nil {
+			t.Fatalf("failed to add lane transaction %d: %v", i, err)
+		}
+	}
+	if slots := pool.all.SharedSlots(); slots != 4 {
+		t.Fatalf("shared slots mismatch: have %d, want %d", slots, 4)
+	}
+	// The lane is full, so the next one competes for the shared slots
+	if err := pool.addRemoteSync(pricedTransaction(2, 100000, big.NewInt(1), keys[0])); !errors.Is(err, txpool.ErrUnderpriced) {
+		t.Fatalf("adding lane transaction beyond the quota error mismatch: have %v, want %v", err, txpool.ErrUnderpriced)
+	}
+	// The better priced transaction evicts the ones out of the lane
+	if err := pool.addRemoteSync(pricedTransaction(0, 100000, big.NewInt(3), keys[3])); err != nil {
+		t.Fatalf("failed to add well priced transaction: %v", err)
+	}
+	if pending, _ := pool.ContentFrom(relayer); len(pending) != 2 {
+		t.Fatalf("lane transactions evicted: have %d, want %d", len(pending), 2)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
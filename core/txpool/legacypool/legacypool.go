@@ -158,6 +158,8 @@ type Config struct {
 
 	Lifetime       time.Duration // Maximum amount of time non-executable transaction are queued
 	ReannounceTime time.Duration // Duration for announcing local pending transactions again
+
+	Lanes []txpool.Lane `toml:",omitempty"` // Priority lanes in order, with the slots reserved beyond the global limits
 }
 
 // DefaultConfig contains the default configurations for the transaction pool.
@@ -305,6 +307,7 @@ func New(config Config, chain BlockChain) *LegacyPool {
 		initDoneCh:      make(chan struct{}),
 		localBufferPool: NewTxOverflowPoolHeap(config.OverflowPoolSlots),
 	}
+	pool.all.lanes = newLaneTracker(txpool.NewLaneSet(config.Lanes), pool.signer)
 	pool.priced = newPricedList(pool.all)
 
 	return pool
@@ -792,8 +795,9 @@ func (pool *LegacyPool) add(tx *types.Transaction) (replaced bool, err error) {
 			}
 		}()
 	}
-	// If the transaction pool is full, discard underpriced transactions
-	if uint64(pool.all.Slots()+numSlots(tx)) > pool.config.GlobalSlots+pool.config.GlobalQueue {
+	// If the transaction pool is full, discard underpriced transactions. The ones
+	// fitting in the reserved slots of their priority lane are exempt.
+	if !pool.all.FitsReserve(tx) && uint64(pool.all.SharedSlots()+numSlots(tx)) > pool.config.GlobalSlots+pool.config.GlobalQueue {
 		// If the new transaction is underpriced, don't accept it
 		if pool.priced.Underpriced(tx) {
 			log.Trace("Discarding underpriced transaction", "hash", hash, "gasTipCap", tx.GasTipCap(), "gasFeeCap", tx.GasFeeCap())
@@ -812,7 +816,7 @@ func (pool *LegacyPool) add(tx *types.Transaction) (replaced bool, err error) {
 
 		// New transaction is better than our worse ones, make room for it.
 		// If we can't make enough room for new one, abort the operation.
-		drop, success := pool.priced.Discard(pool.all.Slots() - int(pool.config.GlobalSlots+pool.config.GlobalQueue) + numSlots(tx))
+		drop, success := pool.priced.Discard(pool.all.SharedSlots() - int(pool.config.GlobalSlots+pool.config.GlobalQueue) + numSlots(tx))
 
 		// Special case, we still can't make the room for the new remote one.
 		if !success {
@@ -1588,6 +1592,10 @@ func (pool *LegacyPool) truncatePending() {
 	// Assemble a spam order to penalize large transactors first
 	spammers := prque.New[uint64, common.Address](nil)
 	for addr, list := range pool.pending {
+		// Leave the senders of the priority lanes within the reserved slots
+		if pool.all.ReservedSender(addr) {
+			continue
+		}
 		// Only evict transactions from high rollers
 		length := uint64(list.Len())
 		pending += length
@@ -1848,6 +1856,7 @@ type lookup struct {
 	txs   map[common.Hash]*types.Transaction
 
 	auths map[common.Address][]common.Hash // All accounts with a pooled authorization
+	lanes *laneTracker                     // Slots used by the priority lanes, nil if none
 }
 
 // newLookup returns a new lookup structure.
@@ -1903,6 +1912,7 @@ func (t *lookup) Add(tx *types.Transaction) {
 
 	t.slots += numSlots(tx)
 	slotsGauge.Update(int64(t.slots))
+	t.lanes.update(tx, numSlots(tx))
 
 	t.txs[tx.Hash()] = tx
 	t.addAuthorities(tx)
@@ -1921,6 +1931,7 @@ func (t *lookup) Remove(hash common.Hash) {
 	t.removeAuthorities(tx)
 	t.slots -= numSlots(tx)
 	slotsGauge.Update(int64(t.slots))
+	t.lanes.update(tx, -numSlots(tx))
 
 	delete(t.txs, hash)
 }
@@ -1933,6 +1944,7 @@ func (t *lookup) Clear() {
 	t.slots = 0
 	t.txs = make(map[common.Hash]*types.Transaction)
 	t.auths = make(map[common.Address][]common.Hash)
+	t.lanes.clear()
 }
 
 // TxsBelowTip finds all remote transactions below the given tip threshold.
@@ -2007,8 +2019,9 @@ func (pool *LegacyPool) transferTransactions() {
 	}
 
 	maxMainPoolSize := int(pool.config.GlobalSlots + pool.config.GlobalQueue)
-	// Use pool.all.Slots() to get the total slots used by all transactions
-	currentMainPoolSize := pool.all.Slots()
+	// Use pool.all.SharedSlots() to get the slots used by all transactions out
+	// of the reserved ones of the priority lanes
+	currentMainPoolSize := pool.all.SharedSlots()
 	if currentMainPoolSize >= maxMainPoolSize {
 		return
 	}
@@ -636,7 +636,15 @@ func (l *pricedList) underpricedFor(h *priceHeap, tx *types.Transaction) bool {
 // priced list and returns them for further removal from the entire pool.
 // If noPending is set to true, we will only consider the floating list
 func (l *pricedList) Discard(slots int) (types.Transactions, bool) {
-	drop := make(types.Transactions, 0, slots) // Remote underpriced transactions to drop
+	var (
+		drop = make(types.Transactions, 0, slots) // Remote underpriced transactions to drop
+		kept types.Transactions                   // Transactions in the reserved slots of the priority lanes
+	)
+	defer func() {
+		for _, tx := range kept {
+			heap.Push(&l.floating, tx)
+		}
+	}()
 	for slots > 0 {
 		if len(l.urgent.list)*floatingRatio > len(l.floating.list)*urgentRatio {
 			// Discard stale transactions if found during cleanup
@@ -658,6 +666,11 @@ func (l *pricedList) Discard(slots int) (types.Transactions, bool) {
 				l.stales.Add(-1)
 				continue
 			}
+			// Keep the transaction in the reserved slots aside
+			if l.all.Reserved(tx) {
+				kept = append(kept, tx)
+				continue
+			}
 			// Non stale transaction found, discard it
 			drop = append(drop, tx)
 			slots -= numSlots(tx)
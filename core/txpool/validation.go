@@ -158,6 +158,21 @@ func ValidateTransaction(tx *types.Transaction, head *types.Header, signer types
 	return nil
 }
 
+// Names of the Oasys-only transaction checks, in the order they are enforced.
+const (
+	OasysCheckCreateAllowList    = "createAllowList"
+	OasysCheckBlockedSender      = "blockedSender"
+	OasysCheckCallDenyList       = "callDenyList"
+	OasysCheckBlockedDestination = "blockedDestination"
+	OasysCheckBlockAll           = "blockAll"
+)
+
+// OasysCheck is the outcome of an Oasys-only transaction check, Err is nil if it passed.
+type OasysCheck struct {
+	Name string
+	Err  error
+}
+
 // ValidateTransactionWithOasysState performs Oasys-only transaction checks that
 // require state access.
 func ValidateTransactionWithOasysState(tx *types.Transaction, signer types.Signer, config *params.ChainConfig, state *state.StateDB) error {
@@ -172,43 +187,67 @@ func ValidateTransactionWithOasysState(tx *types.Transaction, signer types.Signe
 	if err != nil {
 		return fmt.Errorf("%w: %v", ErrInvalidSender, err)
 	}
+	for _, check := range CheckTransactionWithOasysState(tx, sender, state) {
+		if check.Err != nil {
+			return check.Err
+		}
+	}
+	return nil
+}
+
+// CheckTransactionWithOasysState runs every Oasys-only transaction check against
+// the state without stopping at the first failure, so that the callers can
+// report why the transaction is rejected.
+func CheckTransactionWithOasysState(tx *types.Transaction, sender common.Address, state vm.StateDB) []OasysCheck {
+	checks := make([]OasysCheck, 0, 5)
+
 	// Make sure the sender is allowed to create contract.
 	// Create2 built-in deployment proxy is only allowed to call by the allowed addresses.
+	check := OasysCheck{Name: OasysCheckCreateAllowList}
 	if !vm.IsAllowedToCreate(state, sender) {
 		if tx.To() == nil {
-			return fmt.Errorf("%w: the sender is not allowed to create contract. Please contact the Oasys team. sender: %s", vm.ErrUnauthorizedCreate, sender.Hex())
-		}
-		if tx.To().Cmp(oasys.DeterministicDeploymentProxy) == 0 && state.GetCodeSize(oasys.DeterministicDeploymentProxy) > 0 {
-			return fmt.Errorf("%w: the sender is not allowed to create contract. Please contact the Oasys team. sender: %s", vm.ErrUnauthorizedCreate, sender.Hex())
+			check.Err = fmt.Errorf("%w: the sender is not allowed to create contract. Please contact the Oasys team. sender: %s", vm.ErrUnauthorizedCreate, sender.Hex())
+		} else if tx.To().Cmp(oasys.DeterministicDeploymentProxy) == 0 && state.GetCodeSize(oasys.DeterministicDeploymentProxy) > 0 {
+			check.Err = fmt.Errorf("%w: the sender is not allowed to create contract. Please contact the Oasys team. sender: %s", vm.ErrUnauthorizedCreate, sender.Hex())
 		}
 	}
+	checks = append(checks, check)
+
 	// Make sure the sender is not blocked.
+	check = OasysCheck{Name: OasysCheckBlockedSender}
 	if vm.IsBlockedAddress(state, sender) {
-		return fmt.Errorf("%w: the sender is blocked. sender: %s", vm.ErrAddressBlocked, sender.Hex())
+		check.Err = fmt.Errorf("%w: the sender is blocked. sender: %s", vm.ErrAddressBlocked, sender.Hex())
 	}
+	checks = append(checks, check)
+
 	// Make sure the destination is not in denylist.
+	check = OasysCheck{Name: OasysCheckCallDenyList}
 	if tx.To() != nil && vm.IsDeniedToCall(state, *tx.To()) {
-		return fmt.Errorf("%w: the destination is in denylist. destination: %s", vm.ErrUnauthorizedCall, tx.To().Hex())
+		check.Err = fmt.Errorf("%w: the destination is in denylist. destination: %s", vm.ErrUnauthorizedCall, tx.To().Hex())
 	}
+	checks = append(checks, check)
+
 	// Make sure the destination is not blocked.
+	check = OasysCheck{Name: OasysCheckBlockedDestination}
 	if tx.To() != nil && vm.IsBlockedAddress(state, *tx.To()) {
-		return fmt.Errorf("%w: the destination is blocked. destination: %s", vm.ErrAddressBlocked, tx.To().Hex())
+		check.Err = fmt.Errorf("%w: the destination is blocked. destination: %s", vm.ErrAddressBlocked, tx.To().Hex())
 	}
+	checks = append(checks, check)
+
 	// Ensure the transaction is not blocked.
+	check = OasysCheck{Name: OasysCheckBlockAll}
 	if vm.IsBlockedAll(state) {
 		if tx.Type() == types.BlobTxType {
 			// Blob txs are only allowed if value is zero and tx data is empty.
 			if tx.Value().Sign() != 0 || len(tx.Data()) > 0 {
-				return fmt.Errorf("%w: only transactions with zero value and empty tx data are allowed for blob-type transactions. value: %v, data: %v", vm.ErrAllTransactionBlocked, tx.Value(), tx.Data())
+				check.Err = fmt.Errorf("%w: only transactions with zero value and empty tx data are allowed for blob-type transactions. value: %v, data: %v", vm.ErrAllTransactionBlocked, tx.Value(), tx.Data())
 			}
-			return nil
-		}
-		// Bypass the blocked check for the transaction to the transaction blocker contract.
-		if tx.To() == nil || tx.To().Cmp(vm.TransactionBlockerContract) != 0 {
-			return vm.ErrAllTransactionBlocked
+		} else if tx.To() == nil || tx.To().Cmp(vm.TransactionBlockerContract) != 0 {
+			// Bypass the blocked check for the transaction to the transaction blocker contract.
+			check.Err = vm.ErrAllTransactionBlocked
 		}
 	}
-	return nil
+	return append(checks, check)
 }
 
 // validateBlobTx implements the blob-transaction specific validations.
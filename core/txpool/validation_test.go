@@ -0,0 +1,47 @@
+package txpool
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestCheckTransactionWithOasysState(t *testing.T) {
+	var (
+		sender = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		to     = common.HexToAddress("0x2000000000000000000000000000000000000001")
+	)
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	tx := types.NewTx(&types.LegacyTx{To: &to, Value: big.NewInt(1), Gas: params.TxGas})
+
+	checks := CheckTransactionWithOasysState(tx, sender, statedb)
+	wantNames := []string{OasysCheckCreateAllowList, OasysCheckBlockedSender, OasysCheckCallDenyList, OasysCheckBlockedDestination, OasysCheckBlockAll}
+	if len(checks) != len(wantNames) {
+		t.Fatalf("got %d checks, want %d", len(checks), len(wantNames))
+	}
+	for i, check := range checks {
+		if check.Name != wantNames[i] || check.Err != nil {
+			t.Errorf("checks[%d] = {%s, %v}, want {%s, nil}", i, check.Name, check.Err, wantNames[i])
+		}
+	}
+
+	// Every failing check is reported, not only the first one
+	statedb.SetState(vm.TransactionBlockerContract, vm.BlockedAddressStorageKey(sender), common.BigToHash(common.Big1))
+	statedb.SetState(vm.TransactionBlockerContract, vm.BlockedAddressStorageKey(to), common.BigToHash(common.Big1))
+
+	checks = CheckTransactionWithOasysState(tx, sender, statedb)
+	for i, want := range []error{nil, vm.ErrAddressBlocked, nil, vm.ErrAddressBlocked, nil} {
+		if !errors.Is(checks[i].Err, want) || (want == nil) != (checks[i].Err == nil) {
+			t.Errorf("checks[%d] (%s) err = %v, want %v", i, checks[i].Name, checks[i].Err, want)
+		}
+	}
+	if !vm.IsBlockedAddress(statedb, sender) {
+		t.Error("sender is not blocked")
+	}
+}
@@ -25,8 +25,7 @@ var (
 
 // Call `isBlockedAddress` function in the `TransactionBlocker` contract by directly accessing the storage
 func IsBlockedAddress(state StateDB, address common.Address) bool {
-	hash := computeAddressMapStorageKey(address, isBlockedAddressSlot)
-	val := state.GetState(TransactionBlockerContract, hash)
+	val := state.GetState(TransactionBlockerContract, BlockedAddressStorageKey(address))
 	return val.Cmp(emptyHash) != 0
 }
 
@@ -36,3 +35,9 @@ func IsBlockedAll(state StateDB) bool {
 	val := state.GetState(TransactionBlockerContract, blockedAllKeyHash)
 	return val.Cmp(emptyHash) != 0
 }
+
+// BlockedAddressStorageKey returns the storage key of the address in the
+// `isBlockedAddress` mapping of the `TransactionBlocker` contract.
+func BlockedAddressStorageKey(address common.Address) common.Hash {
+	return computeAddressMapStorageKey(address, isBlockedAddressSlot)
+}
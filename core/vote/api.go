@@ -0,0 +1,81 @@
+package vote
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/oasys"
+)
+
+// VoteKeysActivation specifies the block to switch to the reloaded keys, which
+// is either the block number or the start of the next epoch.
+type VoteKeysActivation struct {
+	Block     *hexutil.Uint64 `json:"block"`
+	NextEpoch bool            `json:"nextEpoch"`
+}
+
+// VoteSignerAPI provides an API to manage the keys of the vote signer.
+type VoteSignerAPI struct {
+	manager *VoteManager
+}
+
+// NewVoteSignerAPI creates a new API for the signer of the vote manager.
+func NewVoteSignerAPI(manager *VoteManager) *VoteSignerAPI {
+	return &VoteSignerAPI{manager: manager}
+}
+
+// VoteKeys returns the active keys, and the pending keys with their activation block.
+func (api *VoteSignerAPI) VoteKeys() *VoteKeysStatus {
+	return api.manager.signer.Status()
+}
+
+// ReloadVoteKeys reloads the BLS wallet or the remote signer, and switches to
+// the keys selected by the account names or the public keys ("*" for all) at
+// the activation. The keys are switched immediately if no activation is given.
+// The votes are signed with the key registered to the validator set, so the
+// keys to be registered can be added before the registration takes effect.
+func (api *VoteSignerAPI) ReloadVoteKeys(selectors []string, activation *VoteKeysActivation) (*VoteKeysStatus, error) {
+	var number uint64
+	if activation != nil {
+		head := api.manager.chain.CurrentHeader()
+		switch {
+		case activation.Block != nil && activation.NextEpoch:
+			return nil, errors.New("both of the block and the next epoch are specified")
+		case activation.Block != nil:
+			number = uint64(*activation.Block)
+			if number <= head.Number.Uint64() {
+				return nil, fmt.Errorf("activation block %d is not after the head %d", number, head.Number.Uint64())
+			}
+		case activation.NextEpoch:
+			engine, ok := api.manager.engine.(*oasys.Oasys)
+			if !ok {
+				return nil, errors.New("epoch is not supported by the consensus engine")
+			}
+			env, err := engine.EnvironmentAt(api.manager.chain, head)
+			if err != nil {
+				return nil, err
+			}
+			number = env.NewValueStartBlock(env.Epoch(head.Number.Uint64()) + 1)
+		}
+	}
+	if err := api.manager.signer.Reload(selectors, number); err != nil {
+		return nil, err
+	}
+	return api.manager.signer.Status(), nil
+}
+
+// VotePoolAPI provides an API to inspect the vote pool.
+type VotePoolAPI struct {
+	pool *VotePool
+}
+
+// NewVotePoolAPI creates a new API for the vote pool.
+func NewVotePoolAPI(pool *VotePool) *VotePoolAPI {
+	return &VotePoolAPI{pool: pool}
+}
+
+// PoolStatus returns the sizes of the queues and the number of the votes per block.
+func (api *VotePoolAPI) PoolStatus() *PoolStatus {
+	return api.pool.Status()
+}
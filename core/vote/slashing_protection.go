@@ -0,0 +1,393 @@
+package vote
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/pebble"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// SlashingProtectionDatabase is the name of the slashing protection database
+	// in the instance directory. It's separated from the chain database so that
+	// it survives the resync of the chain.
+	SlashingProtectionDatabase = "slashingprotection"
+
+	// SlashingProtectionMarker is the name of the file in the instance directory
+	// recording the path of the slashing protection database once the votes are
+	// signed with it.
+	SlashingProtectionMarker = "slashingprotection.path"
+
+	// Number of the recent targets whose votes are kept for the surround vote
+	// checks. The older votes are pruned into the low watermarks.
+	slashingProtectionHistory = 4 * maliciousVoteSlashScope
+
+	// Version of the EIP-3076 interchange format.
+	InterchangeFormatVersion = "5"
+)
+
+var (
+	ErrDoubleVote         = errors.New("double vote: another vote has been signed for the same target")
+	ErrSurroundVote       = errors.New("surround vote: the vote surrounds or is surrounded by a signed vote")
+	ErrVoteBelowWatermark = errors.New("vote below the low watermark of the slashing protection")
+	ErrInvalidVoteSpan    = errors.New("vote source is not lower than the target")
+
+	// ErrSlashingProtectionMissing is returned if the slashing protection
+	// database used before is missing, as signing with an empty one might
+	// conflict with the votes signed before.
+	ErrSlashingProtectionMissing = errors.New("slashing protection database used before is missing")
+
+	errSlashingProtectionClosed = errors.New("slashing protection closed")
+
+	slashingProtectionRefusedCounter = metrics.NewRegisteredCounter("votesSigner/slashingProtection/refused", nil)
+)
+
+// protectedKey is the vote watermarks of a BLS key. Any vote whose source is
+// lower than the low source, or whose target is not higher than the low
+// target, is refused as its history might be lost.
+type protectedKey struct {
+	LowSource  uint64
+	LowTarget  uint64
+	HighSource uint64
+	HighTarget uint64
+}
+
+// protectedVote is the vote signed by a BLS key, stored by the target.
+type protectedVote struct {
+	Source      uint64
+	SigningRoot common.Hash // Zero if unknown, conflicting with any vote
+}
+
+// SlashingProtection records the votes signed by the BLS keys, refusing to sign
+// the votes violating the fast finality rules regardless of the vote journal.
+type SlashingProtection struct {
+	db     ethdb.KeyValueStore
+	closed bool
+	lock   sync.Mutex
+}
+
+// NewSlashingProtection creates a slashing protection backed by the given database.
+func NewSlashingProtection(db ethdb.KeyValueStore) *SlashingProtection {
+	return &SlashingProtection{db: db}
+}
+
+// OpenSlashingProtection opens the slashing protection database at the path,
+// creating it if not exists.
+func OpenSlashingProtection(path string, readonly bool) (*SlashingProtection, error) {
+	db, err := pebble.New(path, 16, 16, "eth/db/slashingprotection/", readonly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open slashing protection database: %w", err)
+	}
+	return NewSlashingProtection(db), nil
+}
+
+// OpenSignerSlashingProtection opens the slashing protection database at the
+// path for the vote signer, and records the path in the marker file. It refuses
+// to create the database if the marker shows that one was used before, so that
+// the votes are never signed without their history after the database is lost
+// or the path is misconfigured. The history is restored by importing it.
+func OpenSignerSlashingProtection(path, marker string) (*SlashingProtection, error) {
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		if used, err := os.ReadFile(marker); err == nil {
+			return nil, fmt.Errorf("%w: %s (used before: %s), import the votes with `geth bls slashing-protection import`, or remove %s to start with an empty database",
+				ErrSlashingProtectionMissing, path, strings.TrimSpace(string(used)), marker)
+		}
+	}
+	p, err := OpenSlashingProtection(path, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(marker, []byte(path+"\n"), 0600); err != nil {
+		p.Close()
+		return nil, fmt.Errorf("failed to write slashing protection marker: %w", err)
+	}
+	return p, nil
+}
+
+// Close closes the underlying database.
+func (p *SlashingProtection) Close() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+	return p.db.Close()
+}
+
+// available returns an error if the votes can't be recorded.
+func (p *SlashingProtection) available() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.closed {
+		return errSlashingProtectionClosed
+	}
+	return nil
+}
+
+// CheckAndRecord checks the vote against the votes signed by the key, and
+// records it before signing. Signing the same vote again is allowed.
+func (p *SlashingProtection) CheckAndRecord(pubKey [48]byte, data *types.VoteData) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.closed {
+		return errSlashingProtectionClosed
+	}
+
+	key := p.readKey(pubKey)
+	signingRoot := data.Hash()
+	signed, err := p.check(pubKey, key, data.SourceNumber, data.TargetNumber, signingRoot)
+	if err != nil {
+		slashingProtectionRefusedCounter.Inc(1)
+		return fmt.Errorf("%w (vote %d-->%d)", err, data.SourceNumber, data.TargetNumber)
+	}
+	if signed {
+		return nil
+	}
+	batch := p.db.NewBatch()
+	p.insert(batch, pubKey, key, data.SourceNumber, data.TargetNumber, signingRoot)
+	return batch.Write()
+}
+
+// Observe records the vote signed by the key elsewhere, such as the vote of the
+// primary validator synced to a backup one, without refusing it.
+func (p *SlashingProtection) Observe(pubKey [48]byte, data *types.VoteData) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.closed {
+		return errSlashingProtectionClosed
+	}
+
+	batch := p.db.NewBatch()
+	p.insert(batch, pubKey, p.readKey(pubKey), data.SourceNumber, data.TargetNumber, data.Hash())
+	return batch.Write()
+}
+
+// check reports whether the same vote has been signed, or the error if the vote
+// conflicts with the signed votes.
+func (p *SlashingProtection) check(pubKey [48]byte, key *protectedKey, source, target uint64, signingRoot common.Hash) (bool, error) {
+	if source >= target {
+		return false, ErrInvalidVoteSpan
+	}
+	if source < key.LowSource || target <= key.LowTarget {
+		return false, ErrVoteBelowWatermark
+	}
+	var (
+		signed bool
+		err    error
+	)
+	rawdb.IterateSlashingProtectionVotesRLP(p.db, pubKey[:], func(signedTarget uint64, data rlp.RawValue) bool {
+		var vote protectedVote
+		if rlp.DecodeBytes(data, &vote) != nil {
+			log.Error("Invalid slashing protection vote", "pubKey", hexutil.Encode(pubKey[:]), "target", signedTarget)
+			return true
+		}
+		switch {
+		case signedTarget == target:
+			if vote.SigningRoot != signingRoot || vote.SigningRoot == (common.Hash{}) {
+				err = ErrDoubleVote
+			} else {
+				signed = true
+			}
+		case signedTarget < target && vote.Source > source, // the vote surrounds the signed one
+			signedTarget > target && vote.Source < source: // the signed vote surrounds the vote
+			err = ErrSurroundVote
+		}
+		return err == nil
+	})
+	return signed && err == nil, err
+}
+
+// insert writes the vote into the batch, updating the watermarks of the key.
+// The signing root of the conflicting votes at the same target is cleared.
+func (p *SlashingProtection) insert(batch ethdb.Batch, pubKey [48]byte, key *protectedKey, source, target uint64, signingRoot common.Hash) {
+	if target <= key.LowTarget {
+		return // covered by the low watermark
+	}
+	if data := rawdb.ReadSlashingProtectionVoteRLP(p.db, pubKey[:], target); len(data) > 0 {
+		var vote protectedVote
+		if rlp.DecodeBytes(data, &vote) == nil && vote.Source == source && vote.SigningRoot == signingRoot {
+			return
+		}
+		signingRoot = common.Hash{}
+		source = min(source, vote.Source)
+	}
+	data, _ := rlp.EncodeToBytes(&protectedVote{Source: source, SigningRoot: signingRoot})
+	rawdb.WriteSlashingProtectionVoteRLP(batch, pubKey[:], target, data)
+
+	key.HighSource = max(key.HighSource, source)
+	key.HighTarget = max(key.HighTarget, target)
+	p.prune(batch, pubKey, key)
+	p.writeKey(batch, pubKey, key)
+}
+
+// prune removes the votes out of the history, raising the low watermarks.
+func (p *SlashingProtection) prune(batch ethdb.Batch, pubKey [48]byte, key *protectedKey) {
+	if key.HighTarget <= slashingProtectionHistory {
+		return
+	}
+	cutoff := key.HighTarget - slashingProtectionHistory
+	rawdb.IterateSlashingProtectionVotesRLP(p.db, pubKey[:], func(target uint64, data rlp.RawValue) bool {
+		if target > cutoff {
+			return false
+		}
+		var vote protectedVote
+		if rlp.DecodeBytes(data, &vote) == nil {
+			key.LowSource = max(key.LowSource, vote.Source)
+		}
+		key.LowTarget = max(key.LowTarget, target)
+		rawdb.DeleteSlashingProtectionVote(batch, pubKey[:], target)
+		return true
+	})
+}
+
+func (p *SlashingProtection) readKey(pubKey [48]byte) *protectedKey {
+	key := new(protectedKey)
+	if data := rawdb.ReadSlashingProtectionKeyRLP(p.db, pubKey[:]); len(data) > 0 {
+		if err := rlp.DecodeBytes(data, key); err != nil {
+			log.Error("Invalid slashing protection key", "pubKey", hexutil.Encode(pubKey[:]), "err", err)
+		}
+	}
+	return key
+}
+
+func (p *SlashingProtection) writeKey(batch ethdb.KeyValueWriter, pubKey [48]byte, key *protectedKey) {
+	data, _ := rlp.EncodeToBytes(key)
+	rawdb.WriteSlashingProtectionKeyRLP(batch, pubKey[:], data)
+}
+
+// Interchange is the EIP-3076 slashing protection interchange format. The
+// block numbers of the votes are put into the epochs of the attestations, and
+// the genesis hash of the chain into the genesis validators root.
+type Interchange struct {
+	Metadata InterchangeMetadata `json:"metadata"`
+	Data     []*InterchangeData  `json:"data"`
+}
+
+// InterchangeMetadata is the metadata of the interchange.
+type InterchangeMetadata struct {
+	InterchangeFormatVersion string      `json:"interchange_format_version"`
+	GenesisValidatorsRoot    common.Hash `json:"genesis_validators_root"`
+}
+
+// InterchangeData is the votes signed by a BLS key.
+type InterchangeData struct {
+	PubKey             hexutil.Bytes      `json:"pubkey"`
+	SignedBlocks       []struct{}         `json:"signed_blocks"` // Always empty, as the blocks are not signed by the BLS keys
+	SignedAttestations []*InterchangeVote `json:"signed_attestations"`
+}
+
+// InterchangeVote is a vote signed by a BLS key.
+type InterchangeVote struct {
+	SourceEpoch uint64       `json:"source_epoch,string"`
+	TargetEpoch uint64       `json:"target_epoch,string"`
+	SigningRoot *common.Hash `json:"signing_root,omitempty"`
+}
+
+// Export returns the votes signed by all the keys in the interchange format. The
+// low watermarks are exported as the votes without the signing root.
+func (p *SlashingProtection) Export(genesisHash common.Hash) *Interchange {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	interchange := &Interchange{
+		Metadata: InterchangeMetadata{
+			InterchangeFormatVersion: InterchangeFormatVersion,
+			GenesisValidatorsRoot:    genesisHash,
+		},
+		Data: []*InterchangeData{},
+	}
+	rawdb.IterateSlashingProtectionKeys(p.db, func(pubKey []byte, data rlp.RawValue) bool {
+		var key protectedKey
+		if err := rlp.DecodeBytes(data, &key); err != nil {
+			log.Error("Invalid slashing protection key", "pubKey", hexutil.Encode(pubKey), "err", err)
+			return true
+		}
+		entry := &InterchangeData{PubKey: pubKey, SignedBlocks: []struct{}{}, SignedAttestations: []*InterchangeVote{}}
+		if key.LowTarget > 0 {
+			entry.SignedAttestations = append(entry.SignedAttestations, &InterchangeVote{SourceEpoch: key.LowSource, TargetEpoch: key.LowTarget})
+		}
+		rawdb.IterateSlashingProtectionVotesRLP(p.db, pubKey, func(target uint64, data rlp.RawValue) bool {
+			var vote protectedVote
+			if rlp.DecodeBytes(data, &vote) != nil {
+				return true
+			}
+			exported := &InterchangeVote{SourceEpoch: vote.Source, TargetEpoch: target}
+			if vote.SigningRoot != (common.Hash{}) {
+				exported.SigningRoot = &vote.SigningRoot
+			}
+			entry.SignedAttestations = append(entry.SignedAttestations, exported)
+			return true
+		})
+		interchange.Data = append(interchange.Data, entry)
+		return true
+	})
+	return interchange
+}
+
+// Import merges the votes in the interchange format. As recommended by EIP-3076,
+// the votes lower than the lowest imported ones are refused after the import.
+func (p *SlashingProtection) Import(interchange *Interchange, genesisHash common.Hash) error {
+	if v := interchange.Metadata.InterchangeFormatVersion; v != InterchangeFormatVersion {
+		return fmt.Errorf("unsupported interchange format version: %q", v)
+	}
+	if root := interchange.Metadata.GenesisValidatorsRoot; root != genesisHash {
+		return fmt.Errorf("genesis mismatch: interchange %s, chain %s", root.Hex(), genesisHash.Hex())
+	}
+	for i, entry := range interchange.Data {
+		if len(entry.PubKey) != 48 {
+			return fmt.Errorf("data[%d]: invalid BLS public key: %s", i, entry.PubKey)
+		}
+		for j, vote := range entry.SignedAttestations {
+			if vote.SourceEpoch > vote.TargetEpoch {
+				return fmt.Errorf("data[%d].signed_attestations[%d]: source %d is higher than target %d", i, j, vote.SourceEpoch, vote.TargetEpoch)
+			}
+		}
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, entry := range interchange.Data {
+		if len(entry.SignedAttestations) == 0 {
+			continue
+		}
+		pubKey := [48]byte(entry.PubKey)
+		key := p.readKey(pubKey)
+		lowSource, lowTarget := entry.SignedAttestations[0].SourceEpoch, entry.SignedAttestations[0].TargetEpoch
+		for _, vote := range entry.SignedAttestations {
+			lowSource, lowTarget = min(lowSource, vote.SourceEpoch), min(lowTarget, vote.TargetEpoch)
+			var signingRoot common.Hash
+			if vote.SigningRoot != nil {
+				signingRoot = *vote.SigningRoot
+			}
+			// Written one by one, as the conflicts and the pruning are checked against the database
+			batch := p.db.NewBatch()
+			p.insert(batch, pubKey, key, vote.SourceEpoch, vote.TargetEpoch, signingRoot)
+			if err := batch.Write(); err != nil {
+				return err
+			}
+		}
+		// The lowest imported vote is kept as the history, so the low target
+		// is just below it to refuse the conflicting votes at the target.
+		if lowTarget > 0 {
+			key.LowSource = max(key.LowSource, lowSource)
+			key.LowTarget = max(key.LowTarget, lowTarget-1)
+		}
+		p.writeKey(p.db, pubKey, key)
+		log.Info("Imported slashing protection votes", "pubKey", hexutil.Encode(pubKey[:]), "votes", len(entry.SignedAttestations), "lowTarget", key.LowTarget, "highTarget", key.HighTarget)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package vote
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+)
+
+func testVoteData(source, target uint64) *types.VoteData {
+	return &types.VoteData{
+		SourceNumber: source,
+		SourceHash:   common.BigToHash(new(big.Int).SetUint64(source)),
+		TargetNumber: target,
+		TargetHash:   common.BigToHash(new(big.Int).SetUint64(target + 1_000_000)),
+	}
+}
+
+func TestSlashingProtection(t *testing.T) {
+	var (
+		pubKey     = [48]byte{1}
+		protection = NewSlashingProtection(memorydb.New())
+	)
+	check := func(data *types.VoteData, want error) {
+		t.Helper()
+		if err := protection.CheckAndRecord(pubKey, data); !errors.Is(err, want) {
+			t.Fatalf("vote %d-->%d: err = %v, want %v", data.SourceNumber, data.TargetNumber, err, want)
+		}
+	}
+
+	check(testVoteData(10, 12), nil)
+	check(testVoteData(10, 12), nil) // the same vote can be signed again
+	check(&types.VoteData{SourceNumber: 10, TargetNumber: 12}, ErrDoubleVote)
+	check(testVoteData(12, 12), ErrInvalidVoteSpan)
+	check(testVoteData(11, 15), nil)
+	check(testVoteData(12, 14), ErrSurroundVote) // surrounded by 11-->15
+	check(testVoteData(9, 16), ErrSurroundVote)  // surrounds 10-->12 and 11-->15
+	check(testVoteData(11, 16), nil)
+
+	// Another key is not affected
+	if err := protection.CheckAndRecord([48]byte{2}, &types.VoteData{SourceNumber: 10, TargetNumber: 12}); err != nil {
+		t.Fatalf("another key refused: %v", err)
+	}
+
+	// The votes out of the history are pruned into the low watermarks
+	check(testVoteData(16+slashingProtectionHistory, 16+slashingProtectionHistory+1), nil)
+	key := protection.readKey(pubKey)
+	if key.LowSource != 11 || key.LowTarget != 16 {
+		t.Fatalf("low watermarks = %d/%d, want 11/16", key.LowSource, key.LowTarget)
+	}
+	check(testVoteData(17, 18), nil)
+	check(testVoteData(10, 20), ErrVoteBelowWatermark)
+	check(testVoteData(11, 16), ErrVoteBelowWatermark)
+}
+
+func TestSlashingProtectionInterchange(t *testing.T) {
+	var (
+		pubKey      = [48]byte{1}
+		genesisHash = common.HexToHash("0x01")
+		source      = NewSlashingProtection(memorydb.New())
+	)
+	for _, data := range []*types.VoteData{testVoteData(10, 12), testVoteData(11, 15)} {
+		if err := source.CheckAndRecord(pubKey, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Round trip through JSON, as the interchange is a file
+	encoded, err := json.Marshal(source.Export(genesisHash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var interchange Interchange
+	if err := json.Unmarshal(encoded, &interchange); err != nil {
+		t.Fatal(err)
+	}
+	if len(interchange.Data) != 1 || len(interchange.Data[0].SignedAttestations) != 2 {
+		t.Fatalf("unexpected interchange: %s", encoded)
+	}
+
+	dest := NewSlashingProtection(memorydb.New())
+	if err := dest.Import(&interchange, common.HexToHash("0x02")); err == nil {
+		t.Fatal("imported interchange of another chain")
+	}
+	// The votes signed on the new host are kept
+	if err := dest.CheckAndRecord(pubKey, testVoteData(13, 20)); err != nil {
+		t.Fatal(err)
+	}
+	if err := dest.Import(&interchange, genesisHash); err != nil {
+		t.Fatalf("failed to import: %v", err)
+	}
+	for _, tc := range []struct {
+		data *types.VoteData
+		want error
+	}{
+		{testVoteData(11, 15), nil},
+		{&types.VoteData{SourceNumber: 11, TargetNumber: 15}, ErrDoubleVote},
+		{testVoteData(12, 14), ErrSurroundVote},
+		{testVoteData(5, 11), ErrVoteBelowWatermark},
+		{testVoteData(9, 13), ErrVoteBelowWatermark},
+		{testVoteData(14, 19), ErrSurroundVote}, // surrounded by the vote signed on the new host
+		{testVoteData(15, 21), nil},
+	} {
+		if err := dest.CheckAndRecord(pubKey, tc.data); !errors.Is(err, tc.want) {
+			t.Errorf("vote %d-->%d: err = %v, want %v", tc.data.SourceNumber, tc.data.TargetNumber, err, tc.want)
+		}
+	}
+
+	// The low watermarks are exported as the vote without the signing root
+	pruned := NewSlashingProtection(memorydb.New())
+	pruned.CheckAndRecord(pubKey, testVoteData(1, 2))
+	pruned.CheckAndRecord(pubKey, testVoteData(3, 4+slashingProtectionHistory))
+	exported := pruned.Export(genesisHash).Data[0].SignedAttestations
+	if len(exported) != 2 || exported[0].TargetEpoch != 2 || exported[0].SigningRoot != nil {
+		t.Fatalf("unexpected exported votes: %+v", exported)
+	}
+}
+
+func TestSignerSlashingProtectionMissing(t *testing.T) {
+	var (
+		dir    = t.TempDir()
+		path   = filepath.Join(dir, SlashingProtectionDatabase)
+		marker = filepath.Join(dir, SlashingProtectionMarker)
+	)
+	// The database is created on the first use
+	p, err := OpenSignerSlashingProtection(path, marker)
+	if err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+	if err := p.CheckAndRecord([48]byte{1}, testVoteData(1, 2)); err != nil {
+		t.Fatalf("failed to record: %v", err)
+	}
+	p.Close()
+
+	// Reopening the existing database is allowed
+	if p, err = OpenSignerSlashingProtection(path, marker); err != nil {
+		t.Fatalf("failed to reopen: %v", err)
+	}
+	p.Close()
+
+	// The lost or moved database is refused
+	if err := os.RemoveAll(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenSignerSlashingProtection(path, marker); !errors.Is(err, ErrSlashingProtectionMissing) {
+		t.Fatalf("expected missing database error, got %v", err)
+	}
+	if _, err := OpenSignerSlashingProtection(filepath.Join(dir, "elsewhere"), marker); !errors.Is(err, ErrSlashingProtectionMissing) {
+		t.Fatalf("expected missing database error, got %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatal("missing database is created")
+	}
+
+	// The empty database is started once the marker is removed
+	if err := os.Remove(marker); err != nil {
+		t.Fatal(err)
+	}
+	if p, err = OpenSignerSlashingProtection(path, marker); err != nil {
+		t.Fatalf("failed to open without marker: %v", err)
+	}
+	p.Close()
+}
@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"fmt"
 	"math/big"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/oasys"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/standby"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/event"
@@ -25,6 +27,10 @@ import (
 // before participating in voting after it starts mining.
 const blocksNumberSinceMining = 20 * params.MaxwellBlockTimeReductionFactorForBSC
 
+// upperLimitOfVoteBlockNumber is the number of blocks ahead of the target whose
+// votes are checked against the slashing rules.
+const upperLimitOfVoteBlockNumber = 11 // refer to fetcher.maxUncleDist
+
 var diffInTurn = big.NewInt(2) // Block difficulty for in-turn signatures
 var votesManagerCounter = metrics.NewRegisteredCounter("votesManager/local", nil)
 var notJustified = metrics.NewRegisteredCounter("votesManager/notJustified", nil)
@@ -56,10 +62,13 @@ type VoteManager struct {
 	signer  *VoteSigner
 	journal *VoteJournal
 
+	// standby refuses voting without the validator lease if set
+	standby atomic.Pointer[standby.Manager]
+
 	engine consensus.PoS
 }
 
-func NewVoteManager(eth Backend, chain *core.BlockChain, pool *VotePool, journalPath, blsPasswordPath, blsWalletPath, blsAccountName string, engine consensus.PoS) (*VoteManager, error) {
+func NewVoteManager(eth Backend, chain *core.BlockChain, pool *VotePool, journalPath string, voteSigner *VoteSigner, engine consensus.PoS) (*VoteManager, error) {
 	voteManager := &VoteManager{
 		eth:                    eth,
 		chain:                  chain,
@@ -69,14 +78,10 @@ func NewVoteManager(eth Backend, chain *core.BlockChain, pool *VotePool, journal
 		engine:                 engine,
 	}
 
-	// Create voteSigner.
-	voteSigner, err := NewVoteSigner(blsPasswordPath, blsWalletPath, blsAccountName)
-	if err != nil {
-		return nil, err
-	}
-	log.Info("Create voteSigner successfully", "pubKey", common.Bytes2Hex(voteSigner.PubKey[:]))
+	pubKey := voteSigner.PubKey()
+	log.Info("Use voteSigner", "pubKey", common.Bytes2Hex(pubKey[:]))
 	voteManager.signer = voteSigner
-	metrics.GetOrRegisterLabel("miner-info", nil).Mark(map[string]interface{}{"VoteKey": common.Bytes2Hex(voteManager.signer.PubKey[:])})
+	metrics.GetOrRegisterLabel("miner-info", nil).Mark(map[string]interface{}{"VoteKey": common.Bytes2Hex(pubKey[:])})
 
 	// Create voteJournal
 	voteJournal, err := NewVoteJournal(journalPath)
@@ -95,6 +100,57 @@ func NewVoteManager(eth Backend, chain *core.BlockChain, pool *VotePool, journal
 	return voteManager, nil
 }
 
+// SetStandby makes the vote manager refuse to vote unless the standby manager
+// holds the validator lease, syncing the votes of the active node meanwhile.
+func (voteManager *VoteManager) SetStandby(standby *standby.Manager) {
+	voteManager.standby.Store(standby)
+}
+
+// voting returns whether the node is allowed to vote.
+func (voteManager *VoteManager) voting() bool {
+	if !voteManager.eth.IsMining() {
+		return false
+	}
+	standby := voteManager.standby.Load()
+	return standby == nil || standby.Active()
+}
+
+// CheckTakeover prepares the standby node to take over the voting from another
+// node of the validator. The votes of the keys of the node known to the vote
+// pool are recorded into the vote journal and the slashing protection, so that
+// none of them is conflicted by the new votes.
+func (voteManager *VoteManager) CheckTakeover() error {
+	protection := voteManager.signer.protection
+	if protection != nil {
+		if err := protection.available(); err != nil {
+			return err
+		}
+	}
+	if _, err := voteManager.journal.walLog.LastIndex(); err != nil {
+		return fmt.Errorf("vote journal unavailable: %w", err)
+	}
+	var recorded int
+	for _, vote := range voteManager.pool.GetVotes() {
+		if !voteManager.signer.HasKey(vote.VoteAddress) {
+			continue
+		}
+		if protection != nil {
+			if err := protection.Observe(vote.VoteAddress, vote.Data); err != nil {
+				return fmt.Errorf("failed to record vote into slashing protection: %w", err)
+			}
+		}
+		if data, ok := voteManager.journal.voteDataBuffer.Get(vote.Data.TargetNumber); ok && data.Hash() == vote.Data.Hash() {
+			continue
+		}
+		if err := voteManager.journal.WriteVote(vote); err != nil {
+			return fmt.Errorf("failed to write vote into journal: %w", err)
+		}
+		recorded++
+	}
+	log.Debug("Checked votes before taking over the validator", "recorded", recorded)
+	return nil
+}
+
 func (voteManager *VoteManager) loop() {
 	log.Debug("vote manager routine loop started")
 	defer voteManager.highestVerifiedBlockSub.Unsubscribe()
@@ -136,9 +192,9 @@ func (voteManager *VoteManager) loop() {
 				log.Debug("startVote flag is false, continue")
 				continue
 			}
-			if !voteManager.eth.IsMining() {
+			if !voteManager.voting() {
 				blockCountSinceMining = 0
-				log.Debug("skip voting because mining is disabled, continue")
+				log.Debug("skip voting because mining is disabled or the validator lease is not held, continue")
 				continue
 			}
 			blockCountSinceMining++
@@ -162,12 +218,21 @@ func (voteManager *VoteManager) loop() {
 				}
 			}
 
-			// Check if cur validator is within the validatorSet at curHead
+			// Check if cur validator is within the validatorSet at curHead, and
+			// pick the signer key registered for it (default key if unchecked)
+			signerKeys := voteManager.signer.KeysAt(curHead.Number.Uint64())
+			voteKey := signerKeys[0]
 			if !voteManager.engine.IsActiveValidatorAt(voteManager.chain, curHead,
 				func(bLSPublicKey *types.BLSPublicKey) bool {
-					return bytes.Equal(voteManager.signer.PubKey[:], bLSPublicKey[:])
+					for _, key := range signerKeys {
+						if bytes.Equal(key[:], bLSPublicKey[:]) {
+							voteKey = key
+							return true
+						}
+					}
+					return false
 				}) {
-				log.Debug("cur validator is not within the validatorSet at curHead or registered blsPubKey does not match", "signer", common.Bytes2Hex(voteManager.signer.PubKey[:]), "curHead", curHead.Number)
+				log.Debug("cur validator is not within the validatorSet at curHead or registered blsPubKey does not match", "signer", common.Bytes2Hex(signerKeys[0][:]), "keys", len(signerKeys), "curHead", curHead.Number)
 				continue
 			}
 
@@ -191,7 +256,7 @@ func (voteManager *VoteManager) loop() {
 				voteMessage.Data.SourceNumber = sourceNumber
 				voteMessage.Data.SourceHash = sourceHash
 
-				if err := voteManager.signer.SignVote(voteMessage); err != nil {
+				if err := voteManager.signer.SignVoteWithKey(voteKey, voteMessage); err != nil {
 					log.Error("Failed to sign vote", "err", err, "votedBlockNumber", voteMessage.Data.TargetNumber, "votedBlockHash", voteMessage.Data.TargetHash, "voteMessageHash", voteMessage.Hash())
 					votesSigningErrorCounter.Inc(1)
 					continue
@@ -239,9 +304,14 @@ func (voteManager *VoteManager) loop() {
 
 		case event := <-voteManager.syncVoteCh:
 			voteMessage := event.Vote
-			if voteManager.eth.IsMining() || !bytes.Equal(voteManager.signer.PubKey[:], voteMessage.VoteAddress[:]) {
+			if voteManager.voting() || !voteManager.signer.HasKey(voteMessage.VoteAddress) {
 				continue
 			}
+			if protection := voteManager.signer.protection; protection != nil {
+				if err := protection.Observe(voteMessage.VoteAddress, voteMessage.Data); err != nil {
+					log.Error("Failed to record synced vote into slashing protection", "err", err)
+				}
+			}
 			if err := voteManager.journal.WriteVote(voteMessage); err != nil {
 				log.Error("Failed to write vote into journal", "err", err)
 				voteJournalErrorCounter.Inc(1)
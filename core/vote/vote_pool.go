@@ -1,35 +1,61 @@
 package vote
 
 import (
+	"bytes"
 	"container/heap"
+	"errors"
+	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	mapset "github.com/deckarep/golang-set/v2"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vote/voteconfig"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
 )
 
 const (
-	maxCurVoteAmountPerBlock    = 50
-	maxFutureVoteAmountPerBlock = 100
-
 	voteBufferForPut = 256
-	// votes in the range (currentBlockNum-256,currentBlockNum+11] will be stored
-	lowerLimitOfVoteBlockNumber = 256
-	upperLimitOfVoteBlockNumber = 11 // refer to fetcher.maxUncleDist
 
 	highestVerifiedBlockChanSize = 10 // highestVerifiedBlockChanSize is the size of channel listening to HighestVerifiedBlockEvent.
 
+	// futureVotesRecheckInterval is the interval to retry transferring the future
+	// votes, whose blocks may be verified without becoming the highest one.
+	futureVotesRecheckInterval = 3 * time.Second
+
 	defaultMajorityThreshold = 20 // this is an inaccurate value, mainly used for metric acquisition, ref parlia.verifyVoteAttestation
 )
 
+var (
+	// ErrVoteDuplicate is returned if the vote is already in the pool.
+	ErrVoteDuplicate = errors.New("duplicate vote")
+
+	// ErrVoteStale is returned if the vote is for a block too old to be kept.
+	ErrVoteStale = errors.New("vote for stale block")
+
+	// ErrVoteFarFuture is returned if the vote is for a block too far ahead of
+	// the current head.
+	ErrVoteFarFuture = errors.New("vote for far future block")
+
+	// ErrVotePoolFull is returned if the pool has enough votes for the block.
+	ErrVotePoolFull = errors.New("too many votes for block")
+
+	// ErrVoteInvalidSignature is returned if the BLS signature of the vote is invalid.
+	ErrVoteInvalidSignature = errors.New("invalid vote signature")
+
+	// ErrVoteInvalidVoter is returned if the vote is rejected by the consensus
+	// engine, e.g. it's not signed by a validator.
+	ErrVoteInvalidVoter = errors.New("invalid voter")
+)
+
 var (
 	localCurVotesCounter    = metrics.NewRegisteredCounter("curVotes/local", nil)
 	localFutureVotesCounter = metrics.NewRegisteredCounter("futureVotes/local", nil)
@@ -40,6 +66,12 @@ var (
 	localFutureVotesPqGauge = metrics.NewRegisteredGauge("futureVotesPq/local", nil)
 )
 
+// validatorCounter is implemented by the engines which report the number of the
+// validators voting for a block.
+type validatorCounter interface {
+	ActiveValidatorCount(chain consensus.ChainHeaderReader, header *types.Header) (int, error)
+}
+
 type VoteBox struct {
 	blockNumber  uint64
 	blockHash    common.Hash
@@ -60,8 +92,14 @@ func (v *VoteBox) trySetRecvVoteTime(chain *core.BlockChain) {
 }
 
 type VotePool struct {
-	chain *core.BlockChain
-	mu    sync.RWMutex
+	config voteconfig.Config
+	chain  *core.BlockChain
+	mu     sync.RWMutex
+
+	validators     int    // Number of the active validators at the highest verified block
+	maxCurVotes    uint64 // Maximum number of votes kept per verified block
+	maxFutureVotes uint64 // Maximum number of votes kept per block not verified yet
+	highestHeader  *types.Header
 
 	votesFeed event.Feed
 	scope     event.SubscriptionScope
@@ -77,15 +115,26 @@ type VotePool struct {
 	highestVerifiedBlockCh  chan core.HighestVerifiedBlockEvent
 	highestVerifiedBlockSub event.Subscription
 
-	votesCh chan *types.VoteEnvelope
+	votesCh chan *voteRequest
 
 	engine consensus.PoS
 }
 
+// voteRequest is a vote waiting to be put into the pool, with the callback
+// receiving the result.
+type voteRequest struct {
+	vote   *types.VoteEnvelope
+	result func(error)
+}
+
 type votesPriorityQueue []*types.VoteData
 
-func NewVotePool(chain *core.BlockChain, engine consensus.PoS) *VotePool {
+func NewVotePool(config voteconfig.Config, chain *core.BlockChain, engine consensus.PoS) *VotePool {
+	config = config.Sanitize()
 	votePool := &VotePool{
+		config:                 config,
+		maxCurVotes:            config.CurVotesPerBlock,
+		maxFutureVotes:         config.FutureVotesPerBlock,
 		chain:                  chain,
 		receivedVotes:          mapset.NewSet[common.Hash](),
 		curVotes:               make(map[common.Hash]*VoteBox),
@@ -93,7 +142,7 @@ func NewVotePool(chain *core.BlockChain, engine consensus.PoS) *VotePool {
 		curVotesPq:             &votesPriorityQueue{},
 		futureVotesPq:          &votesPriorityQueue{},
 		highestVerifiedBlockCh: make(chan core.HighestVerifiedBlockEvent, highestVerifiedBlockChanSize),
-		votesCh:                make(chan *types.VoteEnvelope, voteBufferForPut),
+		votesCh:                make(chan *voteRequest, voteBufferForPut),
 		engine:                 engine,
 	}
 
@@ -108,39 +157,69 @@ func NewVotePool(chain *core.BlockChain, engine consensus.PoS) *VotePool {
 func (pool *VotePool) loop() {
 	defer pool.highestVerifiedBlockSub.Unsubscribe()
 
+	recheck := time.NewTicker(futureVotesRecheckInterval)
+	defer recheck.Stop()
+
 	for {
 		select {
 		// Handle ChainHeadEvent.
 		case ev := <-pool.highestVerifiedBlockCh:
 			if ev.Header != nil {
 				latestBlockNumber := ev.Header.Number.Uint64()
+				pool.resize(ev.Header)
 				pool.prune(latestBlockNumber)
 				pool.transferVotesFromFutureToCur(ev.Header)
 			}
 		case <-pool.highestVerifiedBlockSub.Err():
 			return
 
+		// Transfer the future votes whose blocks are verified on the side chains.
+		case <-recheck.C:
+			pool.mu.RLock()
+			header := pool.highestHeader
+			pool.mu.RUnlock()
+			if header != nil {
+				pool.transferVotesFromFutureToCur(header)
+			}
+
 		// Handle votes channel and put the vote into vote pool.
-		case vote := <-pool.votesCh:
-			pool.putIntoVotePool(vote)
+		case req := <-pool.votesCh:
+			err := pool.putIntoVotePool(req.vote)
+			if req.result != nil {
+				req.result(err)
+			}
 		}
 	}
 }
 
 func (pool *VotePool) PutVote(vote *types.VoteEnvelope) {
-	pool.votesCh <- vote
+	pool.votesCh <- &voteRequest{vote: vote}
 }
 
-func (pool *VotePool) putIntoVotePool(vote *types.VoteEnvelope) bool {
+// PutVoteWithResult puts the vote into the pool asynchronously like PutVote, and
+// invokes the callback with the reason if the vote is rejected, or nil. The
+// callback runs on the loop of the pool, so it must not block.
+//
+// The future votes are verified by the consensus engine only when their blocks
+// arrive, so the invalid voters of them are not reported.
+func (pool *VotePool) PutVoteWithResult(vote *types.VoteEnvelope, result func(error)) {
+	pool.votesCh <- &voteRequest{vote: vote, result: result}
+}
+
+func (pool *VotePool) putIntoVotePool(vote *types.VoteEnvelope) error {
 	targetNumber := vote.Data.TargetNumber
 	targetHash := vote.Data.TargetHash
 	header := pool.chain.CurrentBlock()
 	headNumber := header.Number.Uint64()
 
-	// Make sure in the range (currentHeight-lowerLimitOfVoteBlockNumber, currentHeight+upperLimitOfVoteBlockNumber].
-	if targetNumber+lowerLimitOfVoteBlockNumber-1 < headNumber || targetNumber > headNumber+upperLimitOfVoteBlockNumber {
-		log.Debug("BlockNumber of vote is outside the range of header-256~header+11, will be discarded")
-		return false
+	// Make sure in the range (currentHeight-PruneDistance, currentHeight+FutureDistance].
+	if targetNumber+pool.config.PruneDistance-1 < headNumber {
+		log.Debug("BlockNumber of vote is outside the range of header-PruneDistance~header+FutureDistance, will be discarded")
+		return ErrVoteStale
+	}
+	if targetNumber > headNumber+pool.config.FutureDistance {
+		log.Debug("BlockNumber of vote is outside the range of header-PruneDistance~header+FutureDistance, will be discarded")
+		return ErrVoteFarFuture
 	}
 
 	voteData := &types.VoteData{
@@ -163,15 +242,15 @@ func (pool *VotePool) putIntoVotePool(vote *types.VoteEnvelope) bool {
 	}
 
 	voteHash := vote.Hash()
-	if ok := pool.basicVerify(vote, headNumber, votes, isFutureVote, voteHash); !ok {
-		return false
+	if err := pool.basicVerify(vote, headNumber, votes, isFutureVote, voteHash); err != nil {
+		return err
 	}
 
 	if !isFutureVote {
 		// Verify if the vote comes from valid validators based on voteAddress (BLSPublicKey), only verify curVotes here, will verify futureVotes in transfer process.
 		if err := pool.engine.VerifyVote(pool.chain, vote); err != nil {
 			log.Warn("invalid vote", "sourceNumber", vote.Data.SourceNumber, "targetNumber", vote.Data.TargetNumber, "err", err)
-			return false
+			return fmt.Errorf("%w: %v", ErrVoteInvalidVoter, err)
 		}
 
 		// Send vote for handler usage of broadcasting to peers.
@@ -181,7 +260,7 @@ func (pool *VotePool) putIntoVotePool(vote *types.VoteEnvelope) bool {
 
 	pool.putVote(votes, votesPq, vote, voteData, voteHash, isFutureVote)
 
-	return true
+	return nil
 }
 
 func (pool *VotePool) SubscribeNewVoteEvent(ch chan<- core.NewVoteEvent) event.Subscription {
@@ -203,7 +282,7 @@ func (pool *VotePool) putVote(m map[common.Hash]*VoteBox, votesPq *votesPriority
 		voteBox := &VoteBox{
 			blockNumber:  targetNumber,
 			blockHash:    targetHash,
-			voteMessages: make([]*types.VoteEnvelope, 0, maxFutureVoteAmountPerBlock),
+			voteMessages: make([]*types.VoteEnvelope, 0, pool.maxFutureVotes),
 		}
 		m[targetHash] = voteBox
 
@@ -236,13 +315,13 @@ func (pool *VotePool) transferVotesFromFutureToCur(latestBlockHeader *types.Head
 	futurePq := pool.futureVotesPq
 	latestBlockNumber := latestBlockHeader.Number.Uint64()
 
-	// For vote in the range [,latestBlockNumber-11), transfer to cur if valid.
-	for futurePq.Len() > 0 && futurePq.Peek().TargetNumber+upperLimitOfVoteBlockNumber < latestBlockNumber {
+	// For vote in the range [,latestBlockNumber-FutureDistance), transfer to cur if valid.
+	for futurePq.Len() > 0 && futurePq.Peek().TargetNumber+pool.config.FutureDistance < latestBlockNumber {
 		blockHash := futurePq.Peek().TargetHash
 		pool.transfer(blockHash)
 	}
 
-	// For vote in the range [latestBlockNumber-11,latestBlockNumber], only transfer the vote inside the local fork.
+	// For vote in the range [latestBlockNumber-FutureDistance,latestBlockNumber], only transfer the vote inside the local fork.
 	futurePqBuffer := make([]*types.VoteData, 0)
 	for futurePq.Len() > 0 && futurePq.Peek().TargetNumber <= latestBlockNumber {
 		blockHash := futurePq.Peek().TargetHash
@@ -306,6 +385,32 @@ func (pool *VotePool) transfer(blockHash common.Hash) {
 	localFutureVotesCounter.Dec(int64(len(voteBox.voteMessages)))
 }
 
+// resize sizes the vote limits per block from the active validators at the
+// highest verified block.
+func (pool *VotePool) resize(header *types.Header) {
+	validators := -1
+	if counter, ok := pool.engine.(validatorCounter); ok {
+		count, err := counter.ActiveValidatorCount(pool.chain, header)
+		if err != nil {
+			log.Debug("Failed to count the validators for the vote pool", "number", header.Number, "err", err)
+		} else {
+			validators = count
+		}
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pool.highestHeader = header
+	if validators < 0 || validators == pool.validators {
+		return
+	}
+	pool.validators = validators
+	pool.maxCurVotes = max(pool.config.CurVotesPerBlock, uint64(validators))
+	pool.maxFutureVotes = max(pool.config.FutureVotesPerBlock, 2*uint64(validators))
+	log.Debug("Resized the vote pool", "validators", validators, "curVotes", pool.maxCurVotes, "futureVotes", pool.maxFutureVotes)
+}
+
 // Prune old data of duplicationSet, curVotePq and curVotesMap.
 func (pool *VotePool) prune(latestBlockNumber uint64) {
 	pool.mu.Lock()
@@ -313,8 +418,8 @@ func (pool *VotePool) prune(latestBlockNumber uint64) {
 	curVotes := pool.curVotes
 	curVotesPq := pool.curVotesPq
 
-	// delete votes in the range [,latestBlockNumber-lowerLimitOfVoteBlockNumber]
-	for curVotesPq.Len() > 0 && curVotesPq.Peek().TargetNumber+lowerLimitOfVoteBlockNumber-1 < latestBlockNumber {
+	// delete votes in the range [,latestBlockNumber-PruneDistance]
+	for curVotesPq.Len() > 0 && curVotesPq.Peek().TargetNumber+pool.config.PruneDistance-1 < latestBlockNumber {
 		// Prune curPriorityQueue.
 		blockHash := heap.Pop(curVotesPq).(*types.VoteData).TargetHash
 		localCurVotesPqGauge.Update(int64(curVotesPq.Len()))
@@ -347,6 +452,70 @@ func (pool *VotePool) GetVotes() []*types.VoteEnvelope {
 	return votesRes
 }
 
+// BlockVotesStatus is the number of the votes in the pool for a block.
+type BlockVotesStatus struct {
+	Number hexutil.Uint64 `json:"number"`
+	Hash   common.Hash    `json:"hash"`
+	Votes  int            `json:"votes"`
+	Future bool           `json:"future"` // Whether the block is not verified yet
+}
+
+// PoolStatus is the sizes of the queues and the limits of the vote pool.
+type PoolStatus struct {
+	Validators     int                 `json:"validators"`
+	MaxCurVotes    uint64              `json:"maxCurVotes"`
+	MaxFutureVotes uint64              `json:"maxFutureVotes"`
+	PruneDistance  uint64              `json:"pruneDistance"`
+	FutureDistance uint64              `json:"futureDistance"`
+	CurVotes       int                 `json:"curVotes"`
+	FutureVotes    int                 `json:"futureVotes"`
+	CurBlocks      int                 `json:"curBlocks"`
+	FutureBlocks   int                 `json:"futureBlocks"`
+	ReceivedVotes  int                 `json:"receivedVotes"`
+	Blocks         []*BlockVotesStatus `json:"blocks"`
+}
+
+// Status returns the sizes of the queues and the number of the votes per block,
+// in the descending order of the block numbers.
+func (pool *VotePool) Status() *PoolStatus {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	status := &PoolStatus{
+		Validators:     pool.validators,
+		MaxCurVotes:    pool.maxCurVotes,
+		MaxFutureVotes: pool.maxFutureVotes,
+		PruneDistance:  pool.config.PruneDistance,
+		FutureDistance: pool.config.FutureDistance,
+		CurBlocks:      pool.curVotesPq.Len(),
+		FutureBlocks:   pool.futureVotesPq.Len(),
+		ReceivedVotes:  pool.receivedVotes.Cardinality(),
+		Blocks:         make([]*BlockVotesStatus, 0, len(pool.curVotes)+len(pool.futureVotes)),
+	}
+	for future, votes := range map[bool]map[common.Hash]*VoteBox{false: pool.curVotes, true: pool.futureVotes} {
+		for _, voteBox := range votes {
+			if future {
+				status.FutureVotes += len(voteBox.voteMessages)
+			} else {
+				status.CurVotes += len(voteBox.voteMessages)
+			}
+			status.Blocks = append(status.Blocks, &BlockVotesStatus{
+				Number: hexutil.Uint64(voteBox.blockNumber),
+				Hash:   voteBox.blockHash,
+				Votes:  len(voteBox.voteMessages),
+				Future: future,
+			})
+		}
+	}
+	sort.Slice(status.Blocks, func(i, j int) bool {
+		if status.Blocks[i].Number != status.Blocks[j].Number {
+			return status.Blocks[i].Number > status.Blocks[j].Number
+		}
+		return bytes.Compare(status.Blocks[i].Hash[:], status.Blocks[j].Hash[:]) < 0
+	})
+	return status
+}
+
 func (pool *VotePool) FetchVoteByBlockHash(blockHash common.Hash) []*types.VoteEnvelope {
 	pool.mu.RLock()
 	defer pool.mu.RUnlock()
@@ -356,7 +525,7 @@ func (pool *VotePool) FetchVoteByBlockHash(blockHash common.Hash) []*types.VoteE
 	return nil
 }
 
-func (pool *VotePool) basicVerify(vote *types.VoteEnvelope, headNumber uint64, m map[common.Hash]*VoteBox, isFutureVote bool, voteHash common.Hash) bool {
+func (pool *VotePool) basicVerify(vote *types.VoteEnvelope, headNumber uint64, m map[common.Hash]*VoteBox, isFutureVote bool, voteHash common.Hash) error {
 	targetHash := vote.Data.TargetHash
 	pool.mu.RLock()
 	defer pool.mu.RUnlock()
@@ -364,28 +533,28 @@ func (pool *VotePool) basicVerify(vote *types.VoteEnvelope, headNumber uint64, m
 	// Check duplicate voteMessage firstly.
 	if pool.receivedVotes.Contains(voteHash) {
 		log.Debug("Vote pool already contained the same vote", "voteHash", voteHash, "sourceNumber", vote.Data.SourceNumber, "targetNumber", vote.Data.TargetNumber)
-		return false
+		return ErrVoteDuplicate
 	}
 
-	// To prevent DOS attacks, make sure no more than 50 votes per blockHash if not futureVotes
-	// and no more than 100 votes per blockHash if futureVotes.
-	maxVoteAmountPerBlock := maxCurVoteAmountPerBlock
+	// To prevent DOS attacks, limit the votes per blockHash by the number of the
+	// validators, and twice of it if futureVotes.
+	maxVoteAmountPerBlock := pool.maxCurVotes
 	if isFutureVote {
-		maxVoteAmountPerBlock = maxFutureVoteAmountPerBlock
+		maxVoteAmountPerBlock = pool.maxFutureVotes
 	}
 	if voteBox, ok := m[targetHash]; ok {
-		if len(voteBox.voteMessages) >= maxVoteAmountPerBlock {
-			return false
+		if uint64(len(voteBox.voteMessages)) >= maxVoteAmountPerBlock {
+			return ErrVotePoolFull
 		}
 	}
 
 	// Verify bls signature.
 	if err := vote.Verify(); err != nil {
 		log.Error("Failed to verify voteMessage", "err", err)
-		return false
+		return fmt.Errorf("%w: %v", ErrVoteInvalidSignature, err)
 	}
 
-	return true
+	return nil
 }
 
 func (pq votesPriorityQueue) Less(i, j int) bool {
@@ -28,6 +28,7 @@ import (
 	"testing"
 	"time"
 
+	mapset "github.com/deckarep/golang-set/v2"
 	"github.com/google/uuid"
 	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
 	"github.com/prysmaticlabs/prysm/v5/validator/accounts"
@@ -41,6 +42,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vote/voteconfig"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/event"
@@ -159,7 +161,7 @@ func testVotePool(t *testing.T, isValidRules bool) {
 	}
 
 	// Create vote pool
-	votePool := NewVotePool(chain, mockEngine)
+	votePool := NewVotePool(voteconfig.DefaultConfig, chain, mockEngine)
 
 	// Create vote manager
 	// Create a temporary file for the votes journal
@@ -174,7 +176,11 @@ func testVotePool(t *testing.T, isValidRules bool) {
 	file.Close()
 	os.Remove(journal)
 
-	voteManager, err := NewVoteManager(newTestBackend(), chain, votePool, journal, walletPasswordDir, walletDir, "", mockEngine)
+	voteSigner, err := NewVoteSigner(walletPasswordDir, walletDir, "")
+	if err != nil {
+		t.Fatalf("failed to create vote signer: %v", err)
+	}
+	voteManager, err := NewVoteManager(newTestBackend(), chain, votePool, journal, voteSigner, mockEngine)
 	if err != nil {
 		t.Fatalf("failed to create vote managers")
 	}
@@ -242,6 +248,14 @@ func testVotePool(t *testing.T, isValidRules bool) {
 		t.Fatalf("journal failed")
 	}
 
+	// The takeover check doesn't journal the own votes again
+	if err := voteManager.CheckTakeover(); err != nil {
+		t.Fatalf("takeover check failed: %v", err)
+	}
+	if !voteJournal.verifyJournal(12, 12) {
+		t.Fatalf("journal failed after takeover check")
+	}
+
 	for i := 0; i < 256; i++ {
 		bs, _ = core.GenerateChain(params.TestChainConfig, bs[len(bs)-1], ethash.NewFaker(), db, 1, nil)
 		if _, err := chain.InsertChain(bs); err != nil {
@@ -265,7 +279,11 @@ func testVotePool(t *testing.T, isValidRules bool) {
 			TargetNumber: 1000,
 		},
 	}
-	voteManager.pool.PutVote(invalidVote)
+	results := make(chan error, 1)
+	voteManager.pool.PutVoteWithResult(invalidVote, func(err error) { results <- err })
+	if err := <-results; !errors.Is(err, ErrVoteFarFuture) {
+		t.Fatalf("far future vote error mismatch: have %v, want %v", err, ErrVoteFarFuture)
+	}
 
 	if !votePool.verifyStructureSizeOfVotePool(256, 256, 0, 256, 0) {
 		t.Fatalf("put vote failed")
@@ -275,6 +293,15 @@ func testVotePool(t *testing.T, isValidRules bool) {
 	if len(votes) != 256 {
 		t.Fatalf("get votes failed")
 	}
+	status := votePool.Status()
+	if status.CurVotes != 256 || status.FutureVotes != 0 || status.CurBlocks != 256 || len(status.Blocks) != 256 {
+		t.Fatalf("pool status mismatch: %+v", status)
+	}
+	for i := 1; i < len(status.Blocks); i++ {
+		if status.Blocks[i-1].Number < status.Blocks[i].Number {
+			t.Fatalf("pool status blocks not sorted")
+		}
+	}
 
 	// Verify journal
 	if !voteJournal.verifyJournal(268, 268) {
@@ -451,3 +478,41 @@ func setUpKeyManager(t *testing.T) (string, string) {
 	})
 	return walletPasswordDir, walletDir
 }
+
+type mockCountingPOS struct {
+	mockPOS
+	validators int
+}
+
+func (mp *mockCountingPOS) ActiveValidatorCount(chain consensus.ChainHeaderReader, header *types.Header) (int, error) {
+	return mp.validators, nil
+}
+
+func TestVotePoolResize(t *testing.T) {
+	engine := &mockCountingPOS{validators: 10}
+	pool := &VotePool{
+		config:         voteconfig.DefaultConfig,
+		maxCurVotes:    voteconfig.DefaultConfig.CurVotesPerBlock,
+		maxFutureVotes: voteconfig.DefaultConfig.FutureVotesPerBlock,
+		receivedVotes:  mapset.NewSet[common.Hash](),
+		curVotesPq:     &votesPriorityQueue{},
+		futureVotesPq:  &votesPriorityQueue{},
+		engine:         engine,
+	}
+	header := &types.Header{Number: big.NewInt(1)}
+
+	// The configured limits are kept for the few validators.
+	pool.resize(header)
+	if pool.maxCurVotes != 50 || pool.maxFutureVotes != 100 {
+		t.Fatalf("limits mismatch: have %d/%d, want 50/100", pool.maxCurVotes, pool.maxFutureVotes)
+	}
+	// The limits are raised for the many validators.
+	engine.validators = 80
+	pool.resize(header)
+	if pool.maxCurVotes != 80 || pool.maxFutureVotes != 160 {
+		t.Fatalf("limits mismatch: have %d/%d, want 80/160", pool.maxCurVotes, pool.maxFutureVotes)
+	}
+	if status := pool.Status(); status.Validators != 80 {
+		t.Fatalf("validators mismatch: have %d, want 80", status.Validators)
+	}
+}
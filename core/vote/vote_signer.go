@@ -2,7 +2,11 @@ package vote
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -14,6 +18,7 @@ import (
 	"github.com/prysmaticlabs/prysm/v5/validator/keymanager"
 	"github.com/prysmaticlabs/prysm/v5/validator/keymanager/local"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
@@ -25,26 +30,129 @@ const (
 
 var votesSigningErrorCounter = metrics.NewRegisteredCounter("votesSigner/error", nil)
 
+// VoteSigner signs the votes with the BLS keys of the local wallet or the remote
+// signer. It can hold multiple keys, and the keys can be reloaded to switch at a
+// block without restarting the node.
 type VoteSigner struct {
-	km     *keymanager.IKeymanager
-	PubKey [48]byte
+	timeout    time.Duration
+	protection *SlashingProtection // Checks the votes before signing if set
+	load       voteKeyLoader       // Loads the keys again to reload them
+
+	lock    sync.RWMutex
+	km      voteKeymanager
+	keys    [][48]byte       // Active keys, the first one is the default
+	pending *pendingVoteKeys // Keys to switch to at the activation block
+}
+
+// voteKeyLoader loads the keymanager and the keys selected by the account names
+// or the hex encoded public keys. All the keys are selected by "*", and the
+// first key of the keymanager is selected if no selector is given.
+type voteKeyLoader func(selectors []string) (voteKeymanager, [][48]byte, error)
+
+// pendingVoteKeys is the keys reloaded to be activated at the block.
+type pendingVoteKeys struct {
+	km         voteKeymanager
+	keys       [][48]byte
+	activation uint64
+}
+
+// allVoteKeys is the selector of all the keys.
+const allVoteKeys = "*"
+
+// SplitVoteKeySelectors splits the comma separated account names or public keys.
+func SplitVoteKeySelectors(selectors string) []string {
+	var split []string
+	for _, selector := range strings.Split(selectors, ",") {
+		if selector = strings.TrimSpace(selector); selector != "" {
+			split = append(split, selector)
+		}
+	}
+	return split
+}
+
+// selectVoteKeys returns the keys selected by the account names or the hex
+// encoded public keys. The names may be nil if the keymanager has no name.
+func selectVoteKeys(pubKeys [][48]byte, names []string, selectors []string) ([][48]byte, []string) {
+	if len(selectors) == 0 {
+		return pubKeys[:1], nil
+	}
+	var (
+		selected [][48]byte
+		missing  []string
+	)
+	for _, selector := range selectors {
+		if selector == allVoteKeys {
+			return pubKeys, nil
+		}
+		found := false
+		for i, pubKey := range pubKeys {
+			if (i < len(names) && names[i] == selector) || strings.EqualFold(hexutil.Encode(pubKey[:]), selector) {
+				if !slices.Contains(selected, pubKey) {
+					selected = append(selected, pubKey)
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, selector)
+		}
+	}
+	return selected, missing
+}
+
+// voteKeymanager signs the vote with the BLS key, in process or by a remote signer.
+type voteKeymanager interface {
+	sign(ctx context.Context, pubKey [48]byte, data *types.VoteData) (bls.Signature, error)
+}
+
+// localKeymanager signs with the keys of the local Prysm wallet.
+type localKeymanager struct {
+	km keymanager.IKeymanager
 }
 
+func (l *localKeymanager) sign(ctx context.Context, pubKey [48]byte, data *types.VoteData) (bls.Signature, error) {
+	voteDataHash := data.Hash()
+	return l.km.Sign(ctx, &validatorpb.SignRequest{
+		PublicKey:   pubKey[:],
+		SigningRoot: voteDataHash[:],
+	})
+}
+
+// NewVoteSigner creates the vote signer using the keys of the local BLS wallet
+// selected by the comma separated account names or public keys.
 func NewVoteSigner(blsPasswordPath, blsWalletPath, blsAccountName string) (*VoteSigner, error) {
+	load := func(selectors []string) (voteKeymanager, [][48]byte, error) {
+		return loadLocalVoteKeys(blsPasswordPath, blsWalletPath, selectors)
+	}
+	km, keys, err := load(SplitVoteKeySelectors(blsAccountName))
+	if err != nil {
+		return nil, err
+	}
+	return &VoteSigner{
+		timeout: voteSignerTimeout,
+		load:    load,
+		km:      km,
+		keys:    keys,
+	}, nil
+}
+
+// loadLocalVoteKeys opens the local BLS wallet and returns the selected keys.
+func loadLocalVoteKeys(blsPasswordPath, blsWalletPath string, selectors []string) (voteKeymanager, [][48]byte, error) {
 	dirExists, err := wallet.Exists(blsWalletPath)
 	if err != nil {
 		log.Error("Check BLS wallet exists", "err", err)
-		return nil, err
+		return nil, nil, err
 	}
 	if !dirExists {
 		log.Error("BLS wallet did not exists.")
-		return nil, errors.New("BLS wallet did not exists")
+		return nil, nil, errors.New("BLS wallet did not exists")
 	}
 
 	walletPassword, err := os.ReadFile(blsPasswordPath)
 	if err != nil {
 		log.Error("Read BLS wallet password", "err", err)
-		return nil, err
+		return nil, nil, err
 	}
 	log.Info("Read BLS wallet password successfully")
 
@@ -54,14 +162,14 @@ func NewVoteSigner(blsPasswordPath, blsWalletPath, blsAccountName string) (*Vote
 	})
 	if err != nil {
 		log.Error("Open BLS wallet failed", "err", err)
-		return nil, err
+		return nil, nil, err
 	}
 	log.Info("Open BLS wallet successfully")
 
 	km, err := w.InitializeKeymanager(context.Background(), iface.InitKeymanagerConfig{ListenForChanges: false})
 	if err != nil {
 		log.Error("Initialize key manager failed", "err", err)
-		return nil, err
+		return nil, nil, err
 	}
 	log.Info("Initialized keymanager successfully")
 
@@ -70,61 +178,146 @@ func NewVoteSigner(blsPasswordPath, blsWalletPath, blsAccountName string) (*Vote
 
 	pubKeys, err := km.FetchValidatingPublicKeys(ctx)
 	if err != nil {
-		return nil, errors.Wrap(err, "could not fetch validating public keys")
+		return nil, nil, errors.Wrap(err, "could not fetch validating public keys")
 	}
 	if len(pubKeys) == 0 {
-		return nil, errors.New("no public keys in the BLS wallet")
+		return nil, nil, errors.New("no public keys in the BLS wallet")
 	}
 
-	// The default uses the first found key.
-	pubKey := pubKeys[0]
-
-	// If a key name is specified, find for it, but use the first key if not found.
-	if blsAccountName != "" {
+	var accountNames []string
+	if len(selectors) > 0 {
 		ikm, ok := km.(*local.Keymanager)
 		if !ok {
-			return nil, errors.New("could not assert BLS keymanager interface to concrete type")
+			return nil, nil, errors.New("could not assert BLS keymanager interface to concrete type")
 		}
-		accountNames, err := ikm.ValidatingAccountNames()
-		if err != nil {
-			return nil, errors.Wrap(err, "could not fetch BLS account names")
-		}
-		var found bool
-		for i := 0; i < len(accountNames) && !found; i++ {
-			found = accountNames[i] == blsAccountName
-			if found {
-				pubKey = pubKeys[i]
-			}
-		}
-		if !found {
-			log.Warn("Configured voting BLS public key was not found, so the default key will be used",
-				"configured", blsAccountName, "default", accountNames[0])
+		if accountNames, err = ikm.ValidatingAccountNames(); err != nil {
+			return nil, nil, errors.Wrap(err, "could not fetch BLS account names")
 		}
 	}
+	keys, missing := selectVoteKeys(pubKeys, accountNames, selectors)
+	if len(missing) > 0 {
+		log.Warn("Configured voting BLS public keys were not found", "configured", missing)
+	}
+	// Use the first key if none is found, as the key name was optional.
+	if len(keys) == 0 {
+		log.Warn("No configured voting BLS public key was found, so the default key will be used",
+			"default", accountNames[0])
+		keys = pubKeys[:1]
+	}
+	return &localKeymanager{km: km}, keys, nil
+}
 
-	return &VoteSigner{
-		km:     &km,
-		PubKey: pubKey,
-	}, nil
+// SetSlashingProtection enforces the slashing protection before signing the votes.
+func (signer *VoteSigner) SetSlashingProtection(protection *SlashingProtection) {
+	signer.protection = protection
+}
+
+// PubKey returns the default key, which is the first active key.
+func (signer *VoteSigner) PubKey() [48]byte {
+	signer.lock.RLock()
+	defer signer.lock.RUnlock()
+	return signer.keys[0]
+}
+
+// KeysAt returns the keys to vote for the target block, activating the pending
+// keys if the target reached their activation block.
+func (signer *VoteSigner) KeysAt(target uint64) [][48]byte {
+	signer.lock.Lock()
+	defer signer.lock.Unlock()
+
+	if pending := signer.pending; pending != nil && target >= pending.activation {
+		signer.km, signer.keys, signer.pending = pending.km, pending.keys, nil
+		log.Info("Switched the vote keys", "number", target, "keys", len(signer.keys), "default", hexutil.Encode(signer.keys[0][:]))
+	}
+	return slices.Clone(signer.keys)
+}
+
+// HasKey reports whether the key is active or pending.
+func (signer *VoteSigner) HasKey(pubKey [48]byte) bool {
+	signer.lock.RLock()
+	defer signer.lock.RUnlock()
+	return slices.Contains(signer.keys, pubKey) || (signer.pending != nil && slices.Contains(signer.pending.keys, pubKey))
+}
+
+// Reload loads the selected keys again from the wallet or the remote signer,
+// and switches to them at the activation block. They are switched immediately
+// if the activation block is 0.
+func (signer *VoteSigner) Reload(selectors []string, activation uint64) error {
+	if signer.load == nil {
+		return errors.New("vote keys can't be reloaded")
+	}
+	km, keys, err := signer.load(selectors)
+	if err != nil {
+		return err
+	}
+
+	signer.lock.Lock()
+	defer signer.lock.Unlock()
+	if activation == 0 {
+		signer.km, signer.keys, signer.pending = km, keys, nil
+		log.Info("Reloaded the vote keys", "keys", len(keys), "default", hexutil.Encode(keys[0][:]))
+	} else {
+		signer.pending = &pendingVoteKeys{km: km, keys: keys, activation: activation}
+		log.Info("Reloaded the vote keys to switch", "number", activation, "keys", len(keys), "default", hexutil.Encode(keys[0][:]))
+	}
+	return nil
+}
+
+// VoteKeysStatus is the active and the pending keys of the vote signer.
+type VoteKeysStatus struct {
+	Keys        []hexutil.Bytes `json:"keys"`
+	PendingKeys []hexutil.Bytes `json:"pendingKeys,omitempty"`
+	Activation  *hexutil.Uint64 `json:"activation,omitempty"`
 }
 
+// Status returns the active and the pending keys.
+func (signer *VoteSigner) Status() *VoteKeysStatus {
+	signer.lock.RLock()
+	defer signer.lock.RUnlock()
+
+	encode := func(keys [][48]byte) []hexutil.Bytes {
+		encoded := make([]hexutil.Bytes, len(keys))
+		for i, key := range keys {
+			encoded[i] = slices.Clone(key[:])
+		}
+		return encoded
+	}
+	status := &VoteKeysStatus{Keys: encode(signer.keys)}
+	if pending := signer.pending; pending != nil {
+		status.PendingKeys = encode(pending.keys)
+		status.Activation = (*hexutil.Uint64)(&pending.activation)
+	}
+	return status
+}
+
+// SignVote signs the vote with the default key for the target block.
 func (signer *VoteSigner) SignVote(vote *types.VoteEnvelope) error {
-	// Sign the vote, fetch the first pubKey as validator's bls public key.
-	pubKey := signer.PubKey
+	return signer.SignVoteWithKey(signer.KeysAt(vote.Data.TargetNumber)[0], vote)
+}
+
+// SignVoteWithKey signs the vote with the active key.
+func (signer *VoteSigner) SignVoteWithKey(pubKey [48]byte, vote *types.VoteEnvelope) error {
+	signer.lock.RLock()
+	km, active := signer.km, slices.Contains(signer.keys, pubKey)
+	signer.lock.RUnlock()
+	if !active {
+		return fmt.Errorf("vote key %s is not active", hexutil.Encode(pubKey[:]))
+	}
+
 	blsPubKey, err := bls.PublicKeyFromBytes(pubKey[:])
 	if err != nil {
 		return errors.Wrap(err, "convert public key from bytes to bls failed")
 	}
+	if signer.protection != nil {
+		if err := signer.protection.CheckAndRecord(pubKey, vote.Data); err != nil {
+			return err
+		}
+	}
 
-	voteDataHash := vote.Data.Hash()
-
-	ctx, cancel := context.WithTimeout(context.Background(), voteSignerTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), signer.timeout)
 	defer cancel()
 
-	signature, err := (*signer.km).Sign(ctx, &validatorpb.SignRequest{
-		PublicKey:   pubKey[:],
-		SigningRoot: voteDataHash[:],
-	})
+	signature, err := km.sign(ctx, pubKey, vote.Data)
 	if err != nil {
 		return err
 	}
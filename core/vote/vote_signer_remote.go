@@ -0,0 +1,302 @@
+package vote
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// Paths of the Web3Signer-compatible API.
+	remoteSignerPublicKeysPath = "/api/v1/eth2/publicKeys"
+	remoteSignerSignPath       = "/api/v1/eth2/sign/"
+
+	// Type of the signing request of the vote.
+	remoteSignerVoteType = "OASYS_VOTE"
+
+	// Maximum size of the response body of the remote signer.
+	maxRemoteSignerResponseSize = 64 * 1024
+)
+
+var (
+	// ErrRemoteSlashingProtection is returned if the remote signer refused to sign
+	// the vote by its slashing protection.
+	ErrRemoteSlashingProtection = errors.New("vote refused by the slashing protection of the remote signer")
+
+	// ErrRemoteKeyNotFound is returned if the remote signer doesn't hold the key.
+	ErrRemoteKeyNotFound = errors.New("BLS key not found in the remote signer")
+)
+
+// RemoteSignerConfig is the config of the remote signer, which serves the
+// Web3Signer-compatible HTTP API to sign the votes with the BLS keys it holds.
+type RemoteSignerConfig struct {
+	URL        string        // Base URL of the signer
+	PubKey     string        // Hex encoded BLS public key to vote with, the first key of the signer if empty
+	ClientCert string        // TLS client certificate file, for the signer requiring the client authentication
+	ClientKey  string        // TLS client key file
+	CACert     string        // CA certificate file to verify the signer, the system roots if empty
+	Timeout    time.Duration // Timeout of a request to the signer
+}
+
+// remoteVoteCheckpoint is a checkpoint of the vote in the signing request.
+type remoteVoteCheckpoint struct {
+	Number string      `json:"number"` // Decimal string like the epochs of Web3Signer
+	Hash   common.Hash `json:"hash"`
+}
+
+// remoteVote is the vote data in the signing request, which the signer
+// checks against its slashing protection database.
+type remoteVote struct {
+	Source remoteVoteCheckpoint `json:"source"`
+	Target remoteVoteCheckpoint `json:"target"`
+}
+
+// remoteSignRequest is the body of the signing request.
+type remoteSignRequest struct {
+	Type        string      `json:"type"`
+	SigningRoot common.Hash `json:"signingRoot"`
+	Vote        remoteVote  `json:"vote"`
+}
+
+// remoteSignResponse is the JSON body of the signing response.
+type remoteSignResponse struct {
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// remoteKeymanager signs the votes by the remote signer.
+type remoteKeymanager struct {
+	url    string
+	client *http.Client
+}
+
+// NewRemoteVoteSigner creates the vote signer using the BLS key held by the remote signer.
+func NewRemoteVoteSigner(config *RemoteSignerConfig) (*VoteSigner, error) {
+	if config.URL == "" {
+		return nil, errors.New("no remote signer URL")
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = voteSignerTimeout
+	}
+	tlsConfig, err := remoteSignerTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	km := &remoteKeymanager{
+		url:    strings.TrimRight(config.URL, "/"),
+		client: &http.Client{Transport: transport, Timeout: timeout},
+	}
+
+	log.Info("Connected to the remote BLS signer", "url", km.url)
+
+	load := func(selectors []string) (voteKeymanager, [][48]byte, error) {
+		return loadRemoteVoteKeys(km, timeout, selectors)
+	}
+	_, keys, err := load(SplitVoteKeySelectors(config.PubKey))
+	if err != nil {
+		return nil, err
+	}
+	return &VoteSigner{
+		timeout: timeout,
+		load:    load,
+		km:      km,
+		keys:    keys,
+	}, nil
+}
+
+// loadRemoteVoteKeys fetches the keys held by the remote signer and returns the
+// selected ones. Unlike the local wallet, all the selected keys must be held.
+func loadRemoteVoteKeys(km *remoteKeymanager, timeout time.Duration, selectors []string) (voteKeymanager, [][48]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	pubKeys, err := km.publicKeys(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not fetch public keys from the remote signer: %w", err)
+	}
+	if len(pubKeys) == 0 {
+		return nil, nil, errors.New("no public keys in the remote signer")
+	}
+	for _, selector := range selectors {
+		if selector == allVoteKeys {
+			continue
+		}
+		if raw, err := hexutil.Decode(selector); err != nil || len(raw) != len(pubKeys[0]) {
+			return nil, nil, fmt.Errorf("invalid BLS public key: %s", selector)
+		}
+	}
+	keys, missing := selectVoteKeys(pubKeys, nil, selectors)
+	if len(missing) > 0 {
+		return nil, nil, fmt.Errorf("%w: %s", ErrRemoteKeyNotFound, strings.Join(missing, ","))
+	}
+	log.Info("Loaded the keys of the remote BLS signer", "held", len(pubKeys), "selected", len(keys))
+	return km, keys, nil
+}
+
+// remoteSignerTLSConfig returns the TLS config to verify the signer and to
+// authenticate this client, or nil to use the default one.
+func remoteSignerTLSConfig(config *RemoteSignerConfig) (*tls.Config, error) {
+	if config.ClientCert == "" && config.ClientKey == "" && config.CACert == "" {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if config.ClientCert != "" || config.ClientKey != "" {
+		if config.ClientCert == "" || config.ClientKey == "" {
+			return nil, errors.New("both of the TLS client certificate and key are required")
+		}
+		cert, err := tls.LoadX509KeyPair(config.ClientCert, config.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not load TLS client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if config.CACert != "" {
+		pem, err := os.ReadFile(config.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("could not read CA certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", config.CACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+// publicKeys returns the BLS public keys held by the signer.
+func (r *remoteKeymanager) publicKeys(ctx context.Context) ([][48]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url+remoteSignerPublicKeysPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	body, _, err := r.do(req)
+	if err != nil {
+		return nil, err
+	}
+	var encoded []string
+	if err := json.Unmarshal(body, &encoded); err != nil {
+		return nil, fmt.Errorf("invalid public keys response: %w", err)
+	}
+	pubKeys := make([][48]byte, len(encoded))
+	for i, key := range encoded {
+		raw, err := hexutil.Decode(key)
+		if err != nil || len(raw) != len(pubKeys[i]) {
+			return nil, fmt.Errorf("invalid public key in response: %s", key)
+		}
+		copy(pubKeys[i][:], raw)
+	}
+	return pubKeys, nil
+}
+
+func (r *remoteKeymanager) sign(ctx context.Context, pubKey [48]byte, data *types.VoteData) (bls.Signature, error) {
+	signingRoot := data.Hash()
+	payload, err := json.Marshal(&remoteSignRequest{
+		Type:        remoteSignerVoteType,
+		SigningRoot: signingRoot,
+		Vote: remoteVote{
+			Source: remoteVoteCheckpoint{Number: strconv.FormatUint(data.SourceNumber, 10), Hash: data.SourceHash},
+			Target: remoteVoteCheckpoint{Number: strconv.FormatUint(data.TargetNumber, 10), Hash: data.TargetHash},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url+remoteSignerSignPath+hexutil.Encode(pubKey[:]), bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/plain")
+	body, contentType, err := r.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Web3Signer responds the hex signature as plain text unless JSON is preferred
+	var encoded []byte
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == "application/json" {
+		var res remoteSignResponse
+		if err := json.Unmarshal(body, &res); err != nil {
+			return nil, fmt.Errorf("invalid signing response: %w", err)
+		}
+		encoded = res.Signature
+	} else if encoded, err = hexutil.Decode(strings.TrimSpace(string(body))); err != nil {
+		return nil, fmt.Errorf("invalid signing response: %w", err)
+	}
+	signature, err := bls.SignatureFromBytes(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature from the remote signer: %w", err)
+	}
+
+	// Don't trust the signer blindly, the invalid vote is rejected by the peers
+	blsPubKey, err := bls.PublicKeyFromBytes(pubKey[:])
+	if err != nil {
+		return nil, err
+	}
+	if !signature.Verify(blsPubKey, signingRoot[:]) {
+		return nil, errors.New("signature from the remote signer does not match the public key")
+	}
+	return signature, nil
+}
+
+// do sends the request, returning the body and the content type of the successful response.
+func (r *remoteKeymanager) do(req *http.Request) ([]byte, string, error) {
+	res, err := r.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxRemoteSignerResponseSize))
+	if err != nil {
+		return nil, "", err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, "", &remoteSignerError{status: res.StatusCode, message: string(bytes.TrimSpace(body))}
+	}
+	return body, res.Header.Get("Content-Type"), nil
+}
+
+// remoteSignerError is the error response of the remote signer.
+type remoteSignerError struct {
+	status  int
+	message string
+}
+
+func (e *remoteSignerError) Error() string {
+	return fmt.Sprintf("remote signer responded %d %s: %s", e.status, http.StatusText(e.status), e.message)
+}
+
+// Unwrap maps the status of the signing response to the error.
+func (e *remoteSignerError) Unwrap() error {
+	switch e.status {
+	case http.StatusNotFound:
+		return ErrRemoteKeyNotFound
+	case http.StatusPreconditionFailed:
+		return ErrRemoteSlashingProtection
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,205 @@
+package vote
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// testRemoteSigner is a stand-in of the Web3Signer-compatible signer, refusing
+// the votes which don't move the target forward.
+type testRemoteSigner struct {
+	key   bls.SecretKey
+	delay time.Duration
+
+	mu         sync.Mutex
+	lastTarget uint64
+}
+
+func (s *testRemoteSigner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	time.Sleep(s.delay)
+	pubKey := hexutil.Encode(s.key.PublicKey().Marshal())
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == remoteSignerPublicKeysPath:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]string{pubKey})
+	case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, remoteSignerSignPath):
+		if strings.TrimPrefix(r.URL.Path, remoteSignerSignPath) != pubKey {
+			http.Error(w, "unknown key", http.StatusNotFound)
+			return
+		}
+		var req remoteSignRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Type != remoteSignerVoteType {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		source, _ := strconv.ParseUint(req.Vote.Source.Number, 10, 64)
+		target, _ := strconv.ParseUint(req.Vote.Target.Number, 10, 64)
+		data := &types.VoteData{SourceNumber: source, SourceHash: req.Vote.Source.Hash, TargetNumber: target, TargetHash: req.Vote.Target.Hash}
+		if data.Hash() != req.SigningRoot {
+			http.Error(w, "signing root mismatch", http.StatusBadRequest)
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if target <= s.lastTarget || source >= target {
+			http.Error(w, "slashable", http.StatusPreconditionFailed)
+			return
+		}
+		s.lastTarget = target
+
+		signature := hexutil.Encode(s.key.Sign(req.SigningRoot[:]).Marshal())
+		if strings.HasPrefix(r.Header.Get("Accept"), "application/json") {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]string{"signature": signature})
+		} else {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte(signature))
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// writeTestCert issues a certificate signed by the parent, or a self-signed one
+// if the parent is nil, and writes the PEM encoded certificate and key.
+func writeTestCert(t *testing.T, dir, name string, template *x509.Certificate, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	keyDer, _ := x509.MarshalECPrivateKey(key)
+	os.WriteFile(filepath.Join(dir, name+".crt"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	os.WriteFile(filepath.Join(dir, name+".key"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)
+	return cert, key
+}
+
+func TestRemoteVoteSigner(t *testing.T) {
+	dir := t.TempDir()
+	notAfter := time.Now().Add(time.Hour)
+	ca, caKey := writeTestCert(t, dir, "ca", &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil, nil)
+	writeTestCert(t, dir, "server", &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "signer"},
+		NotAfter:     notAfter,
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca, caKey)
+	writeTestCert(t, dir, "client", &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "validator"},
+		NotAfter:     notAfter,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca, caKey)
+
+	secretKey, err := bls.RandKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stand := &testRemoteSigner{key: secretKey}
+	server := httptest.NewUnstartedServer(stand)
+	serverCert, err := tls.LoadX509KeyPair(filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca)
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	config := &RemoteSignerConfig{
+		URL:        server.URL + "/",
+		ClientCert: filepath.Join(dir, "client.crt"),
+		ClientKey:  filepath.Join(dir, "client.key"),
+		CACert:     filepath.Join(dir, "ca.crt"),
+		Timeout:    time.Second,
+	}
+
+	// The signer requires the client certificate
+	if _, err := NewRemoteVoteSigner(&RemoteSignerConfig{URL: server.URL, CACert: config.CACert}); err == nil {
+		t.Fatal("connected without client certificate")
+	}
+	// The configured key must be held by the signer
+	other, _ := bls.RandKey()
+	if _, err := NewRemoteVoteSigner(&RemoteSignerConfig{URL: config.URL, ClientCert: config.ClientCert, ClientKey: config.ClientKey, CACert: config.CACert, PubKey: hexutil.Encode(other.PublicKey().Marshal())}); !errors.Is(err, ErrRemoteKeyNotFound) {
+		t.Fatalf("err = %v, want %v", err, ErrRemoteKeyNotFound)
+	}
+
+	signer, err := NewRemoteVoteSigner(config)
+	if err != nil {
+		t.Fatalf("failed to create remote signer: %v", err)
+	}
+	if signer.PubKey() != [48]byte(secretKey.PublicKey().Marshal()) {
+		t.Fatalf("public key mismatch, got %x", signer.PubKey())
+	}
+
+	vote := &types.VoteEnvelope{Data: &types.VoteData{SourceNumber: 1, SourceHash: common.HexToHash("0x01"), TargetNumber: 2, TargetHash: common.HexToHash("0x02")}}
+	if err := signer.SignVote(vote); err != nil {
+		t.Fatalf("failed to sign vote: %v", err)
+	}
+	if err := vote.Verify(); err != nil {
+		t.Fatalf("invalid signature: %v", err)
+	}
+
+	// The slashing protection of the signer refuses the double vote
+	double := &types.VoteEnvelope{Data: &types.VoteData{SourceNumber: 1, SourceHash: common.HexToHash("0x01"), TargetNumber: 2, TargetHash: common.HexToHash("0x03")}}
+	if err := signer.SignVote(double); !errors.Is(err, ErrRemoteSlashingProtection) {
+		t.Fatalf("err = %v, want %v", err, ErrRemoteSlashingProtection)
+	}
+
+	// The signer is given up after the timeout
+	stand.delay = 2 * config.Timeout
+	next := &types.VoteEnvelope{Data: &types.VoteData{SourceNumber: 2, SourceHash: common.HexToHash("0x02"), TargetNumber: 3, TargetHash: common.HexToHash("0x03")}}
+	start := time.Now()
+	if err := signer.SignVote(next); err == nil {
+		t.Fatal("signed after timeout")
+	}
+	if elapsed := time.Since(start); elapsed >= stand.delay {
+		t.Fatalf("timeout not applied, elapsed %v", elapsed)
+	}
+}
@@ -0,0 +1,110 @@
+package vote
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// testKeymanager signs with the named keys held in memory.
+type testKeymanager struct {
+	names   []string
+	secrets []bls.SecretKey
+}
+
+func newTestKeymanager(t *testing.T, names ...string) *testKeymanager {
+	km := &testKeymanager{names: names}
+	for range names {
+		secret, err := bls.RandKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		km.secrets = append(km.secrets, secret)
+	}
+	return km
+}
+
+func (km *testKeymanager) pubKeys() [][48]byte {
+	pubKeys := make([][48]byte, len(km.secrets))
+	for i, secret := range km.secrets {
+		pubKeys[i] = [48]byte(secret.PublicKey().Marshal())
+	}
+	return pubKeys
+}
+
+func (km *testKeymanager) load(selectors []string) (voteKeymanager, [][48]byte, error) {
+	keys, missing := selectVoteKeys(km.pubKeys(), km.names, selectors)
+	if len(missing) > 0 {
+		return nil, nil, ErrRemoteKeyNotFound
+	}
+	return km, keys, nil
+}
+
+func (km *testKeymanager) sign(ctx context.Context, pubKey [48]byte, data *types.VoteData) (bls.Signature, error) {
+	for i, key := range km.pubKeys() {
+		if key == pubKey {
+			hash := data.Hash()
+			return km.secrets[i].Sign(hash[:]), nil
+		}
+	}
+	return nil, ErrRemoteKeyNotFound
+}
+
+func TestVoteSignerKeys(t *testing.T) {
+	km := newTestKeymanager(t, "a", "b", "c")
+	pubKeys := km.pubKeys()
+	_, keys, _ := km.load(SplitVoteKeySelectors(" b, " + hexutil.Encode(pubKeys[2][:]) + ",b"))
+	signer := &VoteSigner{timeout: voteSignerTimeout, load: km.load, km: km, keys: keys}
+
+	if signer.PubKey() != pubKeys[1] || !signer.HasKey(pubKeys[2]) || signer.HasKey(pubKeys[0]) {
+		t.Fatalf("unexpected keys: %x", signer.KeysAt(0))
+	}
+
+	// Each active key can sign, the others can't
+	vote := &types.VoteEnvelope{Data: testVoteData(1, 2)}
+	if err := signer.SignVoteWithKey(pubKeys[2], vote); err != nil {
+		t.Fatal(err)
+	}
+	if vote.VoteAddress != pubKeys[2] || vote.Verify() != nil {
+		t.Fatalf("invalid vote by %x", vote.VoteAddress)
+	}
+	if err := signer.SignVoteWithKey(pubKeys[0], &types.VoteEnvelope{Data: testVoteData(1, 2)}); err == nil {
+		t.Fatal("signed by the inactive key")
+	}
+
+	// The reloaded keys are switched at the activation
+	if err := signer.Reload([]string{"unknown"}, 10); !errors.Is(err, ErrRemoteKeyNotFound) {
+		t.Fatalf("err = %v, want %v", err, ErrRemoteKeyNotFound)
+	}
+	if err := signer.Reload([]string{"a"}, 10); err != nil {
+		t.Fatal(err)
+	}
+	if status := signer.Status(); len(status.Keys) != 2 || len(status.PendingKeys) != 1 || uint64(*status.Activation) != 10 {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+	if !signer.HasKey(pubKeys[0]) {
+		t.Fatal("pending key is not held")
+	}
+	if keys := signer.KeysAt(9); len(keys) != 2 {
+		t.Fatalf("switched before the activation: %x", keys)
+	}
+	if err := signer.SignVote(&types.VoteEnvelope{Data: testVoteData(8, 10)}); err != nil {
+		t.Fatal(err)
+	}
+	if signer.PubKey() != pubKeys[0] || signer.HasKey(pubKeys[1]) || signer.Status().PendingKeys != nil {
+		t.Fatalf("not switched at the activation: %x", signer.KeysAt(10))
+	}
+
+	// All the keys are switched immediately
+	if err := signer.Reload([]string{allVoteKeys}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if keys := signer.KeysAt(0); len(keys) != 3 {
+		t.Fatalf("unexpected keys: %x", keys)
+	}
+}
@@ -0,0 +1,49 @@
+// Package voteconfig implements the configuration of the vote pool.
+package voteconfig
+
+import (
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Config are the configuration parameters of the vote pool. The votes kept per
+// block are at least the number of the active validators, and twice of it for
+// the blocks not verified yet, so that no legitimate vote is dropped on the
+// networks with many validators.
+type Config struct {
+	CurVotesPerBlock    uint64 // Minimum number of votes kept per verified block
+	FutureVotesPerBlock uint64 // Minimum number of votes kept per block not verified yet
+	PruneDistance       uint64 // Number of blocks behind the head whose votes are kept
+	FutureDistance      uint64 // Number of blocks ahead of the head whose votes are accepted
+}
+
+// DefaultConfig contains the default configurations for the vote pool, which
+// keeps the votes in the range (head-256, head+11].
+var DefaultConfig = Config{
+	CurVotesPerBlock:    50,
+	FutureVotesPerBlock: 100,
+	PruneDistance:       256,
+	FutureDistance:      11, // refer to fetcher.maxUncleDist
+}
+
+// Sanitize checks the provided user configurations and changes anything that's
+// unreasonable or unworkable.
+func (config *Config) Sanitize() Config {
+	conf := *config
+	if conf.CurVotesPerBlock < 1 {
+		log.Warn("Sanitizing invalid votepool current votes", "provided", conf.CurVotesPerBlock, "updated", DefaultConfig.CurVotesPerBlock)
+		conf.CurVotesPerBlock = DefaultConfig.CurVotesPerBlock
+	}
+	if conf.FutureVotesPerBlock < 1 {
+		log.Warn("Sanitizing invalid votepool future votes", "provided", conf.FutureVotesPerBlock, "updated", DefaultConfig.FutureVotesPerBlock)
+		conf.FutureVotesPerBlock = DefaultConfig.FutureVotesPerBlock
+	}
+	if conf.PruneDistance < 1 {
+		log.Warn("Sanitizing invalid votepool prune distance", "provided", conf.PruneDistance, "updated", DefaultConfig.PruneDistance)
+		conf.PruneDistance = DefaultConfig.PruneDistance
+	}
+	if conf.FutureDistance < 1 {
+		log.Warn("Sanitizing invalid votepool future distance", "provided", conf.FutureDistance, "updated", DefaultConfig.FutureDistance)
+		conf.FutureDistance = DefaultConfig.FutureDistance
+	}
+	return conf
+}
@@ -25,7 +25,9 @@ import (
 	"strings"
 
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/standby"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/protocols/bsc"
 	"github.com/ethereum/go-ethereum/rlp"
 )
 
@@ -141,3 +143,18 @@ func (api *AdminAPI) ImportChain(file string) (bool, error) {
 	}
 	return true, nil
 }
+
+// VotePeerScores returns the vote protocol reputations of the peers which have
+// recently sent the invalid or spamming votes.
+func (api *AdminAPI) VotePeerScores() []*bsc.PeerScore {
+	return api.eth.handler.voteScores.Scores()
+}
+
+// StandbyStatus returns whether the standby validator node holds the lease and
+// is allowed to seal and vote.
+func (api *AdminAPI) StandbyStatus() (*standby.Status, error) {
+	if api.eth.standby == nil {
+		return nil, errors.New("standby validator is not enabled")
+	}
+	return api.eth.standby.Status(), nil
+}
@@ -17,11 +17,13 @@
 package eth
 
 import (
+	"errors"
 	"math/big"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/miner"
 )
 
 // MinerAPI provides an API to control the miner.
@@ -84,3 +86,13 @@ func (api *MinerAPI) SetEtherbase(etherbase common.Address) bool {
 func (api *MinerAPI) SetRecommitInterval(interval int) {
 	api.e.Miner().SetRecommitInterval(time.Duration(interval) * time.Millisecond)
 }
+
+// SlotHealth returns the readiness of the validator for its upcoming in-turn
+// slots, and the missed slots of the epoch counted against the jail threshold.
+func (api *MinerAPI) SlotHealth() (*miner.SlotHealth, error) {
+	health := api.e.Miner().SlotHealth()
+	if health == nil {
+		return nil, errors.New("slot health is unknown, the etherbase is not set or the engine is not oasys")
+	}
+	return health, nil
+}
@@ -0,0 +1,316 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/oasys"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+const (
+	// Number of accounts scanned by a single `oasys_listBlockedAddresses` call by default.
+	defaultBlockedAddressScan = 100_000
+	// Maximum number of accounts scanned by a single `oasys_listBlockedAddresses` call.
+	maxBlockedAddressScan = 1_000_000
+)
+
+// OasysAPI provides the Oasys-specific access-control and transaction-blocker state.
+type OasysAPI struct {
+	eth *Ethereum
+}
+
+// NewOasysAPI creates a new OasysAPI instance.
+func NewOasysAPI(eth *Ethereum) *OasysAPI {
+	return &OasysAPI{eth: eth}
+}
+
+// OasysCheckResult is the outcome of an Oasys-only transaction check.
+type OasysCheckResult struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// CheckTransactionResult reports whether the transaction passes the Oasys-only
+// checks of the transaction pool at the block, and the reason of each check.
+type CheckTransactionResult struct {
+	BlockNumber hexutil.Uint64      `json:"blockNumber"`
+	BlockHash   common.Hash         `json:"blockHash"`
+	TxHash      common.Hash         `json:"txHash"`
+	Sender      common.Address      `json:"sender"`
+	Allowed     bool                `json:"allowed"`
+	Checks      []*OasysCheckResult `json:"checks"`
+}
+
+// CheckTransaction runs the Oasys-only checks against the signed transaction at
+// the block, defaulting to the latest one. Unlike the transaction pool, every
+// check is reported instead of the first failure only.
+func (api *OasysAPI) CheckTransaction(ctx context.Context, input hexutil.Bytes, blockNrOrHash *rpc.BlockNumberOrHash) (*CheckTransactionResult, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return nil, err
+	}
+	statedb, header, err := api.stateAt(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	// Recover the sender by the rules of the block, as the pool did at the time
+	signer := types.MakeSigner(api.eth.blockchain.Config(), header.Number, header.Time)
+	sender, err := types.Sender(signer, tx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", txpool.ErrInvalidSender, err)
+	}
+
+	result := &CheckTransactionResult{
+		BlockNumber: hexutil.Uint64(header.Number.Uint64()),
+		BlockHash:   header.Hash(),
+		TxHash:      tx.Hash(),
+		Sender:      sender,
+		Allowed:     true,
+	}
+	for _, check := range txpool.CheckTransactionWithOasysState(tx, sender, statedb) {
+		checkResult := &OasysCheckResult{Name: check.Name, Passed: check.Err == nil}
+		if check.Err != nil {
+			checkResult.Reason = check.Err.Error()
+			result.Allowed = false
+		}
+		result.Checks = append(result.Checks, checkResult)
+	}
+	return result, nil
+}
+
+// BlockedAddressesResult is a page of the addresses blocked by the `TransactionBlocker` contract.
+type BlockedAddressesResult struct {
+	BlockNumber      hexutil.Uint64   `json:"blockNumber"`
+	BlockHash        common.Hash      `json:"blockHash"`
+	BlockAll         bool             `json:"blockAll"`
+	Addresses        []common.Address `json:"addresses"`
+	Scanned          hexutil.Uint64   `json:"scanned"`          // Number of accounts scanned by this call
+	MissingPreimages hexutil.Uint64   `json:"missingPreimages"` // Number of scanned accounts without the address preimage
+	Next             *hexutil.Bytes   `json:"next"`             // Hashed account key to continue scanning from, nil if done
+}
+
+// ListBlockedAddresses lists the addresses in the `isBlockedAddress` mapping of
+// the `TransactionBlocker` contract at the block, defaulting to the latest one.
+//
+// The mapping keys are hashed, so the addresses are resolved by scanning the
+// accounts of the state and matching their storage keys. This requires the
+// preimages of the account keys (--cache.preimages), and can't find the blocked
+// addresses which have no account. The scan is paginated by the `next` cursor.
+func (api *OasysAPI) ListBlockedAddresses(ctx context.Context, blockNrOrHash *rpc.BlockNumberOrHash, start *hexutil.Bytes, maxScan *hexutil.Uint64) (*BlockedAddressesResult, error) {
+	limit := uint64(defaultBlockedAddressScan)
+	if maxScan != nil {
+		if *maxScan == 0 || *maxScan > maxBlockedAddressScan {
+			return nil, fmt.Errorf("maxScan must be between 1 and %d", maxBlockedAddressScan)
+		}
+		limit = uint64(*maxScan)
+	}
+	statedb, header, err := api.stateAt(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BlockedAddressesResult{
+		BlockNumber: hexutil.Uint64(header.Number.Uint64()),
+		BlockHash:   header.Hash(),
+		BlockAll:    vm.IsBlockedAll(statedb),
+		Addresses:   []common.Address{},
+	}
+	slots, err := nonEmptyStorageKeys(statedb, header.Root, vm.TransactionBlockerContract)
+	if err != nil {
+		return nil, err
+	}
+	if len(slots) == 0 {
+		return result, nil // nothing has been blocked
+	}
+
+	tr, err := trie.NewStateTrie(trie.StateTrieID(header.Root), statedb.Database().TrieDB())
+	if err != nil {
+		return nil, err
+	}
+	var seek []byte
+	if start != nil {
+		seek = *start
+	}
+	nodeIt, err := tr.NodeIterator(seek)
+	if err != nil {
+		return nil, err
+	}
+	it := trie.NewIterator(nodeIt)
+	for ; uint64(result.Scanned) < limit && it.Next(); result.Scanned++ {
+		if result.Scanned%10_000 == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		preimage := tr.GetKey(it.Key)
+		if preimage == nil {
+			result.MissingPreimages++
+			continue
+		}
+		address := common.BytesToAddress(preimage)
+		if slots[crypto.Keccak256Hash(vm.BlockedAddressStorageKey(address).Bytes())] {
+			result.Addresses = append(result.Addresses, address)
+		}
+	}
+	if it.Err != nil {
+		return nil, it.Err
+	}
+	if it.Next() {
+		next := hexutil.Bytes(common.CopyBytes(it.Key))
+		result.Next = &next
+	}
+	return result, nil
+}
+
+// GetBlockStats returns the local observation of the block and its votes, or
+// nil if the block is not recent enough to be recorded.
+func (api *OasysAPI) GetBlockStats(hash common.Hash) *BlockStatsResult {
+	stats := api.eth.blockchain.PeekBlockStats(hash)
+	if stats == nil {
+		return nil
+	}
+	return newBlockStatsResult(stats)
+}
+
+// GetFinalityReport reports the vote propagation and the finality of the
+// canonical blocks in the range, including which validators' votes are missing
+// from the attestation of each block.
+func (api *OasysAPI) GetFinalityReport(ctx context.Context, from, to rpc.BlockNumber) ([]*BlockFinalityReport, error) {
+	start, err := api.eth.APIBackend.HeaderByNumber(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	end, err := api.eth.APIBackend.HeaderByNumber(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+	if start == nil || end == nil {
+		return nil, errors.New("block not found")
+	}
+	first, last := start.Number.Uint64(), end.Number.Uint64()
+	if first > last {
+		return nil, fmt.Errorf("invalid range %d-%d", first, last)
+	}
+	if last-first >= maxFinalityReportRange {
+		return nil, fmt.Errorf("range exceeds %d blocks", maxFinalityReportRange)
+	}
+
+	reports := make([]*BlockFinalityReport, 0, last-first+1)
+	for number := first; number <= last; number++ {
+		header := api.eth.blockchain.GetHeaderByNumber(number)
+		if header == nil {
+			break
+		}
+		report, err := api.eth.newBlockFinalityReport(header)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// GetVoteParticipation returns the number of the blocks each validator voted
+// for, voted for too late, or missed in the epochs (inclusive), defaulting to
+// the epoch of the last indexed block. It requires the vote participation
+// indexer (--monitor.voteparticipation).
+func (api *OasysAPI) GetVoteParticipation(fromEpoch, toEpoch *hexutil.Uint64) ([]*EpochVoteParticipation, error) {
+	var from, to uint64
+	if toEpoch != nil {
+		to = uint64(*toEpoch)
+	} else {
+		engine, ok := api.eth.engine.(*oasys.Oasys)
+		if !ok {
+			return nil, errors.New("vote participation is not supported by the consensus engine")
+		}
+		last := rawdb.ReadVoteParticipationHead(api.eth.chainDb)
+		if last == nil {
+			return nil, errors.New("vote participation is not indexed")
+		}
+		header := api.eth.blockchain.GetHeaderByNumber(*last)
+		if header == nil {
+			return nil, errors.New("last indexed block not found")
+		}
+		env, err := engine.EnvironmentAt(api.eth.blockchain, header)
+		if err != nil {
+			return nil, err
+		}
+		to = env.Epoch(*last)
+	}
+	from = to
+	if fromEpoch != nil {
+		from = uint64(*fromEpoch)
+	}
+	if from > to {
+		return nil, fmt.Errorf("invalid range %d-%d", from, to)
+	}
+	if to-from >= maxVoteParticipationRange {
+		return nil, fmt.Errorf("range exceeds %d epochs", maxVoteParticipationRange)
+	}
+
+	participations := []*EpochVoteParticipation{}
+	rawdb.IterateVoteParticipationRLP(api.eth.chainDb, from, to, func(epoch uint64, data rlp.RawValue) bool {
+		participation := new(EpochVoteParticipation)
+		if err := rlp.DecodeBytes(data, participation); err != nil {
+			log.Error("Invalid vote participation RLP", "epoch", epoch, "err", err)
+			return true
+		}
+		participations = append(participations, participation)
+		return true
+	})
+	return participations, nil
+}
+
+// stateAt returns the state and the header of the block, defaulting to the latest one.
+func (api *OasysAPI) stateAt(ctx context.Context, blockNrOrHash *rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error) {
+	if api.eth.blockchain.Config().Oasys == nil {
+		return nil, nil, errors.New("not an oasys chain")
+	}
+	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	if blockNrOrHash != nil {
+		bNrOrHash = *blockNrOrHash
+	}
+	statedb, header, err := api.eth.APIBackend.StateAndHeaderByNumberOrHash(ctx, bNrOrHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if statedb == nil || header == nil {
+		return nil, nil, errors.New("state not found")
+	}
+	return statedb, header, nil
+}
+
+// nonEmptyStorageKeys returns the hashed storage keys of the contract holding a non-zero value.
+func nonEmptyStorageKeys(statedb *state.StateDB, root common.Hash, address common.Address) (map[common.Hash]bool, error) {
+	storageRoot := statedb.GetStorageRoot(address)
+	if storageRoot == types.EmptyRootHash || storageRoot == (common.Hash{}) {
+		return nil, nil
+	}
+	id := trie.StorageTrieID(root, crypto.Keccak256Hash(address.Bytes()), storageRoot)
+	tr, err := trie.NewStateTrie(id, statedb.Database().TrieDB())
+	if err != nil {
+		return nil, err
+	}
+	nodeIt, err := tr.NodeIterator(nil)
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[common.Hash]bool)
+	it := trie.NewIterator(nodeIt)
+	for it.Next() {
+		keys[common.BytesToHash(it.Key)] = true // zero values are deleted from the trie
+	}
+	return keys, it.Err
+}
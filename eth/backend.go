@@ -18,6 +18,7 @@
 package eth
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
@@ -25,6 +26,7 @@ import (
 	"math"
 	"math/big"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
@@ -39,6 +41,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/filtermaps"
 	"github.com/ethereum/go-ethereum/core/monitor"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/standby"
 	"github.com/ethereum/go-ethereum/core/state/pruner"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
@@ -55,6 +58,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
 	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
@@ -141,8 +145,16 @@ type Ethereum struct {
 
 	shutdownTracker *shutdowncheck.ShutdownTracker // Tracks if and when the node has shutdown ungracefully
 
-	votePool *vote.VotePool
-	stopCh   chan struct{}
+	votePool              *vote.VotePool
+	evidenceStore         *monitor.EvidenceStore
+	maliciousVoteReporter *monitor.MaliciousVoteReporter
+	slashingProtection    *vote.SlashingProtection
+	sidecarArchive        *rawdb.SidecarArchive
+	voteManager           *vote.VoteManager
+	standby               *standby.Manager
+	finalityReporter      *finalityReporter
+	participationIndexer  *voteParticipationIndexer
+	stopCh                chan struct{}
 }
 
 // New creates a new Ethereum object (including the initialisation of the common Ethereum object),
@@ -219,11 +231,25 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		overrides.OverrideVerkle = config.OverrideVerkle
 	}
 
+	// Open the archive retaining the blob sidecars pruned by the freezer
+	var (
+		sidecarArchive  *rawdb.SidecarArchive
+		sidecarArchiver ethdb.SidecarArchiver
+	)
+	if config.BlobArchive != "" {
+		if sidecarArchive, err = rawdb.NewSidecarArchive(config.BlobArchive, false); err != nil {
+			return nil, fmt.Errorf("failed to open blob sidecar archive: %v", err)
+		}
+		sidecarArchiver = sidecarArchive
+		first, next := sidecarArchive.Range()
+		log.Info("Opened blob sidecar archive", "dir", config.BlobArchive, "first", first, "next", next)
+	}
 	// startup ancient freeze
 	freezeDb := chainDb
 	if err = freezeDb.SetupFreezerEnv(&ethdb.FreezerEnv{
 		ChainCfg:         chainConfig,
 		BlobExtraReserve: config.BlobExtraReserve,
+		SidecarArchive:   sidecarArchiver,
 	}, config.BlockHistory); err != nil {
 		return nil, err
 	}
@@ -245,6 +271,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		p2pServer:       stack.Server(),
 		discmix:         enode.NewFairMix(discmixTimeout),
 		shutdownTracker: shutdowncheck.NewShutdownTracker(chainDb),
+		sidecarArchive:  sidecarArchive,
 		stopCh:          make(chan struct{}),
 	}
 
@@ -322,9 +349,15 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		options.VmConfig.Tracer = t
 	}
 
+	if stack.Config().EnableDoubleSignMonitor || stack.Config().EnableMaliciousVoteMonitor {
+		eth.evidenceStore = monitor.NewEvidenceStore(chainDb)
+	}
 	bcOps := make([]core.BlockChainOption, 0)
 	if stack.Config().EnableDoubleSignMonitor {
-		bcOps = append(bcOps, core.EnableDoubleSignChecker)
+		bcOps = append(bcOps, core.EnableDoubleSignChecker, core.WithDoubleSignEvidenceStore(eth.evidenceStore))
+	}
+	if eth.sidecarArchive != nil {
+		bcOps = append(bcOps, core.WithSidecarArchive(eth.sidecarArchive))
 	}
 	options.Overrides = &overrides
 	eth.blockchain, err = core.NewBlockChain(chainDb, config.Genesis, eth.engine, options, bcOps...)
@@ -394,6 +427,8 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		DirectBroadcast:        config.DirectBroadcast,
 		DisablePeerTxBroadcast: config.DisablePeerTxBroadcast,
 		PeerSet:                newPeerSet(),
+		BanPeer:                eth.p2pServer.BanPeer,
+		BlobExtraReserve:       config.BlobExtraReserve,
 	}); err != nil {
 		return nil, err
 	}
@@ -403,11 +438,15 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	eth.miner = miner.New(eth, &config.Miner, eth.EventMux(), eth.engine, stack.DataDir())
 	eth.miner.SetExtra(makeExtraData(config.Miner.ExtraData))
 	eth.miner.SetPrioAddresses(config.TxPool.Locals)
+	eth.miner.SetPriorityLanes(config.TxPool.Lanes)
+	if handler := eth.miner.SlotHealthHandler(); handler != nil {
+		stack.RegisterHandler("Validator health", "/validator/health", handler)
+	}
 
 	// Create voteManager instance
 	if pos, ok := eth.engine.(consensus.PoS); ok {
 		// Create votePool instance
-		votePool := vote.NewVotePool(eth.blockchain, pos)
+		votePool := vote.NewVotePool(config.VotePool, eth.blockchain, pos)
 		eth.votePool = votePool
 		if oasys, ok := eth.engine.(*oasys.Oasys); ok {
 			if !config.Miner.DisableVoteAttestation {
@@ -421,21 +460,78 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		eth.handler.votepool = votePool
 		if stack.Config().EnableMaliciousVoteMonitor {
 			eth.handler.maliciousVoteMonitor = monitor.NewMaliciousVoteMonitor()
+			eth.handler.maliciousVoteMonitor.SetEvidenceStore(eth.evidenceStore)
 			log.Info("Create MaliciousVoteMonitor successfully")
+
+			if reporter := stack.Config().MaliciousVoteReporter; reporter != "" {
+				if !common.IsHexAddress(reporter) {
+					return nil, fmt.Errorf("invalid malicious vote reporter: %s", reporter)
+				}
+				if eth.maliciousVoteReporter, err = eth.newMaliciousVoteReporter(stack, common.HexToAddress(reporter)); err != nil {
+					return nil, err
+				}
+				eth.handler.maliciousVoteMonitor.SetReporter(eth.maliciousVoteReporter)
+				log.Info("Create MaliciousVoteReporter successfully", "account", reporter)
+			}
+		}
+
+		if stack.Config().EnableFinalityReporter {
+			eth.finalityReporter = newFinalityReporter(eth)
+			log.Info("Create FinalityReporter successfully")
+		}
+		if stack.Config().EnableVoteParticipationIndexer {
+			if eth.participationIndexer, err = newVoteParticipationIndexer(eth); err != nil {
+				return nil, err
+			}
+			log.Info("Create VoteParticipationIndexer successfully")
 		}
 
 		if config.Miner.VoteEnable {
 			conf := stack.Config()
-			blsPasswordPath := stack.ResolvePath(conf.BLSPasswordFile)
-			blsWalletPath := stack.ResolvePath(conf.BLSWalletDir)
-			blsAccountName := conf.VoteKeyName
+			var voteSigner *vote.VoteSigner
+			if conf.BLSRemoteSigner != "" {
+				voteSigner, err = vote.NewRemoteVoteSigner(&vote.RemoteSignerConfig{
+					URL:        conf.BLSRemoteSigner,
+					PubKey:     conf.BLSRemoteSignerPubKey,
+					ClientCert: resolveOptionalPath(stack, conf.BLSRemoteSignerClientCert),
+					ClientKey:  resolveOptionalPath(stack, conf.BLSRemoteSignerClientKey),
+					CACert:     resolveOptionalPath(stack, conf.BLSRemoteSignerCACert),
+					Timeout:    conf.BLSRemoteSignerTimeout,
+				})
+			} else {
+				blsPasswordPath := stack.ResolvePath(conf.BLSPasswordFile)
+				blsWalletPath := stack.ResolvePath(conf.BLSWalletDir)
+				voteSigner, err = vote.NewVoteSigner(blsPasswordPath, blsWalletPath, conf.VoteKeyName)
+			}
+			if err != nil {
+				log.Error("Failed to Initialize voteSigner", "err", err)
+				return nil, err
+			}
+			log.Info("Create voteSigner successfully", "remote", conf.BLSRemoteSigner != "")
+			slashingProtectionPath := stack.ResolvePath(cmp.Or(conf.SlashingProtectionDir, vote.SlashingProtectionDatabase))
+			if eth.slashingProtection, err = vote.OpenSignerSlashingProtection(slashingProtectionPath, stack.ResolvePath(vote.SlashingProtectionMarker)); err != nil {
+				return nil, err
+			}
+			voteSigner.SetSlashingProtection(eth.slashingProtection)
 			voteJournalPath := stack.ResolvePath(conf.VoteJournalDir)
-			if _, err := vote.NewVoteManager(eth, eth.blockchain, votePool, voteJournalPath, blsPasswordPath, blsWalletPath, blsAccountName, pos); err != nil {
+			if eth.voteManager, err = vote.NewVoteManager(eth, eth.blockchain, votePool, voteJournalPath, voteSigner, pos); err != nil {
 				log.Error("Failed to Initialize voteManager", "err", err)
 				return nil, err
 			}
 			log.Info("Create voteManager successfully")
 		}
+
+		if lease := stack.Config().StandbyLease; lease != "" {
+			if eth.standby, err = newStandby(stack); err != nil {
+				return nil, err
+			}
+			eth.engine.(*oasys.Oasys).SetStandby(eth.standby)
+			if eth.voteManager != nil {
+				eth.voteManager.SetStandby(eth.standby)
+				eth.standby.SetTakeoverCheck(eth.voteManager.CheckTakeover)
+			}
+			log.Info("Create validator standby successfully", "lease", lease)
+		}
 	}
 	eth.APIBackend.gpo = gasprice.NewOracle(eth.APIBackend, config.GPO, config.Miner.GasPrice)
 
@@ -453,6 +549,50 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	return eth, nil
 }
 
+// newStandby creates the standby manager acquiring the validator lease, named
+// after the node ID unless the holder is configured.
+func newStandby(stack *node.Node) (*standby.Manager, error) {
+	conf := stack.Config()
+	lease := conf.StandbyLease
+	if !strings.HasPrefix(lease, "http://") && !strings.HasPrefix(lease, "https://") {
+		lease = stack.ResolvePath(lease)
+	}
+	backend, err := standby.NewBackend(lease)
+	if err != nil {
+		return nil, err
+	}
+	holder := conf.StandbyHolder
+	if holder == "" {
+		holder = enode.PubkeyToIDV4(&conf.NodeKey().PublicKey).String()
+	}
+	return standby.NewManager(backend, holder, conf.StandbyLeaseTTL)
+}
+
+// resolveOptionalPath resolves the path against the instance directory, keeping it empty if unset.
+func resolveOptionalPath(stack *node.Node, path string) string {
+	if path == "" {
+		return ""
+	}
+	return stack.ResolvePath(path)
+}
+
+// newMaliciousVoteReporter creates a reporter that submits the evidence of malicious
+// votes through the in-process RPC, signed by the given account of the keystore.
+func (s *Ethereum) newMaliciousVoteReporter(stack *node.Node, account common.Address) (*monitor.MaliciousVoteReporter, error) {
+	wallet, err := s.accountManager.Find(accounts.Account{Address: account})
+	if wallet == nil || err != nil {
+		return nil, fmt.Errorf("malicious vote reporter account unavailable locally: %v", err)
+	}
+	chainID := s.blockchain.Config().ChainID
+	signer := func(from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if from != account {
+			return nil, errors.New("not authorized to sign this account")
+		}
+		return wallet.SignTx(accounts.Account{Address: account}, tx, chainID)
+	}
+	return monitor.NewMaliciousVoteReporter(ethclient.NewClient(stack.Attach()), account, signer)
+}
+
 func makeExtraData(extra []byte) []byte {
 	if len(extra) == 0 {
 		// create default extradata
@@ -480,6 +620,44 @@ func (s *Ethereum) APIs() []rpc.API {
 		apis = append(apis, p.APIs(s.BlockChain())...)
 	}
 
+	if s.blockchain.Config().Oasys != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "oasys",
+			Service:   NewOasysAPI(s),
+		})
+	}
+	if s.evidenceStore != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "oasys",
+			Service:   monitor.NewEvidenceAPI(s.evidenceStore),
+		})
+	}
+	if !s.config.Miner.DisableSuspiciousTxFilter {
+		apis = append(apis, rpc.API{
+			Namespace: "txfilter",
+			Service:   core.NewSuspiciousTxfilterAPI(),
+		})
+	}
+	if s.maliciousVoteReporter != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "monitor",
+			Service:   monitor.NewMaliciousVoteReporterAPI(s.maliciousVoteReporter),
+		})
+	}
+
+	if s.votePool != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "vote",
+			Service:   vote.NewVotePoolAPI(s.votePool),
+		})
+	}
+	if s.voteManager != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "admin",
+			Service:   vote.NewVoteSignerAPI(s.voteManager),
+		})
+	}
+
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
 		{
@@ -657,6 +835,19 @@ func (s *Ethereum) Start() error {
 	// start log indexer
 	s.filterMaps.Start()
 	go s.updateFilterMapsHeads()
+
+	if s.maliciousVoteReporter != nil {
+		s.maliciousVoteReporter.Start()
+	}
+	if s.finalityReporter != nil {
+		s.finalityReporter.Start()
+	}
+	if s.participationIndexer != nil {
+		s.participationIndexer.Start()
+	}
+	if s.standby != nil {
+		s.standby.Start()
+	}
 	return nil
 }
 
@@ -780,6 +971,18 @@ func (s *Ethereum) Stop() error {
 	s.discmix.Close()
 	s.dropper.Stop()
 	s.handler.Stop()
+	if s.standby != nil {
+		s.standby.Stop()
+	}
+	if s.maliciousVoteReporter != nil {
+		s.maliciousVoteReporter.Stop()
+	}
+	if s.finalityReporter != nil {
+		s.finalityReporter.Stop()
+	}
+	if s.participationIndexer != nil {
+		s.participationIndexer.Stop()
+	}
 
 	// Then stop everything else.
 	ch := make(chan struct{})
@@ -790,11 +993,20 @@ func (s *Ethereum) Stop() error {
 	s.miner.Close()
 	s.blockchain.Stop()
 	s.engine.Close()
+	if s.evidenceStore != nil {
+		s.evidenceStore.Close()
+	}
+	if s.slashingProtection != nil {
+		s.slashingProtection.Close()
+	}
 
 	// Clean shutdown marker as the last thing before closing db
 	s.shutdownTracker.Stop()
 
 	s.chainDb.Close()
+	if s.sidecarArchive != nil {
+		s.sidecarArchive.Close()
+	}
 	s.eventMux.Stop()
 
 	// stop report loop
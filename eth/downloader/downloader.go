@@ -148,6 +148,8 @@ type Downloader struct {
 	SnapSyncer     *snap.Syncer // TODO(karalabe): make private! hack for now
 	stateSyncStart chan *stateSync
 
+	sidecars *sidecarBackfiller // Backfiller of the missing blob sidecars, nil if disabled
+
 	// Cancellation and termination
 	cancelPeer string         // Identifier of the peer currently being used as the master (cancel on drop)
 	cancelCh   chan struct{}  // Channel to cancel mid-flight syncs
@@ -239,12 +241,22 @@ type BlockChain interface {
 	// HistoryPruningCutoff returns the configured history pruning point.
 	// Block bodies along with the receipts will be skipped for synchronization.
 	HistoryPruningCutoff() (uint64, common.Hash)
+
+	// GetHeaderByNumber retrieves a canonical header from the local chain.
+	GetHeaderByNumber(uint64) *types.Header
+
+	// GetSidecarsByHash retrieves the blob sidecars of a local block.
+	GetSidecarsByHash(common.Hash) types.BlobSidecars
+
+	// WriteBlobSidecars verifies and stores the blob sidecars missing in a
+	// local block.
+	WriteBlobSidecars(common.Hash, types.BlobSidecars) error
 }
 
 type DownloadOption func(downloader *Downloader) *Downloader
 
 // New creates a new downloader to fetch hashes and blocks from remote peers.
-func New(stateDb ethdb.Database, mux *event.TypeMux, chain BlockChain, dropPeer peerDropFn, _ func()) *Downloader {
+func New(stateDb ethdb.Database, mux *event.TypeMux, chain BlockChain, dropPeer peerDropFn, _ func(), options ...DownloadOption) *Downloader {
 	cutoffNumber, cutoffHash := chain.HistoryPruningCutoff()
 	dl := &Downloader{
 		stateDB:           stateDb,
@@ -261,8 +273,14 @@ func New(stateDb ethdb.Database, mux *event.TypeMux, chain BlockChain, dropPeer
 		stateSyncStart:    make(chan *stateSync),
 		syncStartBlock:    chain.CurrentSnapBlock().Number.Uint64(),
 	}
+	for _, option := range options {
+		dl = option(dl)
+	}
 
 	go dl.stateFetcher()
+	if dl.sidecars != nil {
+		go dl.sidecars.loop(dl.quitCh)
+	}
 	return dl
 }
 
@@ -353,6 +371,9 @@ func (d *Downloader) UnregisterPeer(id string) error {
 // adding various sanity checks and wrapping it with various log entries.
 func (d *Downloader) LegacySync(id string, head common.Hash, name string, td *big.Int, ttd *big.Int, mode SyncMode) error {
 	err := d.synchronise(id, head, td, ttd, mode, false, nil)
+	if err == nil && d.sidecars != nil {
+		d.sidecars.wakeup()
+	}
 
 	switch err {
 	case nil, errBusy, errCanceled:
@@ -38,5 +38,10 @@ var (
 	receiptDropMeter    = metrics.NewRegisteredMeter("eth/downloader/receipts/drop", nil)
 	receiptTimeoutMeter = metrics.NewRegisteredMeter("eth/downloader/receipts/timeout", nil)
 
+	sidecarInMeter      = metrics.NewRegisteredMeter("eth/downloader/sidecars/in", nil)
+	sidecarReqTimer     = metrics.NewRegisteredTimer("eth/downloader/sidecars/req", nil)
+	sidecarDropMeter    = metrics.NewRegisteredMeter("eth/downloader/sidecars/drop", nil)
+	sidecarTimeoutMeter = metrics.NewRegisteredMeter("eth/downloader/sidecars/timeout", nil)
+
 	throttleCounter = metrics.NewRegisteredCounter("eth/downloader/throttle", nil)
 )
@@ -0,0 +1,245 @@
+package downloader
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	// sidecarBackfillInterval is the interval to check the missing sidecars
+	// besides after every sync cycle.
+	sidecarBackfillInterval = time.Minute
+
+	// maxSidecarFetch is the number of blocks whose sidecars are requested at
+	// once. A peer may respond fewer ones, limited by the response size.
+	maxSidecarFetch = 16
+)
+
+// sidecarChain is the blockchain whose missing sidecars are backfilled.
+type sidecarChain interface {
+	CurrentHeader() *types.Header
+	GetHeaderByNumber(number uint64) *types.Header
+	GetSidecarsByHash(hash common.Hash) types.BlobSidecars
+	WriteBlobSidecars(hash common.Hash, sidecars types.BlobSidecars) error
+}
+
+// SidecarPeer is a peer serving the blob sidecars, on bsc/2 or later.
+type SidecarPeer interface {
+	ID() string
+	Log() log.Logger
+
+	// RequestBlobSidecars requests the sidecars of the blocks, delivering the
+	// response to the sink unless cancelled.
+	RequestBlobSidecars(hashes []common.Hash, sink chan<- []types.BlobSidecars) (cancel func(), err error)
+}
+
+// sidecarBackfiller fetches the blob sidecars missing locally from the peers,
+// within the window where they are retained. The sidecars are missing if the
+// blocks are imported out of the data availability window, in which they are
+// not required, or the sidecars are pruned.
+type sidecarBackfiller struct {
+	chain    sidecarChain
+	peers    func() []SidecarPeer // Retrieves the peers serving the sidecars
+	window   uint64               // Number of the recent blocks whose sidecars are retained
+	dropPeer peerDropFn           // Drops a peer serving invalid sidecars
+	timeout  func() time.Duration
+
+	cursor  uint64   // Highest block number scanned for the missing sidecars
+	missing []uint64 // Numbers of the blocks missing the sidecars, in ascending order
+	next    int      // Index of the peer to request next, rotated over the requests
+
+	wake chan struct{}
+}
+
+func newSidecarBackfiller(chain sidecarChain, peers func() []SidecarPeer, window uint64, dropPeer peerDropFn, timeout func() time.Duration) *sidecarBackfiller {
+	return &sidecarBackfiller{
+		chain:    chain,
+		peers:    peers,
+		window:   window,
+		dropPeer: dropPeer,
+		timeout:  timeout,
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// EnableSidecarBackfill enables backfilling the blob sidecars missing in the
+// recent blocks, retained for the data availability window and extra reserve,
+// from the peers retrieved by the given function.
+func EnableSidecarBackfill(extraReserve uint64, peers func() []SidecarPeer) DownloadOption {
+	return func(dl *Downloader) *Downloader {
+		dl.sidecars = newSidecarBackfiller(dl.blockchain, peers, params.MinBlocksForBlobRequests+extraReserve, dl.dropPeer, dl.peers.rates.TargetTimeout)
+		return dl
+	}
+}
+
+// wakeup schedules a backfill round without blocking.
+func (b *sidecarBackfiller) wakeup() {
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (b *sidecarBackfiller) loop(quit chan struct{}) {
+	ticker := time.NewTicker(sidecarBackfillInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.wake:
+		case <-ticker.C:
+		case <-quit:
+			return
+		}
+		b.scan(quit)
+		b.backfill(quit)
+	}
+}
+
+// scan finds the blocks missing the sidecars after the last scanned block, and
+// forgets the ones which have left the window.
+func (b *sidecarBackfiller) scan(quit chan struct{}) {
+	head := b.chain.CurrentHeader()
+	if head == nil {
+		return
+	}
+	var (
+		number = head.Number.Uint64()
+		low    = number - min(number, b.window)
+	)
+	if b.cursor > number {
+		b.cursor = number // Rewound by setHead
+	}
+	for len(b.missing) > 0 && b.missing[0] < low {
+		b.missing = b.missing[1:]
+	}
+	for n := max(b.cursor+1, low); n <= number; n++ {
+		if n%1024 == 0 {
+			select {
+			case <-quit:
+				return
+			default:
+			}
+		}
+		header := b.chain.GetHeaderByNumber(n)
+		if header == nil {
+			break
+		}
+		if header.BlobGasUsed != nil && *header.BlobGasUsed > 0 && len(b.chain.GetSidecarsByHash(header.Hash())) == 0 {
+			b.missing = append(b.missing, n)
+		}
+		b.cursor = n
+	}
+}
+
+// backfill requests the missing sidecars from the peers, each batch from one of
+// them in turn. The ones not delivered are retried in the next round.
+func (b *sidecarBackfiller) backfill(quit chan struct{}) {
+	if len(b.missing) == 0 {
+		return
+	}
+	peers := b.peers()
+	if len(peers) == 0 {
+		return
+	}
+	var (
+		missing []uint64
+		filled  int
+	)
+	for start := 0; start < len(b.missing); start += maxSidecarFetch {
+		batch := b.missing[start:min(start+maxSidecarFetch, len(b.missing))]
+		headers := make([]*types.Header, 0, len(batch))
+		for _, n := range batch {
+			header := b.chain.GetHeaderByNumber(n)
+			if header == nil || len(b.chain.GetSidecarsByHash(header.Hash())) > 0 {
+				continue // Reorged or filled in the meantime
+			}
+			headers = append(headers, header)
+		}
+		if len(headers) == 0 {
+			continue
+		}
+		peer := peers[b.next%len(peers)]
+		b.next++
+
+		delivered, err := b.fetch(peer, headers, quit)
+		switch {
+		case errors.Is(err, errCanceled):
+			return
+		case errors.Is(err, errBadPeer):
+			peer.Log().Warn("Peer served invalid blob sidecars", "err", err)
+			sidecarDropMeter.Mark(1)
+			if b.dropPeer != nil {
+				b.dropPeer(peer.ID())
+			}
+		case err != nil:
+			peer.Log().Debug("Failed to fetch blob sidecars", "err", err)
+		}
+		for _, header := range headers {
+			if !delivered[header.Hash()] {
+				missing = append(missing, header.Number.Uint64())
+			}
+		}
+		filled += len(delivered)
+	}
+	if filled > 0 {
+		log.Info("Backfilled missing blob sidecars", "blocks", filled, "missing", len(missing))
+	}
+	b.missing = missing
+}
+
+// fetch requests the sidecars of the blocks from the peer, and stores the valid
+// ones delivered. It returns the blocks whose sidecars are filled.
+func (b *sidecarBackfiller) fetch(p SidecarPeer, headers []*types.Header, quit chan struct{}) (map[common.Hash]bool, error) {
+	hashes := make([]common.Hash, len(headers))
+	for i, header := range headers {
+		hashes[i] = header.Hash()
+	}
+	start := time.Now()
+	resCh := make(chan []types.BlobSidecars, 1)
+
+	cancel, err := p.RequestBlobSidecars(hashes, resCh)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	ttl := b.timeout()
+	timeoutTimer := time.NewTimer(ttl)
+	defer timeoutTimer.Stop()
+
+	select {
+	case <-quit:
+		return nil, errCanceled
+
+	case <-timeoutTimer.C:
+		p.Log().Debug("Blob sidecars request timed out", "elapsed", ttl)
+		sidecarTimeoutMeter.Mark(1)
+		return nil, errTimeout
+
+	case sidecars := <-resCh:
+		sidecarReqTimer.Update(time.Since(start))
+
+		if len(sidecars) > len(hashes) {
+			return nil, fmt.Errorf("%w: %d sidecars for %d blocks", errBadPeer, len(sidecars), len(hashes))
+		}
+		delivered := make(map[common.Hash]bool)
+		for i, blockSidecars := range sidecars {
+			if len(blockSidecars) == 0 {
+				continue // Unavailable at the peer
+			}
+			if err := b.chain.WriteBlobSidecars(hashes[i], blockSidecars); err != nil {
+				return delivered, fmt.Errorf("%w: block %d: %v", errBadPeer, headers[i].Number, err)
+			}
+			delivered[hashes[i]] = true
+		}
+		sidecarInMeter.Mark(int64(len(delivered)))
+		return delivered, nil
+	}
+}
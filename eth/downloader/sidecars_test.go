@@ -0,0 +1,154 @@
+package downloader
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// testSidecarChain is a chain whose blocks at the even numbers carry blobs.
+type testSidecarChain struct {
+	headers  []*types.Header
+	sidecars map[common.Hash]types.BlobSidecars
+}
+
+func newTestSidecarChain(n int) *testSidecarChain {
+	chain := &testSidecarChain{sidecars: make(map[common.Hash]types.BlobSidecars)}
+	for i := 0; i < n; i++ {
+		blobGas := uint64(0)
+		if i%2 == 0 {
+			blobGas = 131072
+		}
+		chain.headers = append(chain.headers, &types.Header{Number: big.NewInt(int64(i)), BlobGasUsed: &blobGas})
+	}
+	return chain
+}
+
+func (c *testSidecarChain) CurrentHeader() *types.Header { return c.headers[len(c.headers)-1] }
+
+func (c *testSidecarChain) GetHeaderByNumber(number uint64) *types.Header {
+	if number >= uint64(len(c.headers)) {
+		return nil
+	}
+	return c.headers[number]
+}
+
+func (c *testSidecarChain) GetSidecarsByHash(hash common.Hash) types.BlobSidecars {
+	return c.sidecars[hash]
+}
+
+// WriteBlobSidecars accepts the sidecars only if they refer to the block.
+func (c *testSidecarChain) WriteBlobSidecars(hash common.Hash, sidecars types.BlobSidecars) error {
+	for _, sidecar := range sidecars {
+		if sidecar.BlockHash != hash {
+			return errors.New("invalid sidecar")
+		}
+	}
+	c.sidecars[hash] = sidecars
+	return nil
+}
+
+// testSidecarPeer serves the sidecars of the blocks it knows, or the invalid
+// ones if malicious.
+type testSidecarPeer struct {
+	id        string
+	known     map[common.Hash]bool
+	malicious bool
+	requested int
+}
+
+func (p *testSidecarPeer) ID() string      { return p.id }
+func (p *testSidecarPeer) Log() log.Logger { return log.New("peer", p.id) }
+
+func (p *testSidecarPeer) RequestBlobSidecars(hashes []common.Hash, sink chan<- []types.BlobSidecars) (func(), error) {
+	p.requested += len(hashes)
+
+	res := make([]types.BlobSidecars, len(hashes))
+	for i, hash := range hashes {
+		if !p.known[hash] {
+			continue
+		}
+		sidecar := &types.BlobSidecar{BlockHash: hash}
+		if p.malicious {
+			sidecar.BlockHash = common.Hash{}
+		}
+		res[i] = types.BlobSidecars{sidecar}
+	}
+	sink <- res
+	return func() {}, nil
+}
+
+func TestSidecarBackfill(t *testing.T) {
+	chain := newTestSidecarChain(100)
+	var (
+		peers   []SidecarPeer
+		dropped []string
+	)
+	backfiller := newSidecarBackfiller(chain, func() []SidecarPeer { return peers }, 50, func(id string) { dropped = append(dropped, id) }, func() time.Duration { return time.Second })
+
+	// The blocks with blobs within the window are missing the sidecars
+	backfiller.scan(nil)
+	if len(backfiller.missing) != 25 || backfiller.missing[0] != 50 || backfiller.cursor != 99 {
+		t.Fatalf("missing mismatch: %v, cursor %d", backfiller.missing, backfiller.cursor)
+	}
+	// Nothing is requested without the peers
+	backfiller.backfill(nil)
+	if len(backfiller.missing) != 25 {
+		t.Fatalf("missing mismatch without peers: %v", backfiller.missing)
+	}
+	// The peers serving the invalid sidecars are dropped
+	known := make(map[common.Hash]bool)
+	for _, header := range chain.headers[50:80] {
+		known[header.Hash()] = true
+	}
+	good := &testSidecarPeer{id: "good", known: known}
+	bad := &testSidecarPeer{id: "bad", known: known, malicious: true}
+	peers = []SidecarPeer{good}
+
+	backfiller.backfill(nil)
+	if good.requested != 25 {
+		t.Fatalf("requested sidecars mismatch: have %d, want 25", good.requested)
+	}
+	// The sidecars of the blocks 50-78 are filled, 80-98 are retried
+	if len(backfiller.missing) != 10 || backfiller.missing[0] != 80 {
+		t.Fatalf("missing mismatch after backfilled: %v", backfiller.missing)
+	}
+	if len(chain.sidecars) != 15 {
+		t.Fatalf("filled sidecars mismatch: have %d, want 15", len(chain.sidecars))
+	}
+
+	peers = []SidecarPeer{bad}
+	for _, header := range chain.headers[80:] {
+		known[header.Hash()] = true
+	}
+	backfiller.backfill(nil)
+	if len(dropped) != 1 || dropped[0] != bad.id {
+		t.Fatalf("dropped peers mismatch: %v", dropped)
+	}
+	if len(backfiller.missing) != 10 {
+		t.Fatalf("invalid sidecars filled: %v", backfiller.missing)
+	}
+
+	// The new blocks are scanned, and the old ones leave the window
+	for i := 100; i < 120; i++ {
+		blobGas := uint64(131072)
+		chain.headers = append(chain.headers, &types.Header{Number: big.NewInt(int64(i)), BlobGasUsed: &blobGas})
+	}
+	backfiller.scan(nil)
+	if len(backfiller.missing) != 30 || backfiller.missing[0] != 80 || backfiller.cursor != 119 {
+		t.Fatalf("missing mismatch after the new blocks: %v, cursor %d", backfiller.missing, backfiller.cursor)
+	}
+	chain.headers = append(chain.headers, make([]*types.Header, 20)...)
+	for i := 120; i < 140; i++ {
+		chain.headers[i] = &types.Header{Number: big.NewInt(int64(i))}
+	}
+	backfiller.scan(nil)
+	if len(backfiller.missing) != 25 || backfiller.missing[0] != 90 {
+		t.Fatalf("missing mismatch after the window moved: %v", backfiller.missing)
+	}
+}
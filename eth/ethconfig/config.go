@@ -31,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/history"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
+	"github.com/ethereum/go-ethereum/core/vote/voteconfig"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
@@ -68,6 +69,7 @@ var Defaults = Config{
 	FilterLogCacheSize: 32,
 	Miner:              minerconfig.DefaultConfig,
 	TxPool:             legacypool.DefaultConfig,
+	VotePool:           voteconfig.DefaultConfig,
 	BlobPool:           blobpool.DefaultConfig,
 	RPCGasCap:          50000000,
 	RPCEVMTimeout:      5 * time.Second,
@@ -171,6 +173,9 @@ type Config struct {
 	TxPool   legacypool.Config
 	BlobPool blobpool.Config
 
+	// Vote pool options
+	VotePool voteconfig.Config
+
 	// Gas Price Oracle options
 	GPO gasprice.Config
 
@@ -202,6 +207,7 @@ type Config struct {
 
 	// blob setting
 	BlobExtraReserve uint64
+	BlobArchive      string `toml:",omitempty"` // Directory of the archive retaining the blob sidecars pruned, disabled if empty
 }
 
 // CreateConsensusEngine creates a consensus engine for the given chain config.
@@ -0,0 +1,284 @@
+package eth
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/oasys"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// Maximum number of blocks reported by a single `oasys_getFinalityReport` call.
+	maxFinalityReportRange = 256
+	// Maximum number of blocks reported at once by the finality reporter, which
+	// skips the older ones after a long sync.
+	maxFinalityReporterBacklog = 64
+)
+
+var (
+	firstVoteLatencyTimer    = metrics.NewRegisteredTimer("oasys/finality/vote/first", nil)
+	majorityVoteLatencyTimer = metrics.NewRegisteredTimer("oasys/finality/vote/majority", nil)
+	timeToJustifiedTimer     = metrics.NewRegisteredTimer("oasys/finality/justified", nil)
+	timeToFinalizedTimer     = metrics.NewRegisteredTimer("oasys/finality/finalized", nil)
+	notAttestedMeter         = metrics.NewRegisteredMeter("oasys/finality/notattested", nil)
+	missedVotesMeter         = metrics.NewRegisteredMeter("oasys/finality/missedvotes", nil)
+)
+
+// BlockStatsResult is the local observation of the block and its votes, in
+// unix milliseconds. The zero values are omitted as not observed.
+type BlockStatsResult struct {
+	RecvNewBlockHashTime int64  `json:"recvNewBlockHashTime,omitempty"`
+	RecvNewBlockHashFrom string `json:"recvNewBlockHashFrom,omitempty"`
+	RecvNewBlockTime     int64  `json:"recvNewBlockTime,omitempty"`
+	RecvNewBlockFrom     string `json:"recvNewBlockFrom,omitempty"`
+	StartMiningTime      int64  `json:"startMiningTime,omitempty"`
+	SendBlockTime        int64  `json:"sendBlockTime,omitempty"`
+	StartImportBlockTime int64  `json:"startImportBlockTime,omitempty"`
+	ImportedBlockTime    int64  `json:"importedBlockTime,omitempty"`
+	SendVoteTime         int64  `json:"sendVoteTime,omitempty"`
+	FirstRecvVoteTime    int64  `json:"firstRecvVoteTime,omitempty"`
+	RecvMajorityVoteTime int64  `json:"recvMajorityVoteTime,omitempty"`
+	JustifiedTime        int64  `json:"justifiedTime,omitempty"`
+	FinalizedTime        int64  `json:"finalizedTime,omitempty"`
+}
+
+func newBlockStatsResult(stats *core.BlockStats) *BlockStatsResult {
+	result := &BlockStatsResult{
+		RecvNewBlockHashTime: stats.RecvNewBlockHashTime.Load(),
+		RecvNewBlockTime:     stats.RecvNewBlockTime.Load(),
+		StartMiningTime:      stats.StartMiningTime.Load(),
+		SendBlockTime:        stats.SendBlockTime.Load(),
+		StartImportBlockTime: stats.StartImportBlockTime.Load(),
+		ImportedBlockTime:    stats.ImportedBlockTime.Load(),
+		SendVoteTime:         stats.SendVoteTime.Load(),
+		FirstRecvVoteTime:    stats.FirstRecvVoteTime.Load(),
+		RecvMajorityVoteTime: stats.RecvMajorityVoteTime.Load(),
+		JustifiedTime:        stats.JustifiedTime.Load(),
+		FinalizedTime:        stats.FinalizedTime.Load(),
+	}
+	result.RecvNewBlockHashFrom, _ = stats.RecvNewBlockHashFrom.Load().(string)
+	result.RecvNewBlockFrom, _ = stats.RecvNewBlockFrom.Load().(string)
+	return result
+}
+
+// seenTime returns the time when the block is first seen by this node, by the
+// announcement, the propagation, sealing it or importing it.
+func (s *BlockStatsResult) seenTime() int64 {
+	var seen int64
+	for _, t := range []int64{s.RecvNewBlockHashTime, s.RecvNewBlockTime, s.SendBlockTime, s.StartImportBlockTime, s.ImportedBlockTime} {
+		if t > 0 && (seen == 0 || t < seen) {
+			seen = t
+		}
+	}
+	return seen
+}
+
+// BlockFinalityReport is the vote propagation and the finality of the block.
+// The latencies are in milliseconds from when the block is first seen, and are
+// omitted if either end is not observed by this node.
+type BlockFinalityReport struct {
+	Number    hexutil.Uint64    `json:"number"`
+	Hash      common.Hash       `json:"hash"`
+	Miner     common.Address    `json:"miner"`
+	Timestamp hexutil.Uint64    `json:"timestamp"`
+	Stats     *BlockStatsResult `json:"stats,omitempty"`
+
+	FirstVoteLatency    *int64 `json:"firstVoteLatency,omitempty"`
+	MajorityVoteLatency *int64 `json:"majorityVoteLatency,omitempty"`
+	TimeToJustified     *int64 `json:"timeToJustified,omitempty"`
+	TimeToFinalized     *int64 `json:"timeToFinalized,omitempty"`
+
+	Justified bool `json:"justified"`
+	Finalized bool `json:"finalized"`
+
+	// The attestation of this block is included in the child block, which is
+	// nil if the child is not imported yet.
+	AttestedBy *hexutil.Uint64           `json:"attestedBy,omitempty"`
+	Attested   bool                      `json:"attested"`
+	Voted      int                       `json:"voted"`
+	Validators int                       `json:"validators"`
+	Missing    []*oasys.AttestationVoter `json:"missing"`
+}
+
+// latency returns the milliseconds from the start to the end, or nil if either is unknown.
+func latency(start, end int64) *int64 {
+	if start == 0 || end == 0 {
+		return nil
+	}
+	elapsed := end - start
+	return &elapsed
+}
+
+// formatLatency formats the latency for the logs.
+func formatLatency(elapsed *int64) any {
+	if elapsed == nil {
+		return "unknown"
+	}
+	return time.Duration(*elapsed) * time.Millisecond
+}
+
+// newBlockFinalityReport reports the canonical block.
+func (s *Ethereum) newBlockFinalityReport(header *types.Header) (*BlockFinalityReport, error) {
+	engine, ok := s.engine.(*oasys.Oasys)
+	if !ok {
+		return nil, errors.New("finality is not supported by the consensus engine")
+	}
+	var (
+		chain  = s.blockchain
+		number = header.Number.Uint64()
+		hash   = header.Hash()
+		report = &BlockFinalityReport{
+			Number:    hexutil.Uint64(number),
+			Hash:      hash,
+			Miner:     header.Coinbase,
+			Timestamp: hexutil.Uint64(header.Time),
+			Missing:   []*oasys.AttestationVoter{},
+		}
+	)
+	if stats := chain.PeekBlockStats(hash); stats != nil {
+		report.Stats = newBlockStatsResult(stats)
+		seen := report.Stats.seenTime()
+		report.FirstVoteLatency = latency(seen, report.Stats.FirstRecvVoteTime)
+		report.MajorityVoteLatency = latency(seen, report.Stats.RecvMajorityVoteTime)
+		report.TimeToJustified = latency(seen, report.Stats.JustifiedTime)
+		report.TimeToFinalized = latency(seen, report.Stats.FinalizedTime)
+	}
+
+	head := chain.CurrentHeader()
+	if justified, _, err := engine.GetJustifiedNumberAndHash(chain, []*types.Header{head}); err == nil {
+		report.Justified = number <= justified
+	}
+	if finalized := chain.CurrentFinalBlock(); finalized != nil {
+		report.Finalized = number <= finalized.Number.Uint64()
+	}
+
+	child := chain.GetHeaderByNumber(number + 1)
+	if child == nil || child.ParentHash != hash {
+		return report, nil
+	}
+	attestedBy := hexutil.Uint64(number + 1)
+	report.AttestedBy = &attestedBy
+
+	attestation, voters, err := engine.AttestationVoters(chain, child)
+	if err != nil {
+		return nil, err
+	}
+	report.Attested = attestation != nil && attestation.Data.TargetHash == hash
+	report.Validators = len(voters)
+	for _, voter := range voters {
+		if voter.Voted {
+			report.Voted++
+		} else {
+			report.Missing = append(report.Missing, voter)
+		}
+	}
+	return report, nil
+}
+
+// finalityReporter exports the report of every finalized block as the metrics
+// and the logs, to diagnose the slow finality.
+type finalityReporter struct {
+	eth  *Ethereum
+	last uint64 // Number of the last reported block
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newFinalityReporter(eth *Ethereum) *finalityReporter {
+	return &finalityReporter{eth: eth, quit: make(chan struct{})}
+}
+
+// Start starts the background loop reporting the finalized blocks.
+func (r *finalityReporter) Start() {
+	if finalized := r.eth.blockchain.CurrentFinalBlock(); finalized != nil {
+		r.last = finalized.Number.Uint64()
+	}
+	r.wg.Add(1)
+	go r.loop()
+}
+
+// Stop terminates the background loop.
+func (r *finalityReporter) Stop() {
+	close(r.quit)
+	r.wg.Wait()
+}
+
+func (r *finalityReporter) loop() {
+	defer r.wg.Done()
+
+	headCh := make(chan core.ChainHeadEvent, 10)
+	sub := r.eth.blockchain.SubscribeChainHeadEvent(headCh)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-headCh:
+			r.reportFinalized()
+		case <-sub.Err():
+			return
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+// reportFinalized reports the blocks finalized since the last report.
+func (r *finalityReporter) reportFinalized() {
+	finalized := r.eth.blockchain.CurrentFinalBlock()
+	if finalized == nil || finalized.Number.Uint64() <= r.last {
+		return
+	}
+	number := finalized.Number.Uint64()
+	if number-r.last > maxFinalityReporterBacklog {
+		r.last = number - maxFinalityReporterBacklog
+	}
+	for r.last < number {
+		header := r.eth.blockchain.GetHeaderByNumber(r.last + 1)
+		if header == nil {
+			return
+		}
+		r.last++
+
+		report, err := r.eth.newBlockFinalityReport(header)
+		if err != nil {
+			log.Debug("Failed to report block finality", "number", r.last, "err", err)
+			continue
+		}
+		r.export(report)
+	}
+}
+
+func (r *finalityReporter) export(report *BlockFinalityReport) {
+	for timer, elapsed := range map[*metrics.Timer]*int64{
+		firstVoteLatencyTimer:    report.FirstVoteLatency,
+		majorityVoteLatencyTimer: report.MajorityVoteLatency,
+		timeToJustifiedTimer:     report.TimeToJustified,
+		timeToFinalizedTimer:     report.TimeToFinalized,
+	} {
+		if elapsed != nil && *elapsed >= 0 {
+			timer.Update(time.Duration(*elapsed) * time.Millisecond)
+		}
+	}
+	if !report.Attested {
+		notAttestedMeter.Mark(1)
+	}
+	missedVotesMeter.Mark(int64(len(report.Missing)))
+	missing := make([]common.Address, len(report.Missing))
+	for i, voter := range report.Missing {
+		missing[i] = voter.Operator
+		metrics.GetOrRegisterMeter("oasys/finality/missedvotes/"+voter.Operator.Hex(), nil).Mark(1)
+	}
+
+	log.Debug("Block finality", "number", report.Number, "hash", report.Hash, "miner", report.Miner,
+		"firstVote", formatLatency(report.FirstVoteLatency), "majorityVote", formatLatency(report.MajorityVoteLatency),
+		"justified", formatLatency(report.TimeToJustified), "finalized", formatLatency(report.TimeToFinalized),
+		"attested", report.Attested, "voted", report.Voted, "validators", report.Validators, "missing", missing)
+}
@@ -0,0 +1,37 @@
+package eth
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core"
+)
+
+func TestBlockStatsResult(t *testing.T) {
+	stats := new(core.BlockStats)
+	result := newBlockStatsResult(stats)
+	if seen := result.seenTime(); seen != 0 {
+		t.Fatalf("seen time of unobserved block = %d, want 0", seen)
+	}
+	if latency(result.seenTime(), 1000) != nil {
+		t.Fatal("latency from unobserved block")
+	}
+
+	stats.ImportedBlockTime.Store(1300)
+	stats.RecvNewBlockTime.Store(1100)
+	stats.RecvNewBlockHashTime.Store(1200) // announced after the propagation
+	stats.RecvNewBlockFrom.Store("127.0.0.1:30303")
+	stats.FirstRecvVoteTime.Store(1500)
+	result = newBlockStatsResult(stats)
+	if seen := result.seenTime(); seen != 1100 {
+		t.Fatalf("seen time = %d, want 1100", seen)
+	}
+	if result.RecvNewBlockFrom != "127.0.0.1:30303" || result.RecvNewBlockHashFrom != "" {
+		t.Fatalf("unexpected origins: %q, %q", result.RecvNewBlockFrom, result.RecvNewBlockHashFrom)
+	}
+	if elapsed := latency(result.seenTime(), result.FirstRecvVoteTime); elapsed == nil || *elapsed != 400 {
+		t.Fatalf("first vote latency = %v, want 400", elapsed)
+	}
+	if latency(result.seenTime(), result.JustifiedTime) != nil {
+		t.Fatal("latency to unobserved justification")
+	}
+}
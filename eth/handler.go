@@ -114,7 +114,9 @@ type txPool interface {
 // support all the operations needed by the Ethereum chain protocols.
 type votePool interface {
 	PutVote(vote *types.VoteEnvelope)
+	PutVoteWithResult(vote *types.VoteEnvelope, result func(error))
 	GetVotes() []*types.VoteEnvelope
+	FetchVoteByBlockHash(blockHash common.Hash) []*types.VoteEnvelope
 
 	// SubscribeNewVoteEvent should return an event subscription of
 	// NewVotesEvent and send events to the given channel.
@@ -137,6 +139,8 @@ type handlerConfig struct {
 	DirectBroadcast        bool
 	DisablePeerTxBroadcast bool
 	PeerSet                *peerSet
+	BanPeer                func(id enode.ID, duration time.Duration) // Bans the vote spammers, or only disconnects them if nil
+	BlobExtraReserve       uint64                                    // Extra blocks beyond the data availability window to backfill the sidecars of
 }
 
 type handler struct {
@@ -152,6 +156,7 @@ type handler struct {
 	database             ethdb.Database
 	txpool               txPool
 	votepool             votePool
+	voteScores           *bsc.PeerScores
 	maliciousVoteMonitor *monitor.MaliciousVoteMonitor
 	chain                *core.BlockChain
 	maxPeers             int
@@ -219,6 +224,15 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		handlerStartCh:         make(chan struct{}),
 		stopCh:                 make(chan struct{}),
 	}
+	h.voteScores = bsc.NewPeerScores(func(peer *bsc.Peer, duration time.Duration) {
+		if config.BanPeer == nil {
+			peer.Disconnect(p2p.DiscUselessPeer)
+			return
+		}
+		// The offences are reported from the loop of the vote pool, which
+		// must not wait for the p2p server.
+		go config.BanPeer(peer.Peer.ID(), duration)
+	})
 	if config.Sync == ethconfig.FullSync {
 		// The database seems empty as the current block is the genesis. Yet the snap
 		// block is ahead, so snap sync was enabled for this node at a certain point.
@@ -252,7 +266,7 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		return nil, errors.New("snap sync not supported with snapshots disabled")
 	}
 	// Construct the downloader (long sync)
-	h.downloader = downloader.New(config.Database, h.eventMux, h.chain, h.removePeer, nil)
+	h.downloader = downloader.New(config.Database, h.eventMux, h.chain, h.removePeer, nil, downloader.EnableSidecarBackfill(config.BlobExtraReserve, h.peers.sidecarPeers))
 
 	// Construct the fetcher (short sync)
 	validator := func(header *types.Header) error {
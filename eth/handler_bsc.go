@@ -1,10 +1,12 @@
 package eth
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vote"
 	"github.com/ethereum/go-ethereum/eth/protocols/bsc"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 )
@@ -53,6 +55,15 @@ func (h *bscHandler) Handle(peer *bsc.Peer, packet bsc.Packet) error {
 	case *bsc.VotesPacket:
 		return h.handleVotesBroadcast(peer, packet.Votes)
 
+	case *bsc.GetVotesByBlockHashPacket:
+		return peer.ReplyVotes(packet.RequestId, h.votepool.FetchVoteByBlockHash(packet.BlockHash))
+
+	case *bsc.VotesResponsePacket:
+		return h.handleVotesResponse(peer, packet.Votes)
+
+	case *bsc.VoteBundlesPacket:
+		return h.handleVoteBundlesBroadcast(peer, packet.Unpack())
+
 	default:
 		return fmt.Errorf("unexpected bsc packet type: %T", packet)
 	}
@@ -62,13 +73,71 @@ func (h *bscHandler) Handle(peer *bsc.Peer, packet bsc.Packet) error {
 // votes broadcast for the local node to process.
 func (h *bscHandler) handleVotesBroadcast(peer *bsc.Peer, votes []*types.VoteEnvelope) error {
 	if peer.IsOverLimitAfterReceiving() {
+		h.Penalize(peer, bsc.OffenceRateLimited, len(votes))
 		return nil
 	}
 	// Here we only put the first vote, to avoid ddos attack by sending a large batch of votes.
 	// This won't abandon any valid vote, because one vote is sent every time referring to func voteBroadcastLoop
 	if len(votes) > 0 {
-		h.votepool.PutVote(votes[0])
+		h.putVote(peer, votes[0])
 	}
 
 	return nil
 }
+
+// handleVotesResponse is invoked from a peer's message handler when it responds
+// the votes requested by the local node.
+func (h *bscHandler) handleVotesResponse(peer *bsc.Peer, votes []*types.VoteEnvelope) error {
+	for _, vote := range votes {
+		h.putVote(peer, vote)
+	}
+	return nil
+}
+
+// handleVoteBundlesBroadcast is invoked from a peer's message handler when it
+// transmits a broadcast of the vote bundles. Every vote in the bundles is counted
+// against the rate limit of the peer.
+func (h *bscHandler) handleVoteBundlesBroadcast(peer *bsc.Peer, votes []*types.VoteEnvelope) error {
+	for i, vote := range votes {
+		if peer.IsOverLimitAfterReceiving() {
+			h.Penalize(peer, bsc.OffenceRateLimited, len(votes)-i)
+			return nil
+		}
+		h.putVote(peer, vote)
+	}
+	return nil
+}
+
+// putVote puts the vote received from the peer into the vote pool, and penalizes
+// the peer if the vote is invalid.
+func (h *bscHandler) putVote(peer *bsc.Peer, vote *types.VoteEnvelope) {
+	h.votepool.PutVoteWithResult(vote, func(err error) {
+		if offence, ok := voteOffence(err); ok {
+			h.Penalize(peer, offence, 1)
+		}
+	})
+}
+
+// Penalize implements bsc.PeerScorer, lowering the score of the peer by the offences.
+func (h *bscHandler) Penalize(peer *bsc.Peer, offence bsc.Offence, count int) {
+	h.voteScores.Penalize(peer, offence, count)
+}
+
+// voteOffence classifies the error rejecting the vote from a peer. The votes
+// rejected for the local reasons, e.g. the duplicate ones already received from
+// other peers, are not offences. The far future and stale ones are tracked but
+// not penalized, since they depend on the local head lagging behind the peers.
+func voteOffence(err error) (bsc.Offence, bool) {
+	switch {
+	case errors.Is(err, vote.ErrVoteInvalidSignature):
+		return bsc.OffenceInvalidSignature, true
+	case errors.Is(err, vote.ErrVoteInvalidVoter):
+		return bsc.OffenceInvalidVoter, true
+	case errors.Is(err, vote.ErrVoteFarFuture):
+		return bsc.OffenceFarFutureVote, true
+	case errors.Is(err, vote.ErrVoteStale):
+		return bsc.OffenceStaleVote, true
+	default:
+		return 0, false
+	}
+}
@@ -1,13 +1,18 @@
 package eth
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vote"
+	"github.com/ethereum/go-ethereum/core/vote/voteconfig"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/ethereum/go-ethereum/eth/protocols/bsc"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/event"
@@ -17,6 +22,7 @@ import (
 
 type testBscHandler struct {
 	voteBroadcasts event.Feed
+	voteResponses  event.Feed
 }
 
 func (h *testBscHandler) Chain() *core.BlockChain { panic("no backing chain") }
@@ -30,14 +36,26 @@ func (h *testBscHandler) Handle(peer *bsc.Peer, packet bsc.Packet) error {
 		h.voteBroadcasts.Send(packet.Votes)
 		return nil
 
+	case *bsc.VoteBundlesPacket:
+		h.voteBroadcasts.Send(packet.Unpack())
+		return nil
+
+	case *bsc.GetVotesByBlockHashPacket:
+		return nil
+
+	case *bsc.VotesResponsePacket:
+		h.voteResponses.Send(packet.Votes)
+		return nil
+
 	default:
 		panic(fmt.Sprintf("unexpected bsc packet type in tests: %T", packet))
 	}
 }
 
-func TestSendVotes68(t *testing.T) { testSendVotes(t, eth.ETH68) }
+func TestSendVotes68(t *testing.T)     { testSendVotes(t, eth.ETH68, bsc.Bsc1) }
+func TestSendVotes68Bsc2(t *testing.T) { testSendVotes(t, eth.ETH68, bsc.Bsc2) }
 
-func testSendVotes(t *testing.T, protocol uint) {
+func testSendVotes(t *testing.T, protocol uint, bscVersion uint) {
 	t.Parallel()
 
 	// Create a message handler and fill the pool with big votes
@@ -68,7 +86,7 @@ func testSendVotes(t *testing.T, protocol uint) {
 		},
 		{
 			Name:    "bsc",
-			Version: bsc.Bsc1,
+			Version: bscVersion,
 		},
 	}
 	caps := []p2p.Cap{
@@ -78,7 +96,7 @@ func testSendVotes(t *testing.T, protocol uint) {
 		},
 		{
 			Name:    "bsc",
-			Version: bsc.Bsc1,
+			Version: bscVersion,
 		},
 	}
 
@@ -96,8 +114,8 @@ func testSendVotes(t *testing.T, protocol uint) {
 	defer p2pBscSrc.Close()
 	defer p2pBscSink.Close()
 
-	localBsc := bsc.NewPeer(bsc.Bsc1, p2p.NewPeerWithProtocols(enode.ID{1}, protos, "", caps), p2pBscSrc)
-	remoteBsc := bsc.NewPeer(bsc.Bsc1, p2p.NewPeerWithProtocols(enode.ID{3}, protos, "", caps), p2pBscSink)
+	localBsc := bsc.NewPeer(bscVersion, p2p.NewPeerWithProtocols(enode.ID{1}, protos, "", caps), p2pBscSrc)
+	remoteBsc := bsc.NewPeer(bscVersion, p2p.NewPeerWithProtocols(enode.ID{3}, protos, "", caps), p2pBscSink)
 	defer localBsc.Close()
 	defer remoteBsc.Close()
 
@@ -153,9 +171,10 @@ func testSendVotes(t *testing.T, protocol uint) {
 	}
 }
 
-func TestRecvVotes68(t *testing.T) { testRecvVotes(t, eth.ETH68) }
+func TestRecvVotes68(t *testing.T)     { testRecvVotes(t, eth.ETH68, bsc.Bsc1) }
+func TestRecvVotes68Bsc2(t *testing.T) { testRecvVotes(t, eth.ETH68, bsc.Bsc2) }
 
-func testRecvVotes(t *testing.T, protocol uint) {
+func testRecvVotes(t *testing.T, protocol uint, bscVersion uint) {
 	t.Parallel()
 
 	// Create a message handler and fill the pool with big votes
@@ -169,7 +188,7 @@ func testRecvVotes(t *testing.T, protocol uint) {
 		},
 		{
 			Name:    "bsc",
-			Version: bsc.Bsc1,
+			Version: bscVersion,
 		},
 	}
 	caps := []p2p.Cap{
@@ -179,7 +198,7 @@ func testRecvVotes(t *testing.T, protocol uint) {
 		},
 		{
 			Name:    "bsc",
-			Version: bsc.Bsc1,
+			Version: bscVersion,
 		},
 	}
 
@@ -197,8 +216,8 @@ func testRecvVotes(t *testing.T, protocol uint) {
 	defer p2pBscSrc.Close()
 	defer p2pBscSink.Close()
 
-	localBsc := bsc.NewPeer(bsc.Bsc1, p2p.NewPeerWithProtocols(enode.ID{1}, protos, "", caps), p2pBscSrc)
-	remoteBsc := bsc.NewPeer(bsc.Bsc1, p2p.NewPeerWithProtocols(enode.ID{3}, protos, "", caps), p2pBscSink)
+	localBsc := bsc.NewPeer(bscVersion, p2p.NewPeerWithProtocols(enode.ID{1}, protos, "", caps), p2pBscSrc)
+	remoteBsc := bsc.NewPeer(bscVersion, p2p.NewPeerWithProtocols(enode.ID{3}, protos, "", caps), p2pBscSink)
 	defer localBsc.Close()
 	defer remoteBsc.Close()
 
@@ -254,3 +273,182 @@ func testRecvVotes(t *testing.T, protocol uint) {
 		t.Errorf("no NewVotesEvent received within 2 seconds")
 	}
 }
+
+func TestRequestVotes68Bsc2(t *testing.T) { testRequestVotes(t, eth.ETH68) }
+
+func testRequestVotes(t *testing.T, protocol uint) {
+	t.Parallel()
+
+	// Create a message handler and fill the pool with the votes for two blocks
+	handler := newTestHandler()
+	defer handler.close()
+
+	target := common.Hash{0x1}
+	for index := 0; index < 10; index++ {
+		vote := &types.VoteEnvelope{
+			VoteAddress: types.BLSPublicKey{byte(index)},
+			Data: &types.VoteData{
+				TargetNumber: uint64(1 + index%2),
+				TargetHash:   common.Hash{byte(1 + index%2)},
+			},
+		}
+		handler.votepool.PutVote(vote)
+	}
+
+	protos := []p2p.Protocol{
+		{
+			Name:    "eth",
+			Version: eth.ETH68,
+		},
+		{
+			Name:    "bsc",
+			Version: bsc.Bsc2,
+		},
+	}
+	caps := []p2p.Cap{
+		{
+			Name:    "eth",
+			Version: eth.ETH68,
+		},
+		{
+			Name:    "bsc",
+			Version: bsc.Bsc2,
+		},
+	}
+
+	// Create a source handler to serve the votes and a sink peer to request them
+	p2pBscSrc, p2pBscSink := p2p.MsgPipe()
+	defer p2pBscSrc.Close()
+	defer p2pBscSink.Close()
+
+	localBsc := bsc.NewPeer(bsc.Bsc2, p2p.NewPeerWithProtocols(enode.ID{1}, protos, "", caps), p2pBscSrc)
+	remoteBsc := bsc.NewPeer(bsc.Bsc2, p2p.NewPeerWithProtocols(enode.ID{3}, protos, "", caps), p2pBscSink)
+	defer localBsc.Close()
+	defer remoteBsc.Close()
+
+	go bsc.Handle((*bscHandler)(handler.handler), localBsc)
+
+	backend := new(testBscHandler)
+	responses := make(chan []*types.VoteEnvelope)
+	sub := backend.voteResponses.Subscribe(responses)
+	defer sub.Unsubscribe()
+
+	go bsc.Handle(backend, remoteBsc)
+
+	if err := remoteBsc.RequestVotesByBlockHash(target); err != nil {
+		t.Fatalf("failed to request votes: %v", err)
+	}
+	select {
+	case votes := <-responses:
+		if len(votes) != 5 {
+			t.Errorf("vote count mismatch: have %d, want 5", len(votes))
+		}
+		for _, vote := range votes {
+			if vote.Data.TargetHash != target {
+				t.Errorf("vote for wrong block: have %x, want %x", vote.Data.TargetHash, target)
+			}
+		}
+	case <-time.After(2 * time.Second):
+		t.Errorf("no votes response received within 2 seconds")
+	}
+}
+
+// Tests that a node whose head lags behind the peers doesn't penalize them for
+// relaying the honest votes, which look far future to it.
+func TestVotesOfLaggingNodeNotPenalized(t *testing.T) {
+	t.Parallel()
+
+	handler := newTestHandler()
+	defer handler.close()
+
+	pool := vote.NewVotePool(voteconfig.DefaultConfig, handler.chain, nil)
+	handler.handler.votepool = pool
+
+	peer := bsc.NewPeer(bsc.Bsc2, p2p.NewPeer(enode.ID{1}, "", nil), nil)
+	defer peer.Close()
+
+	head := handler.chain.CurrentBlock().Number.Uint64()
+	for i := uint64(0); i < 100; i++ {
+		(*bscHandler)(handler.handler).putVote(peer, &types.VoteEnvelope{
+			VoteAddress: types.BLSPublicKey{byte(i)},
+			Data: &types.VoteData{
+				TargetNumber: head + voteconfig.DefaultConfig.FutureDistance + 1 + i,
+				TargetHash:   common.Hash{byte(i)},
+			},
+		})
+	}
+	// The votes are handled in order, so all the above are done once this is.
+	done := make(chan error, 1)
+	pool.PutVoteWithResult(&types.VoteEnvelope{
+		Data: &types.VoteData{TargetNumber: head + voteconfig.DefaultConfig.FutureDistance + 1},
+	}, func(err error) { done <- err })
+	if err := <-done; !errors.Is(err, vote.ErrVoteFarFuture) {
+		t.Fatalf("vote not rejected as far future: %v", err)
+	}
+	scores := handler.handler.voteScores.Scores()
+	if len(scores) != 1 || scores[0].Offences["farFutureVote"] != 100 {
+		t.Fatalf("far future votes not tracked: %+v", scores)
+	}
+	if scores[0].Score != 0 {
+		t.Fatalf("peer penalized for far future votes: %+v", scores[0])
+	}
+}
+
+func TestRequestBlobSidecarsBsc2(t *testing.T) {
+	t.Parallel()
+
+	// Create a message handler and store the sidecars of the block 2, while the
+	// others have none
+	handler := newTestHandlerWithBlocks(4)
+	defer handler.close()
+
+	block := handler.chain.GetBlockByNumber(2)
+	sidecars := types.BlobSidecars{{
+		BlobTxSidecar: types.BlobTxSidecar{
+			Blobs:       []kzg4844.Blob{{0x01}},
+			Commitments: []kzg4844.Commitment{{0x02}},
+			Proofs:      []kzg4844.Proof{{0x03}},
+		},
+		BlockNumber: block.Number(),
+		BlockHash:   block.Hash(),
+		TxHash:      common.Hash{0x01},
+	}}
+	rawdb.WriteBlobSidecars(handler.db, block.Hash(), block.NumberU64(), sidecars)
+
+	protos := []p2p.Protocol{{Name: "bsc", Version: bsc.Bsc2}}
+	caps := []p2p.Cap{{Name: "bsc", Version: bsc.Bsc2}}
+
+	// Create a source handler to serve the sidecars and a sink peer to request them
+	p2pBscSrc, p2pBscSink := p2p.MsgPipe()
+	defer p2pBscSrc.Close()
+	defer p2pBscSink.Close()
+
+	localBsc := bsc.NewPeer(bsc.Bsc2, p2p.NewPeerWithProtocols(enode.ID{1}, protos, "", caps), p2pBscSrc)
+	remoteBsc := bsc.NewPeer(bsc.Bsc2, p2p.NewPeerWithProtocols(enode.ID{3}, protos, "", caps), p2pBscSink)
+	defer localBsc.Close()
+	defer remoteBsc.Close()
+
+	go bsc.Handle((*bscHandler)(handler.handler), localBsc)
+	go bsc.Handle(new(testBscHandler), remoteBsc)
+
+	// The sidecars unavailable are responded empty in order
+	hashes := []common.Hash{handler.chain.GetBlockByNumber(1).Hash(), block.Hash(), {0xde, 0xad}}
+	sink := make(chan []types.BlobSidecars, 1)
+	cancel, err := remoteBsc.RequestBlobSidecars(hashes, sink)
+	if err != nil {
+		t.Fatalf("failed to request sidecars: %v", err)
+	}
+	defer cancel()
+
+	select {
+	case res := <-sink:
+		if len(res) != 3 || len(res[0]) != 0 || len(res[2]) != 0 {
+			t.Fatalf("sidecars mismatch: %v", res)
+		}
+		if len(res[1]) != 1 || res[1][0].BlockHash != block.Hash() || res[1][0].Blobs[0] != sidecars[0].Blobs[0] {
+			t.Errorf("sidecars of block 2 mismatch: %v", res[1])
+		}
+	case <-time.After(2 * time.Second):
+		t.Errorf("no sidecars response received within 2 seconds")
+	}
+}
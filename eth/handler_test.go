@@ -374,8 +374,22 @@ func (t *testVotePool) PutVote(vote *types.VoteEnvelope) {
 	t.voteFeed.Send(core.NewVoteEvent{Vote: vote})
 }
 
+func (t *testVotePool) PutVoteWithResult(vote *types.VoteEnvelope, result func(error)) {
+	t.PutVote(vote)
+	result(nil)
+}
+
 func (t *testVotePool) FetchVoteByBlockHash(blockHash common.Hash) []*types.VoteEnvelope {
-	panic("implement me")
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	var votes []*types.VoteEnvelope
+	for _, vote := range t.pool {
+		if vote.Data.TargetHash == blockHash {
+			votes = append(votes, vote)
+		}
+	}
+	return votes
 }
 
 func (t *testVotePool) GetVotes() []*types.VoteEnvelope {
@@ -74,7 +74,8 @@ type snapPeerInfo struct {
 // bscPeerInfo represents a short summary of the `bsc` sub-protocol metadata known
 // about a connected peer.
 type bscPeerInfo struct {
-	Version uint `json:"version"` // bsc protocol version negotiated
+	Version     uint `json:"version"`     // bsc protocol version negotiated
+	VoteBundles bool `json:"voteBundles"` // whether the votes are sent in the bundles
 }
 
 // snapPeer is a wrapper around snap.Peer to maintain a few extra metadata.
@@ -97,6 +98,7 @@ func (p *snapPeer) info() *snapPeerInfo {
 // info gathers and returns some `bsc` protocol metadata known about a peer.
 func (p *bscPeer) info() *bscPeerInfo {
 	return &bscPeerInfo{
-		Version: p.Version(),
+		Version:     p.Version(),
+		VoteBundles: p.SupportsVoteBundles(),
 	}
 }
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/protocols/bsc"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
@@ -384,6 +385,20 @@ func (ps *peerSet) peersWithoutVote(hash common.Hash) []*ethPeer {
 	return list
 }
 
+// sidecarPeers retrieves the `bsc` extensions of the peers serving the blob sidecars.
+func (ps *peerSet) sidecarPeers() []downloader.SidecarPeer {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+
+	list := make([]downloader.SidecarPeer, 0, len(ps.peers))
+	for _, p := range ps.peers {
+		if p.bscExt != nil && p.bscExt.SupportsBlobSidecars() {
+			list = append(list, p.bscExt.Peer)
+		}
+	}
+	return list
+}
+
 // len returns if the current number of `eth` peers in the set. Since the `snap`
 // peers are tied to the existence of an `eth` connection, that will always be a
 // subset of `eth`.
@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 // Handler is a callback to invoke from an outside runner after the boilerplate
@@ -82,6 +84,15 @@ var bsc1 = map[uint64]msgHandler{
 	VotesMsg: handleVotes,
 }
 
+var bsc2 = map[uint64]msgHandler{
+	VotesMsg:               handleVotes,
+	GetVotesByBlockHashMsg: handleGetVotesByBlockHash,
+	VotesResponseMsg:       handleVotesResponse,
+	VoteBundlesMsg:         handleVoteBundles,
+	GetBlobSidecarsMsg:     handleGetBlobSidecars,
+	BlobSidecarsMsg:        handleBlobSidecars,
+}
+
 // handleMessage is invoked whenever an inbound message is received from a
 // remote peer on the `bsc` protocol. The remote connection is torn down upon
 // returning any error.
@@ -97,6 +108,9 @@ func handleMessage(backend Backend, peer *Peer) error {
 	defer msg.Discard()
 
 	var handlers = bsc1
+	if peer.Version() >= Bsc2 {
+		handlers = bsc2
+	}
 
 	// Track the amount of time it takes to serve the request and run the handler
 	if metrics.Enabled() {
@@ -122,10 +136,88 @@ func handleVotes(backend Backend, msg Decoder, peer *Peer) error {
 		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
 	}
 	// Schedule all the unknown hashes for retrieval
+	penalizeRepeatedVotes(backend, peer, ann.Votes)
 	peer.markVotes(ann.Votes)
 	return backend.Handle(peer, ann)
 }
 
+func handleGetVotesByBlockHash(backend Backend, msg Decoder, peer *Peer) error {
+	req := new(GetVotesByBlockHashPacket)
+	if err := msg.Decode(req); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	return backend.Handle(peer, req)
+}
+
+func handleVotesResponse(backend Backend, msg Decoder, peer *Peer) error {
+	res := new(VotesResponsePacket)
+	if err := msg.Decode(res); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	if !peer.fulfilRequest(res.RequestId) {
+		return fmt.Errorf("%w: votes response %d", errUnexpectedResponse, res.RequestId)
+	}
+	if len(res.Votes) > maxVotesServe {
+		res.Votes = res.Votes[:maxVotesServe]
+	}
+	peer.markVotes(res.Votes)
+	return backend.Handle(peer, res)
+}
+
+func handleVoteBundles(backend Backend, msg Decoder, peer *Peer) error {
+	ann := new(VoteBundlesPacket)
+	if err := msg.Decode(ann); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	votes := ann.Unpack()
+	penalizeRepeatedVotes(backend, peer, votes)
+	peer.markVotes(votes)
+	return backend.Handle(peer, ann)
+}
+
+func handleGetBlobSidecars(backend Backend, msg Decoder, peer *Peer) error {
+	req := new(GetBlobSidecarsPacket)
+	if err := msg.Decode(req); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	return peer.ReplyBlobSidecarsRLP(req.RequestId, ServiceGetBlobSidecarsQuery(backend.Chain(), req.Hashes))
+}
+
+// ServiceGetBlobSidecarsQuery assembles the response to a blob sidecars query.
+// The sidecars unavailable locally are responded empty to keep the order of
+// the request. It is exposed to allow external packages to test protocol
+// behavior.
+func ServiceGetBlobSidecarsQuery(chain *core.BlockChain, hashes []common.Hash) []rlp.RawValue {
+	// Gather sidecars until the fetch or network limits is reached
+	var (
+		bytes    int
+		sidecars []rlp.RawValue
+	)
+	for _, hash := range hashes {
+		if bytes >= softResponseLimit || len(sidecars) >= maxSidecarsServe {
+			break
+		}
+		results := chain.GetSidecarsRLP(hash)
+		if len(results) == 0 {
+			results = rlp.EmptyList
+		}
+		sidecars = append(sidecars, results)
+		bytes += len(results)
+	}
+	return sidecars
+}
+
+func handleBlobSidecars(backend Backend, msg Decoder, peer *Peer) error {
+	res := new(BlobSidecarsPacket)
+	if err := msg.Decode(res); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	if !peer.deliverSidecars(res) {
+		peer.Log().Debug("Dropped blob sidecars response of cancelled request", "reqid", res.RequestId)
+	}
+	return nil
+}
+
 // NodeInfo represents a short summary of the `bsc` sub-protocol metadata
 // known about the host peer.
 type NodeInfo struct{}
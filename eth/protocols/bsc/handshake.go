@@ -6,6 +6,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common/gopool"
 	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 const (
@@ -21,10 +22,18 @@ func (p *Peer) Handshake() error {
 
 	var cap BscCapPacket // safe to read after two values have been received from errc
 
+	extra := rlp.RawValue(defaultExtra)
+	if p.version >= Bsc2 {
+		enc, err := rlp.EncodeToBytes(&BscCapExtra{Features: localFeatures})
+		if err != nil {
+			return err
+		}
+		extra = enc
+	}
 	gopool.Submit(func() {
 		errc <- p2p.Send(p.rw, BscCapMsg, &BscCapPacket{
 			ProtocolVersion: p.version,
-			Extra:           defaultExtra,
+			Extra:           extra,
 		})
 	})
 	gopool.Submit(func() {
@@ -42,6 +51,14 @@ func (p *Peer) Handshake() error {
 			return p2p.DiscReadTimeout
 		}
 	}
+	// The features are negotiated since bsc/2, and the unknown extra is
+	// treated as no feature for the compatibility.
+	if p.version >= Bsc2 {
+		var ext BscCapExtra
+		if err := rlp.DecodeBytes(cap.Extra, &ext); err == nil {
+			p.features = ext.Features & localFeatures
+		}
+	}
 	return nil
 }
 
@@ -1,6 +1,10 @@
 package bsc
 
 import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
 	"time"
 
 	mapset "github.com/deckarep/golang-set/v2"
@@ -8,6 +12,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 const (
@@ -26,6 +31,14 @@ const (
 
 	// the time span of one period
 	secondsPerPeriod = float64(30)
+
+	// maxPendingVoteRequests is the maximum number of the vote requests waiting
+	// for the responses from one peer.
+	maxPendingVoteRequests = 16
+
+	// maxPendingSidecarRequests is the maximum number of the blob sidecars
+	// requests waiting for the responses from one peer.
+	maxPendingSidecarRequests = 4
 )
 
 // Peer is a collection of relevant information we have about a `bsc` peer.
@@ -35,6 +48,11 @@ type Peer struct {
 	voteBroadcast chan []*types.VoteEnvelope // Channel used to queue votes propagation requests
 	periodBegin   time.Time                  // Begin time of the latest period for votes counting
 	periodCounter uint                       // Votes number in the latest period
+	features      uint64                     // Features supported by both of the peers
+
+	requests        map[uint64]common.Hash                 // Block hashes of the pending vote requests
+	sidecarRequests map[uint64]chan<- []types.BlobSidecars // Sinks of the pending blob sidecars requests
+	requestLock     sync.Mutex                             // Protects the pending requests
 
 	*p2p.Peer                   // The embedded P2P package peer
 	rw        p2p.MsgReadWriter // Input/output streams for bsc
@@ -48,16 +66,18 @@ type Peer struct {
 func NewPeer(version uint, p *p2p.Peer, rw p2p.MsgReadWriter) *Peer {
 	id := p.ID().String()
 	peer := &Peer{
-		id:            id,
-		knownVotes:    newKnownCache(maxKnownVotes),
-		voteBroadcast: make(chan []*types.VoteEnvelope, voteBufferSize),
-		periodBegin:   time.Now(),
-		periodCounter: 0,
-		Peer:          p,
-		rw:            rw,
-		version:       version,
-		logger:        log.New("peer", id[:8]),
-		term:          make(chan struct{}),
+		id:              id,
+		knownVotes:      newKnownCache(maxKnownVotes),
+		voteBroadcast:   make(chan []*types.VoteEnvelope, voteBufferSize),
+		periodBegin:     time.Now(),
+		periodCounter:   0,
+		requests:        make(map[uint64]common.Hash),
+		sidecarRequests: make(map[uint64]chan<- []types.BlobSidecars),
+		Peer:            p,
+		rw:              rw,
+		version:         version,
+		logger:          log.New("peer", id[:8]),
+		term:            make(chan struct{}),
 	}
 	go peer.broadcastVotes()
 	return peer
@@ -101,13 +121,123 @@ func (p *Peer) markVotes(votes []*types.VoteEnvelope) {
 	}
 }
 
-// sendVotes propagates a batch of votes to the remote peer.
+// SupportsVoteBundles returns whether the votes can be sent to the peer in the bundles.
+func (p *Peer) SupportsVoteBundles() bool {
+	return p.features&FeatureVoteBundles != 0
+}
+
+// sendVotes propagates a batch of votes to the remote peer, in the bundles if
+// the peer supports them.
 func (p *Peer) sendVotes(votes []*types.VoteEnvelope) error {
 	// Mark all the votes as known, but ensure we don't overflow our limits
 	p.markVotes(votes)
+	if len(votes) > 1 && p.SupportsVoteBundles() {
+		return p2p.Send(p.rw, VoteBundlesMsg, &VoteBundlesPacket{NewVoteBundles(votes)})
+	}
 	return p2p.Send(p.rw, VotesMsg, &VotesPacket{votes})
 }
 
+// RequestVotesByBlockHash requests the votes for the block known by the peer.
+func (p *Peer) RequestVotesByBlockHash(hash common.Hash) error {
+	if p.version < Bsc2 {
+		return fmt.Errorf("votes request not supported by bsc/%d", p.version)
+	}
+	id := rand.Uint64()
+
+	p.requestLock.Lock()
+	if len(p.requests) >= maxPendingVoteRequests {
+		p.requestLock.Unlock()
+		return errors.New("too many pending vote requests")
+	}
+	p.requests[id] = hash
+	p.requestLock.Unlock()
+
+	p.Log().Trace("Fetching votes", "reqid", id, "hash", hash)
+	if err := p2p.Send(p.rw, GetVotesByBlockHashMsg, &GetVotesByBlockHashPacket{RequestId: id, BlockHash: hash}); err != nil {
+		p.fulfilRequest(id)
+		return err
+	}
+	return nil
+}
+
+// ReplyVotes responds the votes for the block requested by the peer.
+func (p *Peer) ReplyVotes(id uint64, votes []*types.VoteEnvelope) error {
+	if len(votes) > maxVotesServe {
+		votes = votes[:maxVotesServe]
+	}
+	p.markVotes(votes)
+	return p2p.Send(p.rw, VotesResponseMsg, &VotesResponsePacket{RequestId: id, Votes: votes})
+}
+
+// fulfilRequest removes the pending vote request, and returns whether it's requested.
+func (p *Peer) fulfilRequest(id uint64) bool {
+	p.requestLock.Lock()
+	defer p.requestLock.Unlock()
+
+	_, ok := p.requests[id]
+	delete(p.requests, id)
+	return ok
+}
+
+// SupportsBlobSidecars returns whether the blob sidecars can be requested from the peer.
+func (p *Peer) SupportsBlobSidecars() bool {
+	return p.version >= Bsc2
+}
+
+// RequestBlobSidecars requests the blob sidecars of the blocks from the peer.
+// The response is delivered to the sink, which should be buffered, unless the
+// request is cancelled by the returned function before. The late response of
+// a cancelled request is dropped.
+func (p *Peer) RequestBlobSidecars(hashes []common.Hash, sink chan<- []types.BlobSidecars) (func(), error) {
+	if !p.SupportsBlobSidecars() {
+		return nil, fmt.Errorf("blob sidecars request not supported by bsc/%d", p.version)
+	}
+	id := rand.Uint64()
+
+	p.requestLock.Lock()
+	if len(p.sidecarRequests) >= maxPendingSidecarRequests {
+		p.requestLock.Unlock()
+		return nil, errors.New("too many pending blob sidecars requests")
+	}
+	p.sidecarRequests[id] = sink
+	p.requestLock.Unlock()
+
+	cancel := func() {
+		p.requestLock.Lock()
+		delete(p.sidecarRequests, id)
+		p.requestLock.Unlock()
+	}
+	p.Log().Debug("Fetching batch of blob sidecars", "reqid", id, "count", len(hashes))
+	if err := p2p.Send(p.rw, GetBlobSidecarsMsg, &GetBlobSidecarsPacket{RequestId: id, Hashes: hashes}); err != nil {
+		cancel()
+		return nil, err
+	}
+	return cancel, nil
+}
+
+// ReplyBlobSidecarsRLP responds the encoded blob sidecars requested by the peer.
+func (p *Peer) ReplyBlobSidecarsRLP(id uint64, sidecars []rlp.RawValue) error {
+	return p2p.Send(p.rw, BlobSidecarsMsg, &BlobSidecarsRLPPacket{RequestId: id, Sidecars: sidecars})
+}
+
+// deliverSidecars passes the blob sidecars response to the pending request, and
+// returns whether it's requested.
+func (p *Peer) deliverSidecars(res *BlobSidecarsPacket) bool {
+	p.requestLock.Lock()
+	defer p.requestLock.Unlock()
+
+	sink, ok := p.sidecarRequests[res.RequestId]
+	if !ok {
+		return false
+	}
+	delete(p.sidecarRequests, res.RequestId)
+	select {
+	case sink <- res.Sidecars:
+	default:
+	}
+	return true
+}
+
 // AsyncSendVotes queues a batch of vote hashes for propagation to a remote peer. If
 // the peer's broadcast queue is full, the event is silently dropped.
 func (p *Peer) AsyncSendVotes(votes []*types.VoteEnvelope) {
@@ -3,6 +3,7 @@ package bsc
 import (
 	"errors"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
 )
@@ -10,6 +11,7 @@ import (
 // Constants to match up protocol versions and messages
 const (
 	Bsc1 = 1
+	Bsc2 = 2
 )
 
 // ProtocolName is the official short name of the `bsc` protocol used during
@@ -18,28 +20,64 @@ const ProtocolName = "bsc"
 
 // ProtocolVersions are the supported versions of the `bsc` protocol (first
 // is primary).
-var ProtocolVersions = []uint{Bsc1}
+var ProtocolVersions = []uint{Bsc2, Bsc1}
 
 // protocolLengths are the number of implemented message corresponding to
 // different protocol versions.
-var protocolLengths = map[uint]uint64{Bsc1: 2}
+var protocolLengths = map[uint]uint64{Bsc1: 2, Bsc2: 7}
 
 // maxMessageSize is the maximum cap on the size of a protocol message.
 const maxMessageSize = 10 * 1024 * 1024
 
+// maxVotesServe is the maximum number of votes served by a single response,
+// and the number of votes accepted from it.
+const maxVotesServe = 256
+
+// maxSidecarsServe is the maximum number of blocks whose blob sidecars are
+// served by a single response. With the sidecars of up to 768KB per block, the
+// practical limit will always be softResponseLimit.
+const maxSidecarsServe = 1024
+
+// softResponseLimit is the target maximum size of the blob sidecars response.
+const softResponseLimit = 2 * 1024 * 1024
+
 const (
 	BscCapMsg = 0x00 // bsc capability msg used upon handshake
 	VotesMsg  = 0x01
+
+	// Protocol messages introduced in bsc/2
+	GetVotesByBlockHashMsg = 0x02
+	VotesResponseMsg       = 0x03
+	VoteBundlesMsg         = 0x04
+	GetBlobSidecarsMsg     = 0x05
+	BlobSidecarsMsg        = 0x06
 )
 
 var defaultExtra = []byte{0x00}
 
+// Optional features advertised in the extra field of the capability message
+// since bsc/2. The bsc/1 peers send the default extra, which has no feature.
+const (
+	FeatureVoteBundles = 1 << iota // Votes can be sent in the bundles per target block
+)
+
+// localFeatures are the features supported by this node.
+const localFeatures = FeatureVoteBundles
+
+// BscCapExtra is the extra field of the capability message since bsc/2.
+type BscCapExtra struct {
+	Features uint64
+	// Ignore additional fields (for forward compatibility).
+	Rest []rlp.RawValue `rlp:"tail"`
+}
+
 var (
 	errNoBscCapMsg             = errors.New("no bsc capability message")
 	errMsgTooLarge             = errors.New("message too long")
 	errDecode                  = errors.New("invalid message")
 	errInvalidMsgCode          = errors.New("invalid message code")
 	errProtocolVersionMismatch = errors.New("protocol version mismatch")
+	errUnexpectedResponse      = errors.New("unexpected response")
 )
 
 // Packet represents a p2p message in the `bsc` protocol.
@@ -59,8 +97,112 @@ type VotesPacket struct {
 	Votes []*types.VoteEnvelope
 }
 
+// GetVotesByBlockHashPacket requests the votes for the block known by the peer.
+type GetVotesByBlockHashPacket struct {
+	RequestId uint64 // Request ID to match up responses with
+	BlockHash common.Hash
+}
+
+// VotesResponsePacket is the response of GetVotesByBlockHashPacket.
+type VotesResponsePacket struct {
+	RequestId uint64 // ID of the request this is a response for
+	Votes     []*types.VoteEnvelope
+}
+
+// GetBlobSidecarsPacket requests the blob sidecars of the blocks by the hashes.
+type GetBlobSidecarsPacket struct {
+	RequestId uint64 // Request ID to match up responses with
+	Hashes    []common.Hash
+}
+
+// BlobSidecarsPacket is the response of GetBlobSidecarsPacket, holding the
+// sidecars of each requested block in order. The sidecars of the blocks
+// unavailable at the remote node are empty, and the trailing ones may be
+// omitted to limit the response size.
+type BlobSidecarsPacket struct {
+	RequestId uint64 // ID of the request this is a response for
+	Sidecars  []types.BlobSidecars
+}
+
+// BlobSidecarsRLPPacket is BlobSidecarsPacket with the sidecars already encoded.
+type BlobSidecarsRLPPacket struct {
+	RequestId uint64
+	Sidecars  []rlp.RawValue
+}
+
+// BundledVote is a vote in the bundle sharing the vote data.
+type BundledVote struct {
+	VoteAddress types.BLSPublicKey
+	Signature   types.BLSSignature
+}
+
+// VoteBundle is the votes for the same vote data, which saves repeating the
+// data per validator. The signatures are kept individually instead of being
+// aggregated, because the vote pool aggregates them for the attestation.
+type VoteBundle struct {
+	Data  *types.VoteData
+	Votes []*BundledVote
+}
+
+// VoteBundlesPacket is the network packet for the vote bundles.
+type VoteBundlesPacket struct {
+	Bundles []*VoteBundle
+}
+
+// NewVoteBundles groups the votes into the bundles by the vote data.
+func NewVoteBundles(votes []*types.VoteEnvelope) []*VoteBundle {
+	var (
+		bundles []*VoteBundle
+		index   = make(map[common.Hash]*VoteBundle)
+	)
+	for _, vote := range votes {
+		hash := vote.Data.Hash()
+		bundle, ok := index[hash]
+		if !ok {
+			bundle = &VoteBundle{Data: vote.Data}
+			index[hash] = bundle
+			bundles = append(bundles, bundle)
+		}
+		bundle.Votes = append(bundle.Votes, &BundledVote{VoteAddress: vote.VoteAddress, Signature: vote.Signature})
+	}
+	return bundles
+}
+
+// Unpack returns the votes in the bundles.
+func (p *VoteBundlesPacket) Unpack() []*types.VoteEnvelope {
+	var votes []*types.VoteEnvelope
+	for _, bundle := range p.Bundles {
+		if bundle.Data == nil {
+			continue
+		}
+		for _, vote := range bundle.Votes {
+			data := *bundle.Data
+			votes = append(votes, &types.VoteEnvelope{VoteAddress: vote.VoteAddress, Signature: vote.Signature, Data: &data})
+		}
+	}
+	return votes
+}
+
 func (*BscCapPacket) Name() string { return "BscCap" }
 func (*BscCapPacket) Kind() byte   { return BscCapMsg }
 
 func (*VotesPacket) Name() string { return "Votes" }
 func (*VotesPacket) Kind() byte   { return VotesMsg }
+
+func (*GetVotesByBlockHashPacket) Name() string { return "GetVotesByBlockHash" }
+func (*GetVotesByBlockHashPacket) Kind() byte   { return GetVotesByBlockHashMsg }
+
+func (*VotesResponsePacket) Name() string { return "VotesResponse" }
+func (*VotesResponsePacket) Kind() byte   { return VotesResponseMsg }
+
+func (*VoteBundlesPacket) Name() string { return "VoteBundles" }
+func (*VoteBundlesPacket) Kind() byte   { return VoteBundlesMsg }
+
+func (*GetBlobSidecarsPacket) Name() string { return "GetBlobSidecars" }
+func (*GetBlobSidecarsPacket) Kind() byte   { return GetBlobSidecarsMsg }
+
+func (*BlobSidecarsPacket) Name() string { return "BlobSidecars" }
+func (*BlobSidecarsPacket) Kind() byte   { return BlobSidecarsMsg }
+
+func (*BlobSidecarsRLPPacket) Name() string { return "BlobSidecars" }
+func (*BlobSidecarsRLPPacket) Kind() byte   { return BlobSidecarsMsg }
@@ -130,3 +130,63 @@ func TestBsc1Messages(t *testing.T) {
 		}
 	}
 }
+
+// TestVoteBundles tests that the votes are grouped by the vote data, and are
+// recovered from the encoded bundles.
+func TestVoteBundles(t *testing.T) {
+	var (
+		data1 = &types.VoteData{SourceNumber: 0, SourceHash: common.Hash{0x1}, TargetNumber: 1, TargetHash: common.Hash{0x2}}
+		data2 = &types.VoteData{SourceNumber: 1, SourceHash: common.Hash{0x2}, TargetNumber: 2, TargetHash: common.Hash{0x3}}
+		votes = []*types.VoteEnvelope{
+			{VoteAddress: types.BLSPublicKey{0x1}, Signature: types.BLSSignature{0x1}, Data: data1},
+			{VoteAddress: types.BLSPublicKey{0x2}, Signature: types.BLSSignature{0x2}, Data: data2},
+			{VoteAddress: types.BLSPublicKey{0x3}, Signature: types.BLSSignature{0x3}, Data: data1},
+		}
+	)
+	bundles := NewVoteBundles(votes)
+	if len(bundles) != 2 {
+		t.Fatalf("bundle count mismatch: have %d, want 2", len(bundles))
+	}
+	if len(bundles[0].Votes) != 2 || len(bundles[1].Votes) != 1 {
+		t.Fatalf("bundled vote count mismatch: have %d and %d, want 2 and 1", len(bundles[0].Votes), len(bundles[1].Votes))
+	}
+	enc, err := rlp.EncodeToBytes(&VoteBundlesPacket{bundles})
+	if err != nil {
+		t.Fatalf("failed to encode bundles: %v", err)
+	}
+	var dec VoteBundlesPacket
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatalf("failed to decode bundles: %v", err)
+	}
+	have := make(map[common.Hash]bool)
+	for _, vote := range dec.Unpack() {
+		have[vote.Hash()] = true
+	}
+	if len(have) != len(votes) {
+		t.Fatalf("unpacked vote count mismatch: have %d, want %d", len(have), len(votes))
+	}
+	for _, vote := range votes {
+		if !have[vote.Hash()] {
+			t.Errorf("missing vote: %x", vote.Hash())
+		}
+	}
+}
+
+// TestBscCapExtra tests that the features are decoded from the handshake extra,
+// and that the extra of bsc/1 is not mistaken for the features.
+func TestBscCapExtra(t *testing.T) {
+	enc, err := rlp.EncodeToBytes(&BscCapExtra{Features: FeatureVoteBundles})
+	if err != nil {
+		t.Fatalf("failed to encode extra: %v", err)
+	}
+	var ext BscCapExtra
+	if err := rlp.DecodeBytes(enc, &ext); err != nil {
+		t.Fatalf("failed to decode extra: %v", err)
+	}
+	if ext.Features != FeatureVoteBundles {
+		t.Errorf("features mismatch: have %d, want %d", ext.Features, FeatureVoteBundles)
+	}
+	if err := rlp.DecodeBytes(defaultExtra, new(BscCapExtra)); err == nil {
+		t.Errorf("bsc/1 extra decoded as features")
+	}
+}
@@ -0,0 +1,220 @@
+package bsc
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// scoreBanThreshold is the score at which the peer is disconnected and banned.
+	scoreBanThreshold = -100
+
+	// scoreRecoveryPerSecond is the score recovered every second towards zero,
+	// which forgives the occasional offences of the honest peers.
+	scoreRecoveryPerSecond = 1
+
+	// scoreBanDuration is how long the peer is rejected after banned.
+	scoreBanDuration = time.Hour
+
+	// scoreForgetDelay is how long the recovered peers are kept after their last
+	// offence, so that the offences which don't lower the score are reported too.
+	scoreForgetDelay = time.Minute
+
+	// maxTrackedScores is the number of tracked peers over which the recovered
+	// ones are forgotten.
+	maxTrackedScores = 1024
+)
+
+// Offence is a misbehaviour of a peer in the vote protocol.
+type Offence uint8
+
+const (
+	OffenceInvalidSignature Offence = iota // Vote with an invalid BLS signature
+	OffenceInvalidVoter                    // Vote not signed by a validator
+	OffenceFarFutureVote                   // Vote for a block far ahead of the head
+	OffenceStaleVote                       // Vote for a block too old to be kept
+	OffenceRepeatedVote                    // Vote already sent by or to the peer
+	OffenceRateLimited                     // Vote over the receive rate limit
+	numOffences
+)
+
+var offenceNames = [numOffences]string{
+	OffenceInvalidSignature: "invalidSignature",
+	OffenceInvalidVoter:     "invalidVoter",
+	OffenceFarFutureVote:    "farFutureVote",
+	OffenceStaleVote:        "staleVote",
+	OffenceRepeatedVote:     "repeatedVote",
+	OffenceRateLimited:      "rateLimited",
+}
+
+// offencePenalties is the score lowered by an offence. The honest peers never
+// relay the invalid votes since they are verified before being propagated, but
+// can race to send the same vote. The far future and stale votes are only
+// tracked, since they look so to the node whose head lags behind the peers.
+var offencePenalties = [numOffences]float64{
+	OffenceInvalidSignature: 25,
+	OffenceInvalidVoter:     10,
+	OffenceFarFutureVote:    0,
+	OffenceStaleVote:        0,
+	OffenceRepeatedVote:     1,
+	OffenceRateLimited:      1,
+}
+
+var (
+	offenceMeters [numOffences]*metrics.Meter
+	banMeter      = metrics.NewRegisteredMeter("eth/protocols/bsc/score/ban", nil)
+)
+
+func init() {
+	for offence, name := range offenceNames {
+		offenceMeters[offence] = metrics.NewRegisteredMeter("eth/protocols/bsc/score/offence/"+name, nil)
+	}
+}
+
+// String implements fmt.Stringer.
+func (o Offence) String() string {
+	if o < numOffences {
+		return offenceNames[o]
+	}
+	return "unknown"
+}
+
+// PeerScorer is implemented by the backends which score the peers by their
+// offences, which are reported by the protocol handlers too.
+type PeerScorer interface {
+	Penalize(peer *Peer, offence Offence, count int)
+}
+
+// PeerScore is the vote protocol reputation of a peer.
+type PeerScore struct {
+	ID          string            `json:"id"`
+	Score       float64           `json:"score"`
+	Offences    map[string]uint64 `json:"offences"`
+	BannedUntil *time.Time        `json:"bannedUntil,omitempty"`
+}
+
+type peerScore struct {
+	score       float64
+	updated     time.Time
+	offended    time.Time
+	offences    [numOffences]uint64
+	bannedUntil time.Time
+}
+
+// recover raises the score towards zero by the time elapsed since the last update.
+func (s *peerScore) recover(now time.Time) {
+	s.score = min(0, s.score+now.Sub(s.updated).Seconds()*scoreRecoveryPerSecond)
+	s.updated = now
+}
+
+// PeerScores tracks the scores of the peers lowered by their offences, and bans
+// the peers whose scores fall to the threshold. The scores are kept across the
+// reconnections of the peers.
+type PeerScores struct {
+	scores map[string]*peerScore
+	ban    func(peer *Peer, duration time.Duration)
+	lock   sync.Mutex
+}
+
+// NewPeerScores creates a score tracker invoking the callback to ban a peer.
+func NewPeerScores(ban func(peer *Peer, duration time.Duration)) *PeerScores {
+	return &PeerScores{
+		scores: make(map[string]*peerScore),
+		ban:    ban,
+	}
+}
+
+// Penalize lowers the score of the peer by the offences, and bans it if the
+// score falls to the threshold.
+func (s *PeerScores) Penalize(peer *Peer, offence Offence, count int) {
+	if count <= 0 || offence >= numOffences {
+		return
+	}
+	offenceMeters[offence].Mark(int64(count))
+
+	s.lock.Lock()
+	now := time.Now()
+	score, ok := s.scores[peer.ID()]
+	if !ok {
+		if len(s.scores) >= maxTrackedScores {
+			s.prune(now)
+		}
+		score = &peerScore{updated: now}
+		s.scores[peer.ID()] = score
+	}
+	score.recover(now)
+	score.score -= offencePenalties[offence] * float64(count)
+	score.offences[offence] += uint64(count)
+	score.offended = now
+
+	current := score.score
+	banned := current <= scoreBanThreshold && now.After(score.bannedUntil)
+	if banned {
+		score.bannedUntil = now.Add(scoreBanDuration)
+	}
+	s.lock.Unlock()
+
+	peer.Log().Trace("Penalized peer", "offence", offence, "count", count, "score", current)
+	if banned {
+		peer.Log().Warn("Banning peer for vote spam", "score", current, "duration", scoreBanDuration)
+		banMeter.Mark(1)
+		s.ban(peer, scoreBanDuration)
+	}
+}
+
+// Scores returns the scores of the tracked peers, forgetting the recovered ones.
+func (s *PeerScores) Scores() []*PeerScore {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := time.Now()
+	s.prune(now)
+
+	scores := make([]*PeerScore, 0, len(s.scores))
+	for id, score := range s.scores {
+		result := &PeerScore{ID: id, Score: score.score, Offences: make(map[string]uint64)}
+		for offence, count := range score.offences {
+			if count > 0 {
+				result.Offences[offenceNames[offence]] = count
+			}
+		}
+		if now.Before(score.bannedUntil) {
+			until := score.bannedUntil
+			result.BannedUntil = &until
+		}
+		scores = append(scores, result)
+	}
+	sort.Slice(scores, func(i, j int) bool { return scores[i].Score < scores[j].Score })
+	return scores
+}
+
+// prune forgets the peers which are fully recovered, have not offended recently
+// and are not banned.
+func (s *PeerScores) prune(now time.Time) {
+	for id, score := range s.scores {
+		score.recover(now)
+		if score.score == 0 && now.Sub(score.offended) >= scoreForgetDelay && now.After(score.bannedUntil) {
+			delete(s.scores, id)
+		}
+	}
+}
+
+// penalizeRepeatedVotes reports the votes known to be known by the peer, which
+// it should not have sent.
+func penalizeRepeatedVotes(backend Backend, peer *Peer, votes []*types.VoteEnvelope) {
+	scorer, ok := backend.(PeerScorer)
+	if !ok {
+		return
+	}
+	var repeated int
+	for _, vote := range votes {
+		if peer.knownVotes.contains(vote.Hash()) {
+			repeated++
+		}
+	}
+	scorer.Penalize(peer, OffenceRepeatedVote, repeated)
+}
@@ -0,0 +1,53 @@
+package bsc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+func TestPeerScores(t *testing.T) {
+	var banned []string
+	scores := NewPeerScores(func(peer *Peer, duration time.Duration) {
+		banned = append(banned, peer.ID())
+	})
+	peer := NewPeer(Bsc2, p2p.NewPeer(enode.ID{1}, "", nil), nil)
+	defer peer.Close()
+	other := NewPeer(Bsc2, p2p.NewPeer(enode.ID{2}, "", nil), nil)
+	defer other.Close()
+
+	// The offences below the threshold are tracked without banning.
+	scores.Penalize(peer, OffenceInvalidSignature, 3)
+	scores.Penalize(other, OffenceRepeatedVote, 1)
+	if len(banned) != 0 {
+		t.Fatalf("banned below threshold: %v", banned)
+	}
+	list := scores.Scores()
+	if len(list) != 2 {
+		t.Fatalf("score count mismatch: have %d, want 2", len(list))
+	}
+	if list[0].ID != peer.ID() || list[0].Offences["invalidSignature"] != 3 {
+		t.Errorf("worst score mismatch: have %+v", list[0])
+	}
+
+	// The peer is banned once when the score falls to the threshold.
+	scores.Penalize(peer, OffenceInvalidSignature, 1)
+	scores.Penalize(peer, OffenceInvalidSignature, 1)
+	if len(banned) != 1 || banned[0] != peer.ID() {
+		t.Fatalf("banned peers mismatch: have %v", banned)
+	}
+	if list := scores.Scores(); list[0].BannedUntil == nil {
+		t.Errorf("ban not reported")
+	}
+
+	// The recovered peers are forgotten.
+	scores.lock.Lock()
+	scores.scores[other.ID()].updated = time.Now().Add(-scoreForgetDelay)
+	scores.scores[other.ID()].offended = time.Now().Add(-scoreForgetDelay)
+	scores.lock.Unlock()
+	if list := scores.Scores(); len(list) != 1 || list[0].ID != peer.ID() {
+		t.Errorf("recovered peer not forgotten: %v", list)
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/eth/protocols/bsc"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/log"
 )
@@ -50,8 +51,14 @@ func (h *handler) syncTransactions(p *eth.Peer) {
 	p.AsyncSendPooledTransactionHashes(hashes)
 }
 
-// syncVotes starts sending all currently pending votes to the given peer.
+// syncVotes starts sending all currently pending votes to the given peer, and
+// requests the votes for the current head if the peer supports it.
 func (h *handler) syncVotes(p *bscPeer) {
+	if p.Version() >= bsc.Bsc2 {
+		if err := p.RequestVotesByBlockHash(h.chain.CurrentHeader().Hash()); err != nil {
+			p.Log().Debug("Failed to request votes", "err", err)
+		}
+	}
 	votes := h.votepool.GetVotes()
 	if len(votes) == 0 {
 		return
@@ -0,0 +1,260 @@
+package eth
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/oasys"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// Maximum number of blocks indexed at once by the vote participation indexer,
+	// which skips the older ones after a long sync or a restart.
+	maxVoteParticipationBacklog = 1024
+	// Maximum number of epochs returned by a single `oasys_getVoteParticipation` call.
+	maxVoteParticipationRange = 100
+)
+
+var (
+	participationVotedMeter  = metrics.NewRegisteredMeter("oasys/participation/voted", nil)
+	participationLateMeter   = metrics.NewRegisteredMeter("oasys/participation/late", nil)
+	participationMissedMeter = metrics.NewRegisteredMeter("oasys/participation/missed", nil)
+)
+
+// VoteParticipation is the number of the blocks a validator voted for in the
+// attestation, voted for too late to be included, or missed in the epoch.
+type VoteParticipation struct {
+	Owner       common.Address     `json:"owner"`
+	Operator    common.Address     `json:"operator"`
+	VoteAddress types.BLSPublicKey `json:"voteAddress"`
+	Voted       uint64             `json:"voted"`
+	Late        uint64             `json:"late"`
+	Missed      uint64             `json:"missed"`
+}
+
+// EpochVoteParticipation is the vote participation of the validators in the
+// epoch. The blocks skipped by the indexer are not counted.
+type EpochVoteParticipation struct {
+	Epoch      uint64               `json:"epoch"`
+	Blocks     uint64               `json:"blocks"` // Number of the indexed blocks
+	LastBlock  uint64               `json:"lastBlock"`
+	Validators []*VoteParticipation `json:"validators"`
+}
+
+// add counts the votes for the block. The vote of a validator not included in
+// the attestation is late if it's received anyway.
+func (p *EpochVoteParticipation) add(number uint64, voters []*oasys.AttestationVoter, received func(types.BLSPublicKey) bool) (voted, late, missed []*VoteParticipation) {
+	p.Blocks++
+	p.LastBlock = number
+	for _, voter := range voters {
+		participation := p.validator(voter)
+		switch {
+		case voter.Voted:
+			participation.Voted++
+			voted = append(voted, participation)
+		case received(voter.VoteAddress):
+			participation.Late++
+			late = append(late, participation)
+		default:
+			participation.Missed++
+			missed = append(missed, participation)
+		}
+	}
+	return voted, late, missed
+}
+
+// copy returns a deep copy of the participation.
+func (p *EpochVoteParticipation) copy() *EpochVoteParticipation {
+	cpy := *p
+	cpy.Validators = make([]*VoteParticipation, len(p.Validators))
+	for i, participation := range p.Validators {
+		v := *participation
+		cpy.Validators[i] = &v
+	}
+	return &cpy
+}
+
+// validator returns the participation of the BLS key, adding it if missing.
+func (p *EpochVoteParticipation) validator(voter *oasys.AttestationVoter) *VoteParticipation {
+	for _, participation := range p.Validators {
+		if participation.VoteAddress == voter.VoteAddress {
+			participation.Owner, participation.Operator = voter.Owner, voter.Operator
+			return participation
+		}
+	}
+	participation := &VoteParticipation{Owner: voter.Owner, Operator: voter.Operator, VoteAddress: voter.VoteAddress}
+	p.Validators = append(p.Validators, participation)
+	return participation
+}
+
+// ReadEpochVoteParticipation retrieves the vote participation of the epoch, or
+// nil if it's not indexed.
+func ReadEpochVoteParticipation(db ethdb.KeyValueReader, epoch uint64) *EpochVoteParticipation {
+	data := rawdb.ReadVoteParticipationRLP(db, epoch)
+	if len(data) == 0 {
+		return nil
+	}
+	participation := new(EpochVoteParticipation)
+	if err := rlp.DecodeBytes(data, participation); err != nil {
+		log.Error("Invalid vote participation RLP", "epoch", epoch, "err", err)
+		return nil
+	}
+	return participation
+}
+
+// voteParticipationIndexer records the vote participation of the validators in
+// every finalized block.
+type voteParticipationIndexer struct {
+	eth     *Ethereum
+	engine  *oasys.Oasys
+	current *EpochVoteParticipation // Participation of the epoch being indexed
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newVoteParticipationIndexer(eth *Ethereum) (*voteParticipationIndexer, error) {
+	engine, ok := eth.engine.(*oasys.Oasys)
+	if !ok {
+		return nil, errors.New("vote participation is not supported by the consensus engine")
+	}
+	return &voteParticipationIndexer{eth: eth, engine: engine, quit: make(chan struct{})}, nil
+}
+
+// Start starts the background loop indexing the finalized blocks.
+func (i *voteParticipationIndexer) Start() {
+	i.wg.Add(1)
+	go i.loop()
+}
+
+// Stop terminates the background loop.
+func (i *voteParticipationIndexer) Stop() {
+	close(i.quit)
+	i.wg.Wait()
+}
+
+func (i *voteParticipationIndexer) loop() {
+	defer i.wg.Done()
+
+	headCh := make(chan core.ChainHeadEvent, 10)
+	sub := i.eth.blockchain.SubscribeChainHeadEvent(headCh)
+	defer sub.Unsubscribe()
+
+	i.indexFinalized()
+	for {
+		select {
+		case <-headCh:
+			i.indexFinalized()
+		case <-sub.Err():
+			return
+		case <-i.quit:
+			return
+		}
+	}
+}
+
+// indexFinalized indexes the blocks finalized since the last indexed one.
+func (i *voteParticipationIndexer) indexFinalized() {
+	finalized := i.eth.blockchain.CurrentFinalBlock()
+	if finalized == nil {
+		return
+	}
+	var (
+		db     = i.eth.chainDb
+		number = finalized.Number.Uint64()
+		next   = uint64(1)
+	)
+	if last := rawdb.ReadVoteParticipationHead(db); last != nil {
+		next = *last + 1
+	}
+	if next+maxVoteParticipationBacklog <= number {
+		log.Info("Skipped the vote participation of old blocks", "from", next, "to", number-maxVoteParticipationBacklog)
+		next = number - maxVoteParticipationBacklog + 1
+	}
+	for ; next <= number; next++ {
+		select {
+		case <-i.quit:
+			return
+		default:
+		}
+		if err := i.index(next); err != nil {
+			log.Warn("Failed to index vote participation", "number", next, "err", err)
+			return
+		}
+	}
+}
+
+// index records the votes for the block in its epoch.
+func (i *voteParticipationIndexer) index(number uint64) error {
+	var (
+		chain  = i.eth.blockchain
+		db     = i.eth.chainDb
+		header = chain.GetHeaderByNumber(number)
+		child  = chain.GetHeaderByNumber(number + 1)
+	)
+	if header == nil || child == nil {
+		return errors.New("block not found")
+	}
+	var (
+		batch               = db.NewBatch()
+		current             *EpochVoteParticipation
+		voted, late, missed []*VoteParticipation
+	)
+	_, voters, err := i.engine.AttestationVoters(chain, child)
+	if err != nil {
+		return err
+	}
+	if voters != nil {
+		env, err := i.engine.EnvironmentAt(chain, header)
+		if err != nil {
+			return err
+		}
+		// Count the votes on a copy, the cached totals are updated only once
+		// they are written, or a retry would count the block twice.
+		epoch := env.Epoch(number)
+		if i.current != nil && i.current.Epoch == epoch {
+			current = i.current.copy()
+		} else if current = ReadEpochVoteParticipation(db, epoch); current == nil {
+			current = &EpochVoteParticipation{Epoch: epoch}
+		}
+
+		received := make(map[types.BLSPublicKey]bool)
+		if i.eth.votePool != nil {
+			for _, vote := range i.eth.votePool.FetchVoteByBlockHash(header.Hash()) {
+				received[vote.VoteAddress] = true
+			}
+		}
+		voted, late, missed = current.add(number, voters, func(key types.BLSPublicKey) bool { return received[key] })
+		enc, err := rlp.EncodeToBytes(current)
+		if err != nil {
+			return err
+		}
+		rawdb.WriteVoteParticipationRLP(batch, epoch, enc)
+	}
+	rawdb.WriteVoteParticipationHead(batch, number)
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	if current == nil {
+		return nil
+	}
+	i.current = current
+
+	participationVotedMeter.Mark(int64(len(voted)))
+	participationLateMeter.Mark(int64(len(late)))
+	participationMissedMeter.Mark(int64(len(missed)))
+	for name, participations := range map[string][]*VoteParticipation{"voted": voted, "late": late, "missed": missed} {
+		for _, participation := range participations {
+			metrics.GetOrRegisterCounter("oasys/participation/"+participation.Operator.Hex()+"/"+name, nil).Inc(1)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package eth
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/oasys"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func TestEpochVoteParticipation(t *testing.T) {
+	var (
+		keyA = types.BLSPublicKey{1}
+		keyB = types.BLSPublicKey{2}
+		keyC = types.BLSPublicKey{3}
+	)
+	voters := func(voted ...bool) []*oasys.AttestationVoter {
+		var list []*oasys.AttestationVoter
+		for i, key := range []types.BLSPublicKey{keyA, keyB, keyC} {
+			list = append(list, &oasys.AttestationVoter{
+				Owner:       common.BytesToAddress([]byte{byte(i + 1)}),
+				Operator:    common.BytesToAddress([]byte{byte(i + 0x10)}),
+				VoteAddress: key,
+				Voted:       voted[i],
+			})
+		}
+		return list
+	}
+	received := func(keys ...types.BLSPublicKey) func(types.BLSPublicKey) bool {
+		return func(key types.BLSPublicKey) bool {
+			for _, k := range keys {
+				if k == key {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	participation := &EpochVoteParticipation{Epoch: 7}
+	voted, late, missed := participation.add(100, voters(true, true, false), received(keyC))
+	if len(voted) != 2 || len(late) != 1 || len(missed) != 0 {
+		t.Fatalf("voted/late/missed = %d/%d/%d, want 2/1/0", len(voted), len(late), len(missed))
+	}
+	participation.add(101, voters(true, false, false), received())
+
+	// The votes counted on a copy don't change the original totals
+	cpy := participation.copy()
+	cpy.add(102, voters(true, true, true), received())
+	if participation.Blocks != 2 || participation.Validators[0].Voted != 2 {
+		t.Fatalf("copy modified the original participation: %+v", participation)
+	}
+
+	// Round trip through the database
+	db := rawdb.NewMemoryDatabase()
+	enc, err := rlp.EncodeToBytes(participation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawdb.WriteVoteParticipationRLP(db, participation.Epoch, enc)
+	if ReadEpochVoteParticipation(db, 8) != nil {
+		t.Fatal("read the epoch not indexed")
+	}
+	stored := ReadEpochVoteParticipation(db, participation.Epoch)
+	if stored == nil || stored.Blocks != 2 || stored.LastBlock != 101 || len(stored.Validators) != 3 {
+		t.Fatalf("unexpected participation: %+v", stored)
+	}
+	for i, want := range [][3]uint64{{2, 0, 0}, {1, 0, 1}, {0, 1, 1}} {
+		v := stored.Validators[i]
+		if got := [3]uint64{v.Voted, v.Late, v.Missed}; got != want {
+			t.Errorf("validator %d: voted/late/missed = %v, want %v", i, got, want)
+		}
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"io"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -183,6 +184,18 @@ type AncientWriter interface {
 type FreezerEnv struct {
 	ChainCfg         *params.ChainConfig
 	BlobExtraReserve uint64
+	SidecarArchive   SidecarArchiver // Optional archive of the blob sidecars pruned
+}
+
+// SidecarArchiver retains the blob sidecars before pruned from the ancient store.
+type SidecarArchiver interface {
+	// SidecarsArchived returns the number of the block following the last
+	// archived one.
+	SidecarsArchived() uint64
+
+	// ArchiveSidecarsRLP appends the RLP-encoded sidecars of the block, which
+	// must follow the last archived one.
+	ArchiveSidecarsRLP(number uint64, hash common.Hash, sidecars []byte) error
 }
 
 // AncientFreezer defines the help functions for freezing ancient data
@@ -26,6 +26,7 @@ var Modules = map[string]string{
 	"net":    NetJs,
 	"rpc":    RpcJs,
 	"txpool": TxpoolJs,
+	"vote":   VoteJs,
 	"dev":    DevJs,
 }
 
@@ -158,6 +159,12 @@ web3._extend({
 			name: 'stopWS',
 			call: 'admin_stopWS'
 		}),
+		new web3._extend.Method({
+			name: 'reloadVoteKeys',
+			call: 'admin_reloadVoteKeys',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -172,6 +179,18 @@ web3._extend({
 			name: 'datadir',
 			getter: 'admin_datadir'
 		}),
+		new web3._extend.Property({
+			name: 'voteKeys',
+			getter: 'admin_voteKeys'
+		}),
+		new web3._extend.Property({
+			name: 'votePeerScores',
+			getter: 'admin_votePeerScores'
+		}),
+		new web3._extend.Property({
+			name: 'standbyStatus',
+			getter: 'admin_standbyStatus'
+		}),
 	]
 });
 `
@@ -663,7 +682,12 @@ web3._extend({
 			params: 1,
 		}),
 	],
-	properties: []
+	properties: [
+		new web3._extend.Property({
+			name: 'slotHealth',
+			getter: 'miner_slotHealth'
+		}),
+	]
 });
 `
 
@@ -725,6 +749,20 @@ web3._extend({
 });
 `
 
+const VoteJs = `
+web3._extend({
+	property: 'vote',
+	methods: [],
+	properties:
+	[
+		new web3._extend.Property({
+			name: 'poolStatus',
+			getter: 'vote_poolStatus'
+		}),
+	]
+});
+`
+
 const DevJs = `
 web3._extend({
 	property: 'dev',
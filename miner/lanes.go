@@ -0,0 +1,126 @@
+package miner
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	// laneGasGaugeName is the name pattern of the gas used by a lane in the
+	// latest block filled.
+	laneGasGaugeName = "worker/lane/%s/gas"
+
+	// laneTxsGaugeName is the name pattern of the transactions of a lane in the
+	// latest block filled.
+	laneTxsGaugeName = "worker/lane/%s/txs"
+)
+
+// setPriorityLanes sets the priority lanes with the block gas reserved.
+func (w *worker) setPriorityLanes(lanes []txpool.Lane) {
+	w.confMu.Lock()
+	defer w.confMu.Unlock()
+	w.lanes = txpool.NewLaneSet(lanes)
+}
+
+// laneTxs returns the pending transactions in the lane. The transactions of an
+// account are taken up to the first one out of the lane, as the rest must not
+// be committed ahead of it.
+func laneTxs(lanes *txpool.LaneSet, lane int, pending map[common.Address][]*txpool.LazyTransaction) map[common.Address][]*txpool.LazyTransaction {
+	txs := make(map[common.Address][]*txpool.LazyTransaction)
+	for from, list := range pending {
+		var n int
+		for n < len(list) {
+			// The destination of the unresolved ones, i.e. blob transactions, is
+			// not known, they are only in the lanes by the sender
+			var to *common.Address
+			if list[n].Tx != nil {
+				to = list[n].Tx.To()
+			}
+			if lanes.Match(from, to) != lane {
+				break
+			}
+			n++
+		}
+		if n > 0 {
+			txs[from] = list[:n]
+		}
+	}
+	return txs
+}
+
+// trimCommitted removes the transactions committed from the pending ones. The
+// committed ones are always at the head of the account.
+func trimCommitted(pending map[common.Address][]*txpool.LazyTransaction, committed map[common.Hash]struct{}) {
+	for from, list := range pending {
+		var n int
+		for n < len(list) {
+			if _, ok := committed[list[n].Hash]; !ok {
+				break
+			}
+			n++
+		}
+		if n == len(list) {
+			delete(pending, from)
+		} else if n > 0 {
+			pending[from] = list[n:]
+		}
+	}
+}
+
+// commitLanes fills the block with the transactions of the priority lanes in
+// order, each up to the gas share reserved for it. The transactions committed
+// are removed from the pending ones, and the rest compete with the others.
+func (w *worker) commitLanes(env *environment, lanes *txpool.LaneSet, plainTxs, blobTxs map[common.Address][]*txpool.LazyTransaction,
+	interruptCh chan int32, stopTimer *time.Timer) error {
+	if env.gasPool == nil {
+		env.gasPool = new(core.GasPool).AddGas(env.header.GasLimit)
+	}
+	for i := 0; i < lanes.Len(); i++ {
+		lane := lanes.Lane(i)
+		if lane.GasShare == 0 {
+			continue
+		}
+		var (
+			plain = laneTxs(lanes, i, plainTxs)
+			blob  = laneTxs(lanes, i, blobTxs)
+			start = len(env.txs)
+		)
+		if len(plain) > 0 || len(blob) > 0 {
+			// Commit the lane within the reserved gas, which may run out without
+			// the block being full
+			var (
+				gasPool  = env.gasPool
+				reserved = min(env.header.GasLimit*lane.GasShare/100, gasPool.Gas())
+			)
+			env.gasPool = new(core.GasPool).AddGas(reserved)
+			err := w.commitTransactions(env, newTransactionsByPriceAndNonce(env.signer, plain, env.header.BaseFee),
+				newTransactionsByPriceAndNonce(env.signer, blob, env.header.BaseFee), interruptCh, stopTimer)
+
+			gasPool.SubGas(reserved - env.gasPool.Gas())
+			env.gasPool = gasPool
+			if err != nil && !errors.Is(err, errBlockInterruptedByOutOfGas) {
+				return err
+			}
+		}
+		var (
+			committed = make(map[common.Hash]struct{}, len(env.txs)-start)
+			gasUsed   uint64
+		)
+		for j, tx := range env.txs[start:] {
+			committed[tx.Hash()] = struct{}{}
+			gasUsed += env.receipts[start+j].GasUsed
+		}
+		trimCommitted(plainTxs, committed)
+		trimCommitted(blobTxs, committed)
+
+		metrics.GetOrRegisterGauge(fmt.Sprintf(laneGasGaugeName, lane.Name), nil).Update(int64(gasUsed))
+		metrics.GetOrRegisterGauge(fmt.Sprintf(laneTxsGaugeName, lane.Name), nil).Update(int64(len(committed)))
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package miner
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestLaneTxs(t *testing.T) {
+	var (
+		relayer = common.Address{0x01}
+		user    = common.Address{0x02}
+		bridge  = common.Address{0x10}
+		other   = common.Address{0x20}
+		lanes   = txpool.NewLaneSet([]txpool.Lane{
+			{Name: "relayer", Senders: []common.Address{relayer}, GasShare: 20},
+			{Name: "bridge", Contracts: []common.Address{bridge}, GasShare: 20},
+		})
+	)
+	lazy := func(nonce uint64, to common.Address) *txpool.LazyTransaction {
+		tx := types.NewTransaction(nonce, to, big.NewInt(0), 21000, big.NewInt(1), nil)
+		return &txpool.LazyTransaction{Hash: tx.Hash(), Tx: tx}
+	}
+	pending := map[common.Address][]*txpool.LazyTransaction{
+		relayer: {lazy(0, other), lazy(1, bridge)},
+		user:    {lazy(0, bridge), lazy(1, other), lazy(2, bridge)},
+	}
+	// The transactions of the sender lane are all taken
+	if txs := laneTxs(lanes, 0, pending); len(txs) != 1 || len(txs[relayer]) != 2 {
+		t.Fatalf("relayer lane mismatch: %v", txs)
+	}
+	// The ones to the contract are taken up to the first one out of the lane
+	txs := laneTxs(lanes, 1, pending)
+	if len(txs) != 1 || len(txs[user]) != 1 || txs[user][0] != pending[user][0] {
+		t.Fatalf("bridge lane mismatch: %v", txs)
+	}
+	// The committed ones are removed from the head of the accounts
+	trimCommitted(pending, map[common.Hash]struct{}{
+		pending[relayer][0].Hash: {},
+		pending[relayer][1].Hash: {},
+		pending[user][0].Hash:    {},
+	})
+	if _, ok := pending[relayer]; ok {
+		t.Error("committed account not removed")
+	}
+	if list := pending[user]; len(list) != 2 || list[0].Tx.Nonce() != 1 {
+		t.Errorf("committed transactions not trimmed: %v", list)
+	}
+}
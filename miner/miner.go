@@ -20,11 +20,13 @@ package miner
 import (
 	"fmt"
 	"math/big"
+	"net/http"
 	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/oasys"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/txpool"
@@ -53,6 +55,7 @@ type Miner struct {
 	startCh chan struct{}
 	stopCh  chan struct{}
 	worker  *worker
+	slots   *slotMonitor // Nil unless the engine is Oasys
 
 	wg sync.WaitGroup
 }
@@ -67,6 +70,10 @@ func New(eth Backend, config *minerconfig.Config, mux *event.TypeMux, engine con
 		stopCh:  make(chan struct{}),
 		worker:  newWorker(config, engine, eth, mux, false, datadir),
 	}
+	if engine, ok := engine.(*oasys.Oasys); ok {
+		miner.slots = newSlotMonitor(eth.BlockChain(), engine, miner.worker.etherbase, miner.Mining, miner.worker.syncing.Load)
+		miner.slots.start()
+	}
 	miner.wg.Add(1)
 	go miner.update()
 	return miner
@@ -150,6 +157,9 @@ func (miner *Miner) Stop() {
 }
 
 func (miner *Miner) Close() {
+	if miner.slots != nil {
+		miner.slots.stop()
+	}
 	close(miner.exitCh)
 	miner.wg.Wait()
 }
@@ -158,6 +168,24 @@ func (miner *Miner) Mining() bool {
 	return miner.worker.isRunning()
 }
 
+// SlotHealth returns the readiness of the validator for its upcoming in-turn
+// slots, or nil if unknown.
+func (miner *Miner) SlotHealth() *SlotHealth {
+	if miner.slots == nil {
+		return nil
+	}
+	return miner.slots.Health()
+}
+
+// SlotHealthHandler returns the HTTP handler responding the slot health, or nil
+// if the slots are not monitored.
+func (miner *Miner) SlotHealthHandler() http.Handler {
+	if miner.slots == nil {
+		return nil
+	}
+	return miner.slots
+}
+
 // Pending returns the currently pending block and associated receipts, logs
 // and statedb. The returned values can be nil in case the pending block is
 // not initialized.
@@ -208,6 +236,11 @@ func (miner *Miner) SetPrioAddresses(prio []common.Address) {
 	miner.worker.setPrioAddresses(prio)
 }
 
+// SetPriorityLanes sets the priority lanes with the block gas reserved.
+func (miner *Miner) SetPriorityLanes(lanes []txpool.Lane) {
+	miner.worker.setPriorityLanes(lanes)
+}
+
 // SetGasCeil sets the gaslimit to strive for when mining blocks post 1559.
 // For pre-1559 blocks, it sets the ceiling.
 func (miner *Miner) SetGasCeil(ceil uint64) {
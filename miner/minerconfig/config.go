@@ -46,6 +46,14 @@ type Config struct {
 
 	DisableVoteAttestation    bool // Whether to skip assembling vote attestation
 	DisableSuspiciousTxFilter bool // Whether to disable suspicious tx filter
+
+	SuspiciousTxFilterBackend string `toml:",omitempty"` // Backend of suspicious tx filter: plugin, builtin or sidecar
+	SuspiciousTxFilterBuiltin string `toml:",omitempty"` // Name of the builtin suspicious tx filter
+	SuspiciousTxFilterSidecar string `toml:",omitempty"` // Endpoint of the suspicious tx filter sidecar
+	SuspiciousTxFilterShadow  bool   // Whether to journal the suspicious tx filter decisions without dropping transactions
+
+	SuspiciousTxFilterSources      []string `toml:",omitempty"` // Sources of the suspicious tx filter artifacts tried in order
+	SuspiciousTxFilterMetadataHash string   `toml:",omitempty"` // Hex encoded sha256 hash pinning the suspicious tx filter metadata
 }
 
 // DefaultConfig contains default settings for miner.
@@ -0,0 +1,375 @@
+package miner
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/oasys"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// slotCheckInterval is the interval to check the health without new blocks,
+	// which detects the node stuck behind the chain.
+	slotCheckInterval = 5 * time.Second
+
+	// slotLatencyBlocks is the number of the recent imported blocks whose import
+	// latency is averaged.
+	slotLatencyBlocks = 16
+
+	// slotReorgDepth is the number of the recent blocks whose sealers are checked
+	// again on every new head, in case they are reorged.
+	slotReorgDepth = 16
+
+	// maxHeadLagPeriods is the number of the block periods for which the head can
+	// be old without the node considered behind.
+	maxHeadLagPeriods = 3
+
+	// slotAlertInterval is the minimum interval of the repeated alerts.
+	slotAlertInterval = time.Minute
+
+	// slotForecastFraction is the fraction of the epoch, from its end, in which
+	// the slots of the next epoch are forecast.
+	slotForecastFraction = 4
+)
+
+var (
+	upcomingSlotsGauge  = metrics.NewRegisteredGauge("miner/slots/upcoming", nil)
+	atRiskSlotsGauge    = metrics.NewRegisteredGauge("miner/slots/atrisk", nil)
+	missedSlotsGauge    = metrics.NewRegisteredGauge("miner/slots/missed", nil)
+	jailBudgetGauge     = metrics.NewRegisteredGauge("miner/slots/jailbudget", nil)
+	importLatencyGauge  = metrics.NewRegisteredGauge("miner/slots/importlatency", nil)
+	nextEpochSlotsGauge = metrics.NewRegisteredGauge("miner/slots/nextepoch", nil)
+	healthyGauge        = metrics.NewRegisteredGauge("miner/slots/healthy", nil)
+)
+
+// SlotHealth is the readiness of the validator for its upcoming in-turn slots,
+// and the missed slots of the epoch counted against the jail threshold.
+type SlotHealth struct {
+	Validator common.Address `json:"validator"`
+	Healthy   bool           `json:"healthy"`
+	Problems  []string       `json:"problems,omitempty"`
+	Mining    bool           `json:"mining"`
+	Syncing   bool           `json:"syncing"`
+
+	Head          hexutil.Uint64 `json:"head"`
+	HeadAge       int64          `json:"headAge"`       // Seconds since the timestamp of the head
+	ImportLatency int64          `json:"importLatency"` // Average milliseconds from the timestamps to the imports of the recent blocks
+
+	Epoch        uint64          `json:"epoch"`
+	Slots        int             `json:"slots"`    // In-turn slots of the epoch
+	Sealed       int             `json:"sealed"`   // Past slots sealed by the validator
+	Missed       int             `json:"missed"`   // Past slots sealed by others, which are slashed
+	Upcoming     int             `json:"upcoming"` // Slots after the head
+	AtRisk       int             `json:"atRisk"`   // Upcoming slots to be missed unless the node recovers
+	NextSlot     *hexutil.Uint64 `json:"nextSlot,omitempty"`
+	NextSlotTime *hexutil.Uint64 `json:"nextSlotTime,omitempty"` // Estimated unix time of the next slot
+
+	JailThreshold uint64 `json:"jailThreshold"`
+	JailBudget    int64  `json:"jailBudget"` // Slots which can be missed before jailed
+
+	NextEpoch *oasys.EpochForecast `json:"nextEpoch,omitempty"` // Forecast in the last part of the epoch
+}
+
+// slotChain is the blockchain the slot monitor follows.
+type slotChain interface {
+	consensus.ChainHeaderReader
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+	PeekBlockStats(hash common.Hash) *core.BlockStats
+}
+
+// slotScheduler is the consensus engine scheduling the in-turn slots.
+type slotScheduler interface {
+	InTurnSlots(chain consensus.ChainHeaderReader, head *types.Header, validator common.Address) (*oasys.EpochSlots, error)
+	NextEpochSlots(chain consensus.ChainHeaderReader, head *types.Header, validator common.Address) (*oasys.EpochForecast, error)
+	Author(header *types.Header) (common.Address, error)
+}
+
+// slotMonitor follows the in-turn slots of the validator, and warns if they are
+// going to be missed, since the late node gets no notice before being slashed
+// and eventually jailed by the other nodes.
+type slotMonitor struct {
+	chain     slotChain
+	engine    slotScheduler
+	validator func() common.Address
+	mining    func() bool
+	syncing   func() bool
+
+	slots      *oasys.EpochSlots
+	owner      common.Address       // Validator of the slots
+	forecast   *oasys.EpochForecast // Slots of the next epoch, nil unless in the last part of the epoch
+	forecastAt common.Hash          // Head the forecast is made at
+	missed     map[uint64]bool      // Whether the past slots are missed
+	latencies  []int64              // Import latencies of the recent blocks in milliseconds
+	alerted    time.Time
+	health     atomic.Pointer[SlotHealth]
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newSlotMonitor(chain slotChain, engine slotScheduler, validator func() common.Address, mining, syncing func() bool) *slotMonitor {
+	return &slotMonitor{
+		chain:     chain,
+		engine:    engine,
+		validator: validator,
+		mining:    mining,
+		syncing:   syncing,
+		quit:      make(chan struct{}),
+	}
+}
+
+func (m *slotMonitor) start() {
+	m.wg.Add(1)
+	go m.loop()
+}
+
+func (m *slotMonitor) stop() {
+	close(m.quit)
+	m.wg.Wait()
+}
+
+func (m *slotMonitor) loop() {
+	defer m.wg.Done()
+
+	headCh := make(chan core.ChainHeadEvent, chainHeadChanSize)
+	sub := m.chain.SubscribeChainHeadEvent(headCh)
+	defer sub.Unsubscribe()
+
+	ticker := time.NewTicker(slotCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case ev := <-headCh:
+			m.recordLatency(ev.Header)
+			m.update(ev.Header, time.Now())
+		case <-ticker.C:
+			m.update(m.chain.CurrentHeader(), time.Now())
+		case <-sub.Err():
+			return
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// recordLatency records the time from the timestamp of the block to its import.
+// The blocks sealed locally are not imported, so not recorded.
+func (m *slotMonitor) recordLatency(header *types.Header) {
+	stats := m.chain.PeekBlockStats(header.Hash())
+	if stats == nil {
+		return
+	}
+	imported := stats.ImportedBlockTime.Load()
+	if imported == 0 {
+		return
+	}
+	m.latencies = append(m.latencies, max(0, imported-int64(header.Time)*1000))
+	if len(m.latencies) > slotLatencyBlocks {
+		m.latencies = m.latencies[1:]
+	}
+}
+
+// update refreshes the in-turn slots and the missed ones by the head, and
+// publishes the health.
+func (m *slotMonitor) update(head *types.Header, now time.Time) {
+	if head == nil {
+		return
+	}
+	validator := m.validator()
+	if validator == (common.Address{}) {
+		m.health.Store(nil)
+		return
+	}
+	number := head.Number.Uint64()
+	if m.slots == nil || m.owner != validator || number+1 < m.slots.StartBlock || number+1 > m.slots.EndBlock {
+		slots, err := m.engine.InTurnSlots(m.chain, head, validator)
+		if err != nil {
+			log.Debug("Failed to get the in-turn slots", "number", number, "err", err)
+			return
+		}
+		m.slots, m.owner, m.missed = slots, validator, make(map[uint64]bool)
+		m.forecast, m.forecastAt = nil, common.Hash{}
+		m.checkSlots(0, number)
+	} else {
+		m.checkSlots(number-min(number, slotReorgDepth), number)
+	}
+	m.updateForecast(head)
+	health := m.evaluate(head, now)
+	m.health.Store(health)
+	m.export(health)
+	m.alert(health, now)
+}
+
+// updateForecast forecasts the slots of the next epoch once the head is in the
+// last part of the epoch, at most once per head as it calls the contracts.
+func (m *slotMonitor) updateForecast(head *types.Header) {
+	var (
+		number = head.Number.Uint64()
+		length = m.slots.EndBlock - m.slots.StartBlock + 1
+	)
+	if number+1 <= m.slots.StartBlock || number+1+length/slotForecastFraction <= m.slots.EndBlock {
+		m.forecast, m.forecastAt = nil, common.Hash{}
+		return
+	}
+	if m.forecastAt == head.Hash() {
+		return
+	}
+	forecast, err := m.engine.NextEpochSlots(m.chain, head, m.owner)
+	if err != nil {
+		log.Debug("Failed to forecast the next epoch slots", "number", number, "err", err)
+		return
+	}
+	m.forecast, m.forecastAt = forecast, head.Hash()
+}
+
+// checkSlots checks the sealers of the past slots in the range.
+func (m *slotMonitor) checkSlots(from, to uint64) {
+	for _, slot := range m.slots.Slots {
+		if slot < from || slot > to {
+			continue
+		}
+		header := m.chain.GetHeaderByNumber(slot)
+		if header == nil {
+			continue
+		}
+		sealer, err := m.engine.Author(header)
+		if err != nil {
+			continue
+		}
+		m.missed[slot] = sealer != m.owner
+	}
+}
+
+// evaluate reports the health of the validator at the head.
+func (m *slotMonitor) evaluate(head *types.Header, now time.Time) *SlotHealth {
+	var (
+		number = head.Number.Uint64()
+		period = max(m.slots.BlockPeriod, 1)
+	)
+	health := &SlotHealth{
+		Validator:     m.owner,
+		Mining:        m.mining(),
+		Syncing:       m.syncing(),
+		Head:          hexutil.Uint64(number),
+		HeadAge:       now.Unix() - int64(head.Time),
+		Epoch:         m.slots.Epoch,
+		Slots:         len(m.slots.Slots),
+		JailThreshold: m.slots.JailThreshold,
+		NextEpoch:     m.forecast,
+	}
+	if len(m.latencies) > 0 {
+		var sum int64
+		for _, latency := range m.latencies {
+			sum += latency
+		}
+		health.ImportLatency = sum / int64(len(m.latencies))
+	}
+	for _, slot := range m.slots.Slots {
+		if slot > number {
+			if health.Upcoming == 0 {
+				next, at := hexutil.Uint64(slot), hexutil.Uint64(head.Time+(slot-number)*period)
+				health.NextSlot, health.NextSlotTime = &next, &at
+			}
+			health.Upcoming++
+			continue
+		}
+		missed, checked := m.missed[slot]
+		switch {
+		case !checked:
+		case !missed:
+			health.Sealed++
+		case m.slots.Slashing:
+			health.Missed++
+		}
+	}
+	health.JailBudget = int64(m.slots.JailThreshold) - int64(health.Missed)
+
+	if !health.Mining {
+		health.Problems = append(health.Problems, "not mining")
+	}
+	if health.Syncing {
+		health.Problems = append(health.Problems, "syncing")
+	}
+	if health.HeadAge > int64(maxHeadLagPeriods*period) {
+		health.Problems = append(health.Problems, fmt.Sprintf("head is %ds behind", health.HeadAge))
+	}
+	if health.ImportLatency > int64(period)*1000/2 {
+		health.Problems = append(health.Problems, fmt.Sprintf("blocks imported %dms late", health.ImportLatency))
+	}
+	health.Healthy = len(health.Problems) == 0
+	if !health.Healthy {
+		health.AtRisk = health.Upcoming
+	}
+	return health
+}
+
+func (m *slotMonitor) export(health *SlotHealth) {
+	upcomingSlotsGauge.Update(int64(health.Upcoming))
+	atRiskSlotsGauge.Update(int64(health.AtRisk))
+	missedSlotsGauge.Update(int64(health.Missed))
+	jailBudgetGauge.Update(health.JailBudget)
+	importLatencyGauge.Update(health.ImportLatency)
+	if health.NextEpoch != nil {
+		nextEpochSlotsGauge.Update(int64(health.NextEpoch.Expected))
+	} else {
+		nextEpochSlotsGauge.Update(0)
+	}
+	if health.Healthy {
+		healthyGauge.Update(1)
+	} else {
+		healthyGauge.Update(0)
+	}
+}
+
+// alert warns if the upcoming slots are at risk or the jail budget is running
+// out, at most once per interval.
+func (m *slotMonitor) alert(health *SlotHealth, now time.Time) {
+	budgetLow := health.Slots > 0 && health.Missed > 0 && health.JailBudget <= int64(health.JailThreshold)/2
+	if (health.AtRisk == 0 && !budgetLow) || now.Sub(m.alerted) < slotAlertInterval {
+		return
+	}
+	m.alerted = now
+
+	if health.AtRisk > 0 {
+		ctx := []any{"validator", health.Validator, "epoch", health.Epoch, "slots", health.AtRisk, "problems", health.Problems}
+		if health.NextSlot != nil {
+			ctx = append(ctx, "next", uint64(*health.NextSlot), "in", common.PrettyDuration(time.Until(time.Unix(int64(*health.NextSlotTime), 0)).Truncate(time.Second)))
+		}
+		log.Warn(fmt.Sprintf("Validator will miss %d in-turn slots in this epoch unless recovered", health.AtRisk), ctx...)
+	}
+	if budgetLow {
+		log.Warn("Validator is close to being jailed", "validator", health.Validator, "epoch", health.Epoch,
+			"missed", health.Missed, "threshold", health.JailThreshold, "budget", health.JailBudget)
+	}
+}
+
+// Health returns the latest health of the validator, or nil if unknown.
+func (m *slotMonitor) Health() *SlotHealth {
+	return m.health.Load()
+}
+
+// ServeHTTP implements http.Handler, responding the health of the validator with
+// the status 200 if healthy, or 503 otherwise.
+func (m *slotMonitor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	health := m.Health()
+	status := http.StatusOK
+	if health == nil || !health.Healthy {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(health)
+}
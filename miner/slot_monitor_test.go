@@ -0,0 +1,181 @@
+package miner
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/oasys"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+var (
+	testSlotValidator = common.HexToAddress("0x01")
+	testSlotOther     = common.HexToAddress("0x02")
+)
+
+type testSlotChain struct {
+	consensus.ChainHeaderReader
+	headers map[uint64]*types.Header
+}
+
+func (c *testSlotChain) GetHeaderByNumber(number uint64) *types.Header    { return c.headers[number] }
+func (c *testSlotChain) PeekBlockStats(hash common.Hash) *core.BlockStats { return nil }
+func (c *testSlotChain) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return nil
+}
+
+// testSlotScheduler schedules the epochs of 10 blocks, authored by the coinbases.
+type testSlotScheduler struct {
+	calls     int
+	forecasts int
+}
+
+func (s *testSlotScheduler) InTurnSlots(chain consensus.ChainHeaderReader, head *types.Header, validator common.Address) (*oasys.EpochSlots, error) {
+	s.calls++
+	start := (head.Number.Uint64() + 1) / 10 * 10
+	return &oasys.EpochSlots{
+		Epoch:         start / 10,
+		StartBlock:    start,
+		EndBlock:      start + 9,
+		BlockPeriod:   6,
+		JailThreshold: 3,
+		Slashing:      true,
+		Slots:         []uint64{start + 1, start + 3, start + 5, start + 8},
+	}, nil
+}
+
+func (s *testSlotScheduler) NextEpochSlots(chain consensus.ChainHeaderReader, head *types.Header, validator common.Address) (*oasys.EpochForecast, error) {
+	s.forecasts++
+	start := (head.Number.Uint64()/10 + 1) * 10
+	return &oasys.EpochForecast{
+		Epoch:       start / 10,
+		StartBlock:  start,
+		EndBlock:    start + 9,
+		BlockPeriod: 6,
+		Elected:     true,
+		Expected:    3,
+	}, nil
+}
+
+func (s *testSlotScheduler) Author(header *types.Header) (common.Address, error) {
+	return header.Coinbase, nil
+}
+
+func TestSlotMonitor(t *testing.T) {
+	chain := &testSlotChain{headers: make(map[uint64]*types.Header)}
+	for number := uint64(100); number <= 110; number++ {
+		sealer := testSlotValidator
+		if number == 103 {
+			sealer = testSlotOther
+		}
+		chain.headers[number] = &types.Header{Number: new(big.Int).SetUint64(number), Time: 1000 + number*6, Coinbase: sealer}
+	}
+	scheduler := new(testSlotScheduler)
+	mining := true
+	monitor := newSlotMonitor(chain, scheduler,
+		func() common.Address { return testSlotValidator },
+		func() bool { return mining },
+		func() bool { return false },
+	)
+
+	// Healthy at the head in time, with the slot 103 missed
+	head := chain.headers[105]
+	monitor.update(head, time.Unix(int64(head.Time)+2, 0))
+	health := monitor.Health()
+	if !health.Healthy || len(health.Problems) != 0 {
+		t.Fatalf("unhealthy: %v", health.Problems)
+	}
+	if health.Epoch != 10 || health.Slots != 4 || health.Sealed != 2 || health.Missed != 1 || health.Upcoming != 1 || health.AtRisk != 0 {
+		t.Fatalf("slots mismatch: %+v", health)
+	}
+	if health.NextSlot == nil || *health.NextSlot != 108 || *health.NextSlotTime != hexutil.Uint64(head.Time+18) {
+		t.Fatalf("next slot mismatch: %v at %v", health.NextSlot, health.NextSlotTime)
+	}
+	if health.JailThreshold != 3 || health.JailBudget != 2 {
+		t.Fatalf("jail budget mismatch: %d of %d", health.JailBudget, health.JailThreshold)
+	}
+
+	// The upcoming slots are at risk once the head gets old or the mining stops
+	monitor.update(head, time.Unix(int64(head.Time)+60, 0))
+	if health = monitor.Health(); health.Healthy || health.AtRisk != 1 || len(health.Problems) != 1 {
+		t.Fatalf("behind node is healthy: %+v", health)
+	}
+	mining = false
+	monitor.update(head, time.Unix(int64(head.Time)+2, 0))
+	if health = monitor.Health(); health.Healthy || health.AtRisk != 1 {
+		t.Fatalf("stopped node is healthy: %+v", health)
+	}
+	mining = true
+
+	// The next epoch is forecast only in the last part of the epoch, once per head
+	if health.NextEpoch != nil || scheduler.forecasts != 0 {
+		t.Fatalf("next epoch forecast in the middle of the epoch: %+v", health.NextEpoch)
+	}
+	head = chain.headers[107]
+	monitor.update(head, time.Unix(int64(head.Time), 0))
+	monitor.update(head, time.Unix(int64(head.Time)+1, 0))
+	health = monitor.Health()
+	if health.NextEpoch == nil || health.NextEpoch.Epoch != 11 || health.NextEpoch.StartBlock != 110 || health.NextEpoch.Expected != 3 {
+		t.Fatalf("next epoch forecast mismatch: %+v", health.NextEpoch)
+	}
+	if health.Epoch != 10 || scheduler.forecasts != 1 {
+		t.Fatalf("forecast not reported separately, epoch %d, forecasts %d", health.Epoch, scheduler.forecasts)
+	}
+
+	// The slots are rescheduled in the next epoch
+	if scheduler.calls != 1 {
+		t.Fatalf("slots rescheduled within the epoch, calls: %d", scheduler.calls)
+	}
+	head = chain.headers[109]
+	monitor.update(head, time.Unix(int64(head.Time), 0))
+	if scheduler.calls != 2 {
+		t.Fatalf("slots not rescheduled in the next epoch, calls: %d", scheduler.calls)
+	}
+	if health = monitor.Health(); health.Epoch != 11 || health.Missed != 0 || health.Upcoming != 4 || health.NextEpoch != nil {
+		t.Fatalf("next epoch mismatch: %+v", health)
+	}
+}
+
+func TestSlotMonitorHandler(t *testing.T) {
+	chain := &testSlotChain{headers: make(map[uint64]*types.Header)}
+	monitor := newSlotMonitor(chain, new(testSlotScheduler),
+		func() common.Address { return testSlotValidator },
+		func() bool { return true },
+		func() bool { return false },
+	)
+	server := httptest.NewServer(monitor)
+	defer server.Close()
+
+	check := func(status int) {
+		t.Helper()
+		res, err := http.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		if res.StatusCode != status {
+			t.Fatalf("status mismatch, have %d, want %d", res.StatusCode, status)
+		}
+		var health *SlotHealth
+		if err := json.NewDecoder(res.Body).Decode(&health); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check(http.StatusServiceUnavailable)
+
+	head := &types.Header{Number: big.NewInt(100), Time: 1000}
+	monitor.update(head, time.Unix(1000, 0))
+	check(http.StatusOK)
+
+	monitor.update(head, time.Unix(2000, 0))
+	check(http.StatusServiceUnavailable)
+}
@@ -186,6 +186,7 @@ type worker struct {
 	engine      consensus.Engine
 	eth         Backend
 	prio        []common.Address // A list of senders to prioritize
+	lanes       *txpool.LaneSet  // Priority lanes with the block gas reserved
 	chain       *core.BlockChain
 
 	// Feeds
@@ -366,7 +367,16 @@ func (w *worker) start() {
 	// transactions are filtered from the very first block.
 	if !w.config.DisableSuspiciousTxFilter && core.SuspiciousTxfilterGlobal == nil {
 		var err error
-		if core.SuspiciousTxfilterGlobal, err = core.NewSuspiciousTxfilter(w.chainConfig, w.txfilterDatadir, w.exitCh); err != nil {
+		txfilterConfig := &core.SuspiciousTxfilterConfig{
+			Backend: w.config.SuspiciousTxFilterBackend,
+			Builtin: w.config.SuspiciousTxFilterBuiltin,
+			Sidecar: w.config.SuspiciousTxFilterSidecar,
+			Shadow:  w.config.SuspiciousTxFilterShadow,
+
+			Sources:      w.config.SuspiciousTxFilterSources,
+			MetadataHash: w.config.SuspiciousTxFilterMetadataHash,
+		}
+		if core.SuspiciousTxfilterGlobal, err = core.NewSuspiciousTxfilter(w.chainConfig, txfilterConfig, w.txfilterDatadir, w.exitCh); err != nil {
 			// Stop execution here to avoid miner running without suspicious tx filter.
 			log.Crit("Failed to create suspicious tx filter", "err", err)
 		} else {
@@ -551,7 +561,11 @@ func (w *worker) taskLoop() {
 			w.pendingMu.Unlock()
 
 			if err := w.engine.Seal(w.chain, task.block, w.resultCh, stopCh); err != nil {
-				log.Warn("Block sealing failed", "err", err)
+				if errors.Is(err, consensus.ErrStandbyInactive) {
+					log.Debug("Block sealing skipped", "number", task.block.Number(), "err", err)
+				} else {
+					log.Warn("Block sealing failed", "err", err)
+				}
 				w.pendingMu.Lock()
 				delete(w.pendingTasks, sealHash)
 				w.pendingMu.Unlock()
@@ -1088,6 +1102,7 @@ func (w *worker) fillTransactions(interruptCh chan int32, env *environment, stop
 	w.confMu.RLock()
 	tip := w.tip
 	prio := w.prio
+	lanes := w.lanes
 	w.confMu.RUnlock()
 
 	// Retrieve the pending transactions pre-filtered by the 1559/4844 dynamic fees
@@ -1106,6 +1121,12 @@ func (w *worker) fillTransactions(interruptCh chan int32, env *environment, stop
 	filter.OnlyPlainTxs, filter.OnlyBlobTxs = false, true
 	pendingBlobTxs := w.eth.TxPool().Pending(filter)
 
+	// Fill the gas reserved for the priority lanes ahead of the others
+	if lanes.Len() > 0 {
+		if err := w.commitLanes(env, lanes, pendingPlainTxs, pendingBlobTxs, interruptCh, stopTimer); err != nil {
+			return err
+		}
+	}
 	// Split the pending transactions into locals and remotes.
 	prioPlainTxs, normalPlainTxs := make(map[common.Address][]*txpool.LazyTransaction), pendingPlainTxs
 	prioBlobTxs, normalBlobTxs := make(map[common.Address][]*txpool.LazyTransaction), pendingBlobTxs
@@ -24,6 +24,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -219,6 +220,18 @@ type Config struct {
 	// EnableMaliciousVoteMonitor is a flag that whether to enable the malicious vote checker
 	EnableMaliciousVoteMonitor bool `toml:",omitempty"`
 
+	// MaliciousVoteReporter is the account to submit the evidence of malicious votes
+	// to the SlashIndicator contract. The evidence is only logged if empty.
+	MaliciousVoteReporter string `toml:",omitempty"`
+
+	// EnableFinalityReporter is a flag that whether to export the vote propagation
+	// and the finality of every finalized block as the metrics and the logs
+	EnableFinalityReporter bool `toml:",omitempty"`
+
+	// EnableVoteParticipationIndexer is a flag that whether to index the vote
+	// participation of the validators in every finalized block
+	EnableVoteParticipationIndexer bool `toml:",omitempty"`
+
 	// BLSPasswordFile is the file that contains BLS wallet password.
 	BLSPasswordFile string `toml:",omitempty"`
 
@@ -227,10 +240,46 @@ type Config struct {
 	// current directory.
 	BLSWalletDir string `toml:",omitempty"`
 
+	// BLSRemoteSigner is the URL of the Web3Signer-compatible remote signer to
+	// sign the votes with. The local BLS wallet is not used if set.
+	BLSRemoteSigner string `toml:",omitempty"`
+
+	// BLSRemoteSignerPubKey is the comma separated BLS public keys held by the
+	// remote signer to vote with. The first key of the signer is used if empty.
+	BLSRemoteSignerPubKey string `toml:",omitempty"`
+
+	// BLSRemoteSignerClientCert and BLSRemoteSignerClientKey are the TLS client
+	// certificate and key files to authenticate to the remote signer.
+	BLSRemoteSignerClientCert string `toml:",omitempty"`
+	BLSRemoteSignerClientKey  string `toml:",omitempty"`
+
+	// BLSRemoteSignerCACert is the CA certificate file to verify the remote signer.
+	BLSRemoteSignerCACert string `toml:",omitempty"`
+
+	// BLSRemoteSignerTimeout is the timeout of a request to the remote signer.
+	BLSRemoteSignerTimeout time.Duration `toml:",omitempty"`
+
+	// StandbyLease is the path of the lease file or the URL of the lease server.
+	// If set, the node runs as a standby validator which seals and votes only
+	// while holding the lease.
+	StandbyLease string `toml:",omitempty"`
+
+	// StandbyLeaseTTL is the time for which the validator lease is acquired.
+	StandbyLeaseTTL time.Duration `toml:",omitempty"`
+
+	// StandbyHolder is the name of the node holding the validator lease. The
+	// node ID is used if empty.
+	StandbyHolder string `toml:",omitempty"`
+
 	// VoteJournalDir is the directory to store votes in the fast finality feature.
 	VoteJournalDir string `toml:",omitempty"`
 
-	// VoteKeyName is name of the BLS public key used for voting
+	// SlashingProtectionDir is the directory of the slashing protection database
+	// of the votes. The default is inside the instance directory.
+	SlashingProtectionDir string `toml:",omitempty"`
+
+	// VoteKeyName is the comma separated names or public keys of the BLS keys
+	// used for voting
 	VoteKeyName string `toml:",omitempty"`
 
 	// BatchRequestLimit is the maximum number of requests in a batch.
@@ -124,6 +124,7 @@ type Server struct {
 	// State of run loop and listenLoop.
 	inboundHistory     expHeap
 	disconnectEnodeSet map[enode.ID]struct{}
+	bannedPeers        map[enode.ID]time.Time // Expiry of the peers banned by BanPeer
 }
 
 type peerOpFunc func(map[enode.ID]*Peer)
@@ -288,6 +289,19 @@ func (srv *Server) RemovePeer(node *enode.Node) {
 	}
 }
 
+// BanPeer disconnects the peer if it's connected, and rejects its connections
+// until the ban expires. Trusted peers are banned too.
+func (srv *Server) BanPeer(id enode.ID, duration time.Duration) {
+	until := time.Now().Add(duration)
+	srv.doPeerOp(func(peers map[enode.ID]*Peer) {
+		srv.bannedPeers[id] = until
+		if peer := peers[id]; peer != nil {
+			peer.Disconnect(DiscUselessPeer)
+		}
+		srv.log.Debug("Banned p2p peer", "id", id, "until", until)
+	})
+}
+
 // AddTrustedPeer adds the given node to a reserved trusted list which allows the
 // node to always connect, even if the slot are full.
 func (srv *Server) AddTrustedPeer(node *enode.Node) {
@@ -426,6 +440,7 @@ func (srv *Server) Start() (err error) {
 	srv.peerOp = make(chan peerOpFunc)
 	srv.peerOpDone = make(chan struct{})
 	srv.disconnectEnodeSet = make(map[enode.ID]struct{})
+	srv.bannedPeers = make(map[enode.ID]time.Time)
 
 	if err := srv.setupLocalNode(); err != nil {
 		return err
@@ -841,6 +856,13 @@ func (srv *Server) addPeerChecks(peers map[enode.ID]*Peer, inboundCount int, c *
 		return errors.New("explicitly disconnected peer previously")
 	}
 
+	if until, ok := srv.bannedPeers[c.node.ID()]; ok {
+		if time.Now().Before(until) {
+			return errors.New("banned peer")
+		}
+		delete(srv.bannedPeers, c.node.ID())
+	}
+
 	if srv.peerNameFilter != nil {
 		for _, re := range srv.peerNameFilter {
 			if re.MatchString(c.name) {
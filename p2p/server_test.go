@@ -333,6 +333,45 @@ func TestServerAtCap(t *testing.T) {
 	}
 }
 
+// This test checks that the banned peers are rejected until the ban expires.
+func TestServerBanPeer(t *testing.T) {
+	remote := newkey()
+	srv := &Server{
+		Config: Config{
+			PrivateKey:  newkey(),
+			MaxPeers:    10,
+			NoDial:      true,
+			NoDiscovery: true,
+			Logger:      testlog.Logger(t, log.LvlTrace),
+		},
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("could not start: %v", err)
+	}
+	defer srv.Stop()
+
+	newconn := func(id enode.ID) *conn {
+		fd, _ := net.Pipe()
+		tx := newTestTransport(&remote.PublicKey, fd, nil)
+		node := enode.SignNull(new(enr.Record), id)
+		return &conn{fd: fd, transport: tx, flags: inboundConn, node: node, cont: make(chan error)}
+	}
+
+	banned, expired := randomID(), randomID()
+	srv.BanPeer(banned, time.Hour)
+	srv.BanPeer(expired, -time.Second)
+
+	if err := srv.checkpoint(newconn(banned), srv.checkpointAddPeer); err == nil {
+		t.Error("banned peer accepted")
+	}
+	if err := srv.checkpoint(newconn(expired), srv.checkpointAddPeer); err != nil {
+		t.Errorf("unexpected error for expired ban: %v", err)
+	}
+	if err := srv.checkpoint(newconn(randomID()), srv.checkpointAddPeer); err != nil {
+		t.Errorf("unexpected error for other peer: %v", err)
+	}
+}
+
 func TestServerPeerLimits(t *testing.T) {
 	srvkey := newkey()
 	clientkey := newkey()
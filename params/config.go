@@ -23,6 +23,7 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/params/forks"
 )
 
@@ -596,6 +597,30 @@ func (c CliqueConfig) String() string {
 type OasysConfig struct {
 	Period uint64 `json:"period"` // Number of seconds between blocks to enforce
 	Epoch  uint64 `json:"epoch"`  // Epoch length to reset votes and checkpoint
+
+	// Schedule of the built-in contract deployments. If not set, the schedule
+	// hard-coded for the genesis is used, e.g. for the mainnet and testnet.
+	Deployments []OasysDeployment `json:"deployments,omitempty"`
+}
+
+// OasysDeployment schedules the built-in contract deployment sets and the
+// contracts defined in the config at the block.
+type OasysDeployment struct {
+	Block      uint64                         `json:"block"`
+	Sets       []string                       `json:"sets,omitempty"`       // Names of the built-in deployment sets, deployed in order
+	Contracts  []OasysContract                `json:"contracts,omitempty"`  // Contracts deployed in order after the sets
+	CodeHashes map[common.Address]common.Hash `json:"codeHashes,omitempty"` // Expected keccak256 hash of the deployed code, checked at startup
+}
+
+// OasysContract is a contract deployed by the chain config. The storage maps the
+// slots to the values laid out the same way as the built-in contracts: a string
+// is a hex value or a text, and an object of a single "array", "mapping" or
+// "struct" member is the Solidity data structure of the nested values.
+type OasysContract struct {
+	Name    string                 `json:"name,omitempty"`
+	Address common.Address         `json:"address"`
+	Code    hexutil.Bytes          `json:"code,omitempty"`
+	Storage map[string]interface{} `json:"storage,omitempty"`
 }
 
 // String implements the stringer interface, returning the consensus engine details.
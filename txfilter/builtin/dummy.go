@@ -0,0 +1,92 @@
+// Package builtin registers the suspicious txfilters compiled into the binary,
+// which are selected with `--miner.txfilter.backend=builtin` and
+// `--miner.txfilter.builtin=<name>`.
+package builtin
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	txfilterlog "github.com/ethereum/go-ethereum/txfilter/log"
+)
+
+const (
+	// DummyName is the name of the filter passing every transaction.
+	DummyName = "dummy"
+
+	// DummyBlockingName is the name of the filter blocking every transaction,
+	// with the details of the transaction in the reason.
+	DummyBlockingName = "dummy-blocking"
+
+	// dummyVersion is the version of the dummy filters.
+	dummyVersion = "1.0.0"
+)
+
+func init() {
+	core.RegisterSuspiciousTxfilter(DummyName, func() (core.SuspiciousTxfilterPlugin, error) {
+		return &dummyTxfilter{}, nil
+	})
+	core.RegisterSuspiciousTxfilter(DummyBlockingName, func() (core.SuspiciousTxfilterPlugin, error) {
+		return &dummyTxfilter{blocking: true}, nil
+	})
+}
+
+type logEntry struct {
+	Address string   `json:"address"`
+	Topics  []string `json:"topics"`
+	Data    string   `json:"data"`
+}
+
+type reasonJSON struct {
+	From  string     `json:"from"`
+	To    string     `json:"to"`
+	Value string     `json:"value"`
+	Logs  []logEntry `json:"logs"`
+}
+
+// dummyTxfilter is the builtin counterpart of the dummy plugin, so that the
+// networks testing the filter can run without building the plugin.
+type dummyTxfilter struct {
+	blocking bool
+}
+
+// FilterTransaction implements core.SuspiciousTxfilterPlugin.
+func (f *dummyTxfilter) FilterTransaction(txhash common.Hash, from, to common.Address, value [32]byte, logs []txfilterlog.Log) (isBlocked bool, reason string, err error) {
+	if !f.blocking {
+		return false, "", nil
+	}
+	entries := make([]logEntry, len(logs))
+	for i, log := range logs {
+		entries[i] = logEntry{
+			Address: log.Address.Hex(),
+			Topics:  make([]string, len(log.Topics)),
+			Data:    hexutil.Encode(log.Data),
+		}
+		for j, topic := range log.Topics {
+			entries[i].Topics[j] = topic.Hex()
+		}
+	}
+	data, err := json.Marshal(reasonJSON{
+		From:  from.Hex(),
+		To:    to.Hex(),
+		Value: hexutil.Encode(value[:]),
+		Logs:  entries,
+	})
+	if err != nil {
+		return false, "", errors.New("failed to marshal reason JSON")
+	}
+	return true, string(data), nil
+}
+
+// Version implements core.SuspiciousTxfilterPlugin.
+func (*dummyTxfilter) Version() string {
+	return dummyVersion
+}
+
+// Clear implements core.SuspiciousTxfilterPlugin.
+func (*dummyTxfilter) Clear() error {
+	return nil
+}
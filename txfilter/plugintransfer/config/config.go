@@ -7,6 +7,9 @@ import (
 )
 
 // PluginConfig is loaded from remote JSON and controls all filter behavior.
+// The transaction is blocked if any of the rules matches. The transactions sent
+// to the allowed contracts are exempted from the thresholds and the threshold
+// and velocity rules, but not from the denied contracts.
 type PluginConfig struct {
 	Version           uint64                  `json:"version"`
 	Whitelists        map[common.Address]bool `json:"whitelists"`
@@ -14,6 +17,10 @@ type PluginConfig struct {
 	Threshold         ThresholdConfig         `json:"threshold"`
 	NativeToken       NativeTokenConfig       `json:"native_token"`
 	TargetERC20s      []TargetERC20Config     `json:"target_erc20s"`
+	TargetERC721s     []TargetNFTConfig       `json:"target_erc721s"`
+	TargetERC1155s    []TargetNFTConfig       `json:"target_erc1155s"`
+	AllowedContracts  []common.Address        `json:"allowed_contracts"`
+	Rules             []RuleConfig            `json:"rules"`
 	Disabled          bool                    `json:"disabled"`
 }
 
@@ -27,6 +34,40 @@ type TargetERC20Config struct {
 	ToYenRate float64        `json:"to_yen_rate"`
 }
 
+// TargetNFTConfig prices the ERC721 or ERC1155 token transfers.
+type TargetNFTConfig struct {
+	Address   common.Address `json:"address"`
+	ToYenRate float64        `json:"to_yen_rate"` // Per token for ERC721, per unit for ERC1155
+}
+
+// MaxRuleDepth is the maximum nesting depth of the and/or rules, the top level
+// rules are at the depth 1. The deeper rules are rejected on loading the config.
+const MaxRuleDepth = 8
+
+// RuleConfig is a declarative blocking rule, which is either a combination of
+// the sub rules by `and`/`or`, or a single condition. The threshold matches if
+// the transfers of the tokens exceed it in the window, and the velocity is the
+// same but measured per recipient of the transfers. The denied contracts match
+// if the transaction is sent to, or transfers the tokens of any of them.
+type RuleConfig struct {
+	Name            string               `json:"name"`
+	And             []RuleConfig         `json:"and"`
+	Or              []RuleConfig         `json:"or"`
+	Threshold       *RuleThresholdConfig `json:"threshold"`
+	Velocity        *RuleThresholdConfig `json:"velocity"`
+	DeniedContracts []common.Address     `json:"denied_contracts"`
+}
+
+// RuleThresholdConfig limits the transfers of the tokens within the window.
+// Ok, if the amount is same as the threshold.
+// Empty tokens mean all the target tokens, the zero address means the native token.
+type RuleThresholdConfig struct {
+	Tokens               []common.Address `json:"tokens"`
+	MeasurementWindow    time.Duration    `json:"measurement_window"`
+	BlockCountThreshold  uint             `json:"block_count_threshold"`
+	BlockAmountThreshold uint64           `json:"block_amount_threshold"`
+}
+
 // Ok, if the amount is same as the threshold
 type ThresholdConfig struct {
 	WarningCountThreshold  uint   `json:"warning_tx_count_threshold"`
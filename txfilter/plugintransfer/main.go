@@ -8,6 +8,7 @@ import (
 	"math"
 	"math/bits"
 	"net/http"
+	"slices"
 	"sync/atomic"
 	"time"
 
@@ -42,9 +43,15 @@ var (
 	// Zero value marker for common.Hash.
 	emptyHash common.Hash
 
-	// ERC20 Transfer(address,address,uint256) event signature.
+	// ERC20 Transfer(address,address,uint256) event signature, ERC721 uses the same one with the indexed token id.
 	transferEventTopic = common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
 
+	// ERC1155 TransferSingle(address,address,address,uint256,uint256) event signature.
+	transferSingleEventTopic = common.HexToHash("0xc3d58168c5ae7397731d063d5bbf3d657854427343f4c083240f7aacaa2d0f62")
+
+	// ERC1155 TransferBatch(address,address,address,uint256[],uint256[]) event signature.
+	transferBatchEventTopic = common.HexToHash("0x4a39dc06d4c0dbc64b70af90fd698a233a518aa5d07e595d983b8c0526c8f7fb")
+
 	// transferPlugin implements core.Plugin interface.
 	// _ core.SuspiciousTxfilterPlugin = (*transferPlugin)(nil)
 )
@@ -54,7 +61,8 @@ var (
 type transferPlugin struct {
 	version     *string
 	configCache ConfigCache
-	countedTxs  *lrucache // Recent counted transactions used for threshold/window checks.
+	countedTxs  *lrucache   // Recent counted transactions used for threshold/window checks.
+	rules       *ruleEngine // Declarative rules of the config, rebuilt when the config version changes.
 }
 
 // Version returns the version of the plugin.
@@ -69,6 +77,7 @@ func (p *transferPlugin) Clear() error {
 	p.configCache.Config = config.PluginConfig{}
 	p.configCache.updatedAt = time.Time{}
 	p.countedTxs = nil
+	p.rules = nil
 	return nil
 }
 
@@ -76,6 +85,28 @@ func (p *transferPlugin) Clear() error {
 // It refreshes config when needed, accumulates tx amount in JPY, and
 // blocks only when configured count/amount thresholds are exceeded.
 func (p *transferPlugin) FilterTransaction(txhash common.Hash, from, to common.Address, value [32]byte, logs []types.Log) (isBlocked bool, reason string, err error) {
+	isBlocked, reason, _, err = p.FilterTransactionWithAmount(txhash, from, to, value, logs)
+	return isBlocked, reason, err
+}
+
+// FilterTransactionWithAmount is FilterTransaction also reporting the JPY-equivalent
+// amount of the transaction to the host, which records it in the decision journal.
+// The amount is nil if the config is not available.
+func (p *transferPlugin) FilterTransactionWithAmount(txhash common.Hash, from, to common.Address, value [32]byte, logs []types.Log) (isBlocked bool, reason string, amount *uint64, err error) {
+	if err := p.refresh(); err != nil {
+		// No block, but notify the error to the caller
+		return false, "", nil, err
+	}
+	var (
+		transfers      = p.configCache.transfers(from, to, value, logs)
+		accumulatedYen = sumYen(transfers)
+	)
+	isBlocked, reason, err = p.filter(txhash, from, to, logs, transfers, accumulatedYen)
+	return isBlocked, reason, &accumulatedYen, err
+}
+
+// refresh updates the config if it's expired, and rebuilds the rules of it.
+func (p *transferPlugin) refresh() error {
 	// Initialize or update it if it's expired
 	if p.configCache.isExpired() {
 		// Asychronously download config, to avoid blocking the chain execution
@@ -84,7 +115,7 @@ func (p *transferPlugin) FilterTransaction(txhash common.Hash, from, to common.A
 		if err := p.configCache.update(&p.countedTxs); err != nil {
 			if p.configCache.isEmptyConfig() {
 				// No block, just return error to not block chain execution but notify the error to the caller
-				return false, "", fmt.Errorf("failed to fetch config: %w", err)
+				return fmt.Errorf("failed to fetch config: %w", err)
 			}
 			// Just log the error, keep using the old config
 			log.Error("failed to update config", "error", err, "url", configURL)
@@ -93,52 +124,59 @@ func (p *transferPlugin) FilterTransaction(txhash common.Hash, from, to common.A
 
 	// Exit if the config is empty
 	if p.countedTxs == nil || p.configCache.isEmptyConfig() {
-		// No block, but notify the error to the caller
-		return false, "", fmt.Errorf("config is empty")
+		return fmt.Errorf("config is empty")
+	}
+
+	// Rebuild the rules, as their windows are sized by the config
+	if p.rules == nil || p.rules.version != p.configCache.Config.Version {
+		rules, err := newRuleEngine(&p.configCache.Config)
+		if err != nil {
+			return fmt.Errorf("invalid rules: %w", err)
+		}
+		p.rules = rules
 	}
+	return nil
+}
 
+// filter decides whether to block the transaction of the transfers by the
+// rules and the thresholds of the config.
+func (p *transferPlugin) filter(txhash common.Hash, from, to common.Address, logs []types.Log, transfers []transfer, accumulatedYen uint64) (isBlocked bool, reason string, err error) {
 	// Skip if the plugin is disabled, or whitelisted, or already counted
-	if p.configCache.Config.Disabled || p.configCache.isWhitelisted(from) || p.countedTxs.contains(txhash) {
+	if p.configCache.Config.Disabled || p.configCache.isWhitelisted(from) ||
+		p.countedTxs.contains(txhash) || p.rules.seen(txhash) {
 		return allow()
 	}
 
-	// initialize accumulated amount by native token amount
-	accumulatedYen := p.configCache.toYen(nil, value)
+	var (
+		now     = time.Now().Unix()
+		allowed = p.configCache.isAllowedContract(to)
+		tx      = &ruleTx{hash: txhash, to: to, allowed: allowed, transfers: transfers, logs: logs}
+	)
 
-	for i := range logs {
-		// Skip if the log is not a transfer event
-		if len(logs[i].Topics) != 3 || logs[i].Topics[0] != transferEventTopic {
-			continue
-		}
-		// Skip if the log is not a target ERC20
-		target, ok := p.configCache.isTargetERC20(logs[i].Address)
-		if !ok {
-			continue
-		}
-		// The amount is stored in `log.Data` as a 32-byte value.
-		// sanity check: this should not occur in a standard ERC20 transfer
-		if len(logs[i].Data) != 32 {
-			continue
-		}
-		// Add accumulated amount by ERC20 token amount
-		var amount [32]byte
-		copy(amount[:], logs[i].Data)
-		accumulatedYen += p.configCache.toYen(target, amount)
+	// Check the rules, the transfers below 1 yen may still match them
+	if blocks, reason := p.rules.match(tx, now); blocks {
+		return block(reason)
+	}
+
+	// Skip the thresholds if sent to the allowed contract, which is not counted
+	if allowed {
+		return allow()
 	}
 
 	// Skip if the accumulated amount is zero or smaller than 1 yen
 	if accumulatedYen == 0 {
+		p.rules.record(tx, now)
 		return allow()
 	}
 
 	// Check the count and amount thresholds
-	now := time.Now().Unix()
 	if blocks, reason := isOverThreshold(p.countedTxs, &p.configCache.Config, accumulatedYen, now); blocks {
 		return block(reason)
 	}
 
 	// Count the transaction
 	p.countedTxs.push(txhash, accumulatedYen, now)
+	p.rules.record(tx, now)
 
 	return allow()
 }
@@ -264,6 +302,10 @@ func (c *ConfigCache) isWhitelisted(address common.Address) bool {
 	return c.Config.Whitelists[address]
 }
 
+func (c *ConfigCache) isAllowedContract(address common.Address) bool {
+	return slices.Contains(c.Config.AllowedContracts, address)
+}
+
 // isTargetERC20 returns target token config by contract address.
 func (c *ConfigCache) isTargetERC20(address common.Address) (*config.TargetERC20Config, bool) {
 	for i := range c.Config.TargetERC20s {
@@ -274,6 +316,150 @@ func (c *ConfigCache) isTargetERC20(address common.Address) (*config.TargetERC20
 	return nil, false
 }
 
+// isTargetNFT returns target NFT config by contract address.
+func isTargetNFT(targets []config.TargetNFTConfig, address common.Address) (*config.TargetNFTConfig, bool) {
+	for i := range targets {
+		if targets[i].Address == address {
+			return &targets[i], true
+		}
+	}
+	return nil, false
+}
+
+// transfer is a priced transfer of the native token or a target token.
+type transfer struct {
+	token    common.Address // Zero address for the native token
+	from, to common.Address
+	yen      uint64
+}
+
+// transfers extracts the native token transfer and the target token transfers
+// from the ERC20/ERC721 Transfer and ERC1155 TransferSingle/TransferBatch events.
+func (c *ConfigCache) transfers(from, to common.Address, value [32]byte, logs []types.Log) []transfer {
+	var transfers []transfer
+	if value != [32]byte{} {
+		transfers = append(transfers, transfer{from: from, to: to, yen: c.toYen(nil, value)})
+	}
+
+	for i := range logs {
+		l := &logs[i]
+		if len(l.Topics) == 0 {
+			continue
+		}
+		switch {
+		case l.Topics[0] == transferEventTopic && len(l.Topics) == 3:
+			// Skip if the log is not a target ERC20
+			target, ok := c.isTargetERC20(l.Address)
+			if !ok {
+				continue
+			}
+			// The amount is stored in `log.Data` as a 32-byte value.
+			// sanity check: this should not occur in a standard ERC20 transfer
+			if len(l.Data) != 32 {
+				continue
+			}
+			var amount [32]byte
+			copy(amount[:], l.Data)
+			transfers = append(transfers, transfer{token: l.Address, from: topicAddress(l.Topics[1]), to: topicAddress(l.Topics[2]), yen: c.toYen(target, amount)})
+
+		case l.Topics[0] == transferEventTopic && len(l.Topics) == 4:
+			// ERC721 transfers a single token identified by the indexed token id
+			target, ok := isTargetNFT(c.Config.TargetERC721s, l.Address)
+			if !ok {
+				continue
+			}
+			transfers = append(transfers, transfer{token: l.Address, from: topicAddress(l.Topics[1]), to: topicAddress(l.Topics[2]), yen: nftToYen(target, 1)})
+
+		case (l.Topics[0] == transferSingleEventTopic || l.Topics[0] == transferBatchEventTopic) && len(l.Topics) == 4:
+			target, ok := isTargetNFT(c.Config.TargetERC1155s, l.Address)
+			if !ok {
+				continue
+			}
+			var units uint64
+			if l.Topics[0] == transferSingleEventTopic {
+				// The data is the token id and the amount
+				if len(l.Data) != 64 {
+					continue
+				}
+				var amount [32]byte
+				copy(amount[:], l.Data[32:])
+				units = amountFromRaw(amount, 0)
+			} else {
+				amounts, ok := decodeBatchAmounts(l.Data)
+				if !ok {
+					continue
+				}
+				for _, amount := range amounts {
+					units = saturatingAdd(units, amountFromRaw(amount, 0))
+				}
+			}
+			// The topics are the operator, the sender and the recipient
+			transfers = append(transfers, transfer{token: l.Address, from: topicAddress(l.Topics[2]), to: topicAddress(l.Topics[3]), yen: nftToYen(target, units)})
+		}
+	}
+	return transfers
+}
+
+// decodeBatchAmounts decodes the amounts of the ERC1155 TransferBatch event,
+// whose data is the ABI encoded `(uint256[] ids, uint256[] values)`.
+func decodeBatchAmounts(data []byte) ([][32]byte, bool) {
+	if len(data) < 64 {
+		return nil, false
+	}
+	offset, ok := abiUint(data[32:64])
+	if !ok || offset > uint64(len(data))-32 {
+		return nil, false
+	}
+	n, ok := abiUint(data[offset : offset+32])
+	if !ok || n > (uint64(len(data))-offset-32)/32 {
+		return nil, false
+	}
+	amounts := make([][32]byte, n)
+	for i := range amounts {
+		start := offset + 32 + uint64(i)*32
+		copy(amounts[i][:], data[start:start+32])
+	}
+	return amounts, true
+}
+
+// abiUint decodes the 32-byte word as uint64, reporting false if it overflows.
+func abiUint(word []byte) (uint64, bool) {
+	for _, b := range word[:24] {
+		if b != 0 {
+			return 0, false
+		}
+	}
+	var v uint64
+	for _, b := range word[24:] {
+		v = v<<8 | uint64(b)
+	}
+	return v, true
+}
+
+func topicAddress(topic common.Hash) common.Address {
+	return common.BytesToAddress(topic[12:])
+}
+
+func sumYen(transfers []transfer) uint64 {
+	var sum uint64
+	for i := range transfers {
+		sum = saturatingAdd(sum, transfers[i].yen)
+	}
+	return sum
+}
+
+func saturatingAdd(a, b uint64) uint64 {
+	if sum, carry := bits.Add64(a, b, 0); carry == 0 {
+		return sum
+	}
+	return math.MaxUint64
+}
+
+// nftToYen converts the number of NFT units to integer JPY units (truncating fractional part).
+func nftToYen(target *config.TargetNFTConfig, units uint64) uint64 {
+	return uint64(float64(units) * target.ToYenRate)
+}
+
 // toYen converts token raw amount to integer JPY units (truncating fractional part).
 func (c *ConfigCache) toYen(target *config.TargetERC20Config, value [32]byte) uint64 {
 	if target == nil { // native token
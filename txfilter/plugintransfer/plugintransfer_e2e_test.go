@@ -135,6 +135,12 @@ func TestPluginTransferE2E(t *testing.T) {
 	t.Run("AmountThreshold", func(t *testing.T) {
 		runAmountThresholdScenario(t, ctx)
 	})
+
+	// Denied contract scenario, only if the config denies a contract by a top-level rule
+	t.Logf("Starting DeniedContract scenario...")
+	t.Run("DeniedContract", func(t *testing.T) {
+		runDeniedContractScenario(t, ctx)
+	})
 }
 
 func runCountThresholdScenario(t *testing.T, ctx context.Context) {
@@ -196,6 +202,32 @@ func runAmountThresholdScenario(t *testing.T, ctx context.Context) {
 	}
 }
 
+func runDeniedContractScenario(t *testing.T, ctx context.Context) {
+	var denied common.Address
+	for _, rule := range cfg.Rules {
+		if len(rule.DeniedContracts) > 0 {
+			denied = rule.DeniedContracts[0]
+			break
+		}
+	}
+	if denied == (common.Address{}) {
+		t.Skip("no denied contract in config rules")
+	}
+	nativeValue, _ := computeYenValue(cfg.NativeToken, targetERC20Config, 1)
+
+	// Any transaction sent to the denied contract should be blocked
+	receipt := sendNativeTransferToAndWaitReceipt(t, ctx, privKyes[0], denied, &nativeValue)
+	if receipt != nil {
+		t.Fatalf("denied contract: tx to %s should be blocked (no receipt within %v), but got receipt block %d", denied.Hex(), blockedTxTimeout, receipt.BlockNumber.Uint64())
+	}
+
+	// Whitelist can still send
+	receipt = sendNativeTransferToAndWaitReceipt(t, ctx, whitelistKeys[0], denied, &nativeValue)
+	if receipt == nil {
+		t.Fatalf("denied contract: tx from whitelist should be mined within %v", blockedTxTimeout)
+	}
+}
+
 // transferKind is the random choice for what to send in sendRandomTransferAndWaitReceipt.
 type transferKind int
 
@@ -220,6 +252,12 @@ func sendTransferAndWaitReceipt(t *testing.T, ctx context.Context, key *ecdsaKey
 
 // sendNativeTransferAndWaitReceipt sends a simple ETH transfer and polls for receipt.
 func sendNativeTransferAndWaitReceipt(t *testing.T, ctx context.Context, key *ecdsaKey, value *big.Int) *types.Receipt {
+	t.Helper()
+	return sendNativeTransferToAndWaitReceipt(t, ctx, key, destinationAddress, value)
+}
+
+// sendNativeTransferToAndWaitReceipt sends a simple ETH transfer to the address and polls for receipt.
+func sendNativeTransferToAndWaitReceipt(t *testing.T, ctx context.Context, key *ecdsaKey, to common.Address, value *big.Int) *types.Receipt {
 	t.Helper()
 	nonce, err := client.PendingNonceAt(ctx, key.addr)
 	if err != nil {
@@ -231,7 +269,7 @@ func sendNativeTransferAndWaitReceipt(t *testing.T, ctx context.Context, key *ec
 	}
 	tx := types.NewTx(&types.LegacyTx{
 		Nonce:    nonce,
-		To:       &destinationAddress,
+		To:       &to,
 		Value:    value,
 		Gas:      params.TxGas,
 		GasPrice: gasPrice,
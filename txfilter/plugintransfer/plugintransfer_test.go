@@ -353,12 +353,15 @@ func TestFilterTransaction(t *testing.T) {
 	}
 	p.configCache.Config.Disabled = false
 
-	// Case 3: whitelisted sender => allowed and not counted
+	// Case 3: whitelisted sender => allowed and not counted, but the amount is reported
 	p.configCache.Config.Whitelists[from] = true
-	blocked, reason, err = p.FilterTransaction(tx1, from, to, oneOAS, nil)
+	blocked, reason, amount, err := p.FilterTransactionWithAmount(tx1, from, to, oneOAS, nil)
 	if err != nil || blocked || reason != "" {
 		t.Fatalf("whitelist path expected allow, got blocked=%t reason=%q err=%v", blocked, reason, err)
 	}
+	if amount == nil || *amount != 1 {
+		t.Fatalf("whitelist path expected amount 1, got %v", amount)
+	}
 	if p.countedTxs.len() != 0 {
 		t.Fatalf("expected no count in whitelist path, got len=%d", p.countedTxs.len())
 	}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/txfilter/plugintransfer/config"
+)
+
+const (
+	// Maximum number of the recipients tracked by a velocity rule. The stale
+	// recipients are evicted first when the limit is reached.
+	maxTrackedCounterparties = 10_000
+
+	// Number of the recent transactions recorded by the rules, used to skip the
+	// transactions which were already allowed.
+	maxSeenTxs = 4096
+)
+
+// ruleTx is the transaction evaluated by the rules.
+type ruleTx struct {
+	hash      common.Hash
+	to        common.Address
+	allowed   bool // Sent to an allowed contract, exempted from the threshold and velocity rules
+	transfers []transfer
+	logs      []types.Log
+}
+
+// ruleEngine evaluates the declarative rules of the config. It keeps the
+// windows of the threshold and velocity rules, so it's rebuilt when the config
+// version changes.
+type ruleEngine struct {
+	version uint64
+	rules   []*rule
+	seenTxs *lrucache
+}
+
+// rule is a node of the rule tree, exactly one of the fields is set.
+type rule struct {
+	name      string
+	and, or   []*rule
+	threshold *thresholdRule
+	velocity  *velocityRule
+	denied    map[common.Address]bool
+}
+
+func newRuleEngine(cfg *config.PluginConfig) (*ruleEngine, error) {
+	e := &ruleEngine{version: cfg.Version, seenTxs: newCache(maxSeenTxs)}
+	for i := range cfg.Rules {
+		r, err := newRule(&cfg.Rules[i], 1)
+		if err != nil {
+			return nil, err
+		}
+		e.rules = append(e.rules, r)
+	}
+	return e, nil
+}
+
+// newRule builds the rule at the depth of the tree, rejecting the rules nested
+// too deep, which would be expensive to evaluate for every transaction.
+func newRule(cfg *config.RuleConfig, depth int) (*rule, error) {
+	if depth > config.MaxRuleDepth {
+		return nil, fmt.Errorf("rule %q: nested deeper than %d", cfg.Name, config.MaxRuleDepth)
+	}
+	r := &rule{name: cfg.Name}
+	kinds := 0
+	if len(cfg.And) > 0 {
+		kinds++
+		for i := range cfg.And {
+			sub, err := newRule(&cfg.And[i], depth+1)
+			if err != nil {
+				return nil, err
+			}
+			r.and = append(r.and, sub)
+		}
+	}
+	if len(cfg.Or) > 0 {
+		kinds++
+		for i := range cfg.Or {
+			sub, err := newRule(&cfg.Or[i], depth+1)
+			if err != nil {
+				return nil, err
+			}
+			r.or = append(r.or, sub)
+		}
+	}
+	if cfg.Threshold != nil {
+		kinds++
+		if cfg.Threshold.BlockCountThreshold == 0 {
+			return nil, fmt.Errorf("rule %q: block_count_threshold must be greater than 0", cfg.Name)
+		}
+		threshold := *cfg.Threshold // Detach from the config, which is decoded in place
+		r.threshold = &thresholdRule{
+			cfg:        &threshold,
+			tokens:     tokenSet(cfg.Threshold.Tokens),
+			countedTxs: newCache(cfg.Threshold.BlockCountThreshold),
+		}
+	}
+	if cfg.Velocity != nil {
+		kinds++
+		if cfg.Velocity.BlockCountThreshold == 0 {
+			return nil, fmt.Errorf("rule %q: block_count_threshold must be greater than 0", cfg.Name)
+		}
+		velocity := *cfg.Velocity
+		r.velocity = &velocityRule{
+			cfg:            &velocity,
+			tokens:         tokenSet(cfg.Velocity.Tokens),
+			counterparties: make(map[common.Address]*lrucache),
+		}
+	}
+	if len(cfg.DeniedContracts) > 0 {
+		kinds++
+		r.denied = make(map[common.Address]bool, len(cfg.DeniedContracts))
+		for _, address := range cfg.DeniedContracts {
+			r.denied[address] = true
+		}
+	}
+	if kinds != 1 {
+		return nil, fmt.Errorf("rule %q: exactly one of and, or, threshold, velocity and denied_contracts must be set", cfg.Name)
+	}
+	return r, nil
+}
+
+// tokenSet returns nil, which means all the target tokens, if no token is given.
+func tokenSet(tokens []common.Address) map[common.Address]bool {
+	if len(tokens) == 0 {
+		return nil
+	}
+	set := make(map[common.Address]bool, len(tokens))
+	for _, token := range tokens {
+		set[token] = true
+	}
+	return set
+}
+
+// seen reports whether the transaction was already allowed by the rules.
+func (e *ruleEngine) seen(txhash common.Hash) bool {
+	return e.seenTxs.contains(txhash)
+}
+
+// match reports whether any of the rules matches the transaction.
+func (e *ruleEngine) match(tx *ruleTx, now int64) (blocks bool, reason string) {
+	for _, r := range e.rules {
+		if ok, reason := r.match(tx, now); ok {
+			return true, fmt.Sprintf("rule %s: %s", r.name, reason)
+		}
+	}
+	return false, ""
+}
+
+// record counts the allowed transaction in the windows of all the rules.
+func (e *ruleEngine) record(tx *ruleTx, now int64) {
+	if len(e.rules) == 0 {
+		return
+	}
+	for _, r := range e.rules {
+		r.record(tx, now)
+	}
+	e.seenTxs.push(tx.hash, 0, now)
+}
+
+func (r *rule) match(tx *ruleTx, now int64) (bool, string) {
+	switch {
+	case r.and != nil:
+		reasons := make([]string, 0, len(r.and))
+		for _, sub := range r.and {
+			ok, reason := sub.match(tx, now)
+			if !ok {
+				return false, ""
+			}
+			reasons = append(reasons, reason)
+		}
+		return true, strings.Join(reasons, " and ")
+	case r.or != nil:
+		for _, sub := range r.or {
+			if ok, reason := sub.match(tx, now); ok {
+				return true, reason
+			}
+		}
+		return false, ""
+	case r.threshold != nil:
+		return r.threshold.match(tx, now)
+	case r.velocity != nil:
+		return r.velocity.match(tx, now)
+	default:
+		return matchDenied(r.denied, tx)
+	}
+}
+
+func (r *rule) record(tx *ruleTx, now int64) {
+	for _, sub := range r.and {
+		sub.record(tx, now)
+	}
+	for _, sub := range r.or {
+		sub.record(tx, now)
+	}
+	if r.threshold != nil {
+		r.threshold.record(tx, now)
+	}
+	if r.velocity != nil {
+		r.velocity.record(tx, now)
+	}
+}
+
+// matchDenied matches if the transaction is sent to, or emits the logs of the denied contracts.
+func matchDenied(denied map[common.Address]bool, tx *ruleTx) (bool, string) {
+	if denied[tx.to] {
+		return true, fmt.Sprintf("denied contract: %s", tx.to.Hex())
+	}
+	for i := range tx.logs {
+		if denied[tx.logs[i].Address] {
+			return true, fmt.Sprintf("denied contract: %s", tx.logs[i].Address.Hex())
+		}
+	}
+	return false, ""
+}
+
+// scopedAmount sums the transfers of the tokens, reporting false if there is none.
+func scopedAmount(tokens map[common.Address]bool, transfers []transfer) (uint64, bool) {
+	var (
+		amount uint64
+		found  bool
+	)
+	for i := range transfers {
+		if tokens == nil || tokens[transfers[i].token] {
+			amount = saturatingAdd(amount, transfers[i].yen)
+			found = true
+		}
+	}
+	return amount, found
+}
+
+// checkWindow checks the count and amount thresholds of the window.
+func checkWindow(c *lrucache, cfg *config.RuleThresholdConfig, amount uint64, now int64) (bool, string) {
+	startTime := now - int64(cfg.MeasurementWindow/time.Second)
+	if blocks, reason := checkCountThreshold(c, cfg.BlockCountThreshold, startTime); blocks {
+		return true, reason
+	}
+	if blocks, reason, _ := checkAmountThreshold(c, cfg.BlockAmountThreshold, startTime, amount); blocks {
+		return true, reason
+	}
+	return false, ""
+}
+
+// thresholdRule limits the transfers of the tokens within the window.
+type thresholdRule struct {
+	cfg        *config.RuleThresholdConfig
+	tokens     map[common.Address]bool
+	countedTxs *lrucache
+}
+
+func (r *thresholdRule) match(tx *ruleTx, now int64) (bool, string) {
+	if tx.allowed {
+		return false, ""
+	}
+	amount, ok := scopedAmount(r.tokens, tx.transfers)
+	if !ok {
+		return false, ""
+	}
+	return checkWindow(r.countedTxs, r.cfg, amount, now)
+}
+
+func (r *thresholdRule) record(tx *ruleTx, now int64) {
+	if amount, ok := scopedAmount(r.tokens, tx.transfers); ok {
+		r.countedTxs.push(tx.hash, amount, now)
+	}
+}
+
+// velocityRule limits the transfers of the tokens to each recipient within the window.
+type velocityRule struct {
+	cfg            *config.RuleThresholdConfig
+	tokens         map[common.Address]bool
+	counterparties map[common.Address]*lrucache
+}
+
+// amounts sums the transfers of the tokens per recipient.
+func (r *velocityRule) amounts(transfers []transfer) map[common.Address]uint64 {
+	amounts := make(map[common.Address]uint64)
+	for i := range transfers {
+		if r.tokens == nil || r.tokens[transfers[i].token] {
+			amounts[transfers[i].to] = saturatingAdd(amounts[transfers[i].to], transfers[i].yen)
+		}
+	}
+	return amounts
+}
+
+func (r *velocityRule) match(tx *ruleTx, now int64) (bool, string) {
+	if tx.allowed {
+		return false, ""
+	}
+	for counterparty, amount := range r.amounts(tx.transfers) {
+		c, ok := r.counterparties[counterparty]
+		if !ok {
+			// No history, only the current transaction is checked
+			if amount > r.cfg.BlockAmountThreshold {
+				return true, fmt.Sprintf("over amount threshold: %d (single tx), counterparty: %s", r.cfg.BlockAmountThreshold, counterparty.Hex())
+			}
+			continue
+		}
+		if blocks, reason := checkWindow(c, r.cfg, amount, now); blocks {
+			return true, fmt.Sprintf("%s, counterparty: %s", reason, counterparty.Hex())
+		}
+	}
+	return false, ""
+}
+
+func (r *velocityRule) record(tx *ruleTx, now int64) {
+	for counterparty, amount := range r.amounts(tx.transfers) {
+		c, ok := r.counterparties[counterparty]
+		if !ok {
+			if len(r.counterparties) >= maxTrackedCounterparties {
+				r.evict(now)
+			}
+			c = newCache(r.cfg.BlockCountThreshold)
+			r.counterparties[counterparty] = c
+		}
+		c.push(tx.hash, amount, now)
+	}
+}
+
+// evict drops the recipients without any transfer in the window, or an
+// arbitrary recipient if all of them are active.
+func (r *velocityRule) evict(now int64) {
+	startTime := now - int64(r.cfg.MeasurementWindow/time.Second)
+	for counterparty, c := range r.counterparties {
+		if newest, ok := c.getNewest(); !ok || newest.createdAt < startTime {
+			delete(r.counterparties, counterparty)
+		}
+	}
+	for counterparty := range r.counterparties {
+		if len(r.counterparties) < maxTrackedCounterparties {
+			break
+		}
+		delete(r.counterparties, counterparty)
+	}
+}
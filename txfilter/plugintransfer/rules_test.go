@@ -0,0 +1,224 @@
+package main
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/txfilter/plugintransfer/config"
+)
+
+func addressTopic(address common.Address) common.Hash {
+	return common.BytesToHash(address.Bytes())
+}
+
+func word(v uint64) []byte {
+	return common.BigToHash(new(big.Int).SetUint64(v)).Bytes()
+}
+
+func TestTransfers(t *testing.T) {
+	var (
+		from    = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		to      = common.HexToAddress("0x1000000000000000000000000000000000000002")
+		erc721  = common.HexToAddress("0x3000000000000000000000000000000000000001")
+		erc1155 = common.HexToAddress("0x3000000000000000000000000000000000000002")
+		other   = common.HexToAddress("0x3000000000000000000000000000000000000003")
+	)
+	c := ConfigCache{Config: config.PluginConfig{
+		NativeToken:    config.NativeTokenConfig{ToYenRate: 1},
+		TargetERC721s:  []config.TargetNFTConfig{{Address: erc721, ToYenRate: 1000}},
+		TargetERC1155s: []config.TargetNFTConfig{{Address: erc1155, ToYenRate: 10}},
+	}}
+
+	batchData := append(append(append(append(word(64), word(160)...), word(2)...), append(word(1), word(2)...)...), append(append(word(2), word(3)...), word(4)...)...)
+	logs := []types.Log{
+		// ERC721 Transfer
+		{Address: erc721, Topics: []common.Hash{transferEventTopic, addressTopic(from), addressTopic(to), common.HexToHash("0x7")}},
+		// ERC1155 TransferSingle of 5 units
+		{Address: erc1155, Topics: []common.Hash{transferSingleEventTopic, addressTopic(from), addressTopic(from), addressTopic(to)}, Data: append(word(1), word(5)...)},
+		// ERC1155 TransferBatch of 3+4 units
+		{Address: erc1155, Topics: []common.Hash{transferBatchEventTopic, addressTopic(from), addressTopic(from), addressTopic(to)}, Data: batchData},
+		// Malformed TransferBatch
+		{Address: erc1155, Topics: []common.Hash{transferBatchEventTopic, addressTopic(from), addressTopic(from), addressTopic(to)}, Data: append(word(64), word(1<<40)...)},
+		// Not a target
+		{Address: other, Topics: []common.Hash{transferEventTopic, addressTopic(from), addressTopic(to), common.HexToHash("0x7")}},
+	}
+	transfers := c.transfers(from, to, rawFromString256("2000000000000000000"), logs)
+	want := []transfer{
+		{from: from, to: to, yen: 2},
+		{token: erc721, from: from, to: to, yen: 1000},
+		{token: erc1155, from: from, to: to, yen: 50},
+		{token: erc1155, from: from, to: to, yen: 70},
+	}
+	if len(transfers) != len(want) {
+		t.Fatalf("got %d transfers, want %d: %+v", len(transfers), len(want), transfers)
+	}
+	for i := range want {
+		if transfers[i] != want[i] {
+			t.Errorf("transfers[%d] = %+v, want %+v", i, transfers[i], want[i])
+		}
+	}
+	if got := sumYen(transfers); got != 1122 {
+		t.Errorf("sumYen = %d, want 1122", got)
+	}
+}
+
+func TestRuleEngine(t *testing.T) {
+	p := &Plugin
+	defer p.Clear()
+
+	var (
+		from    = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		alice   = common.HexToAddress("0x1000000000000000000000000000000000000002")
+		bob     = common.HexToAddress("0x1000000000000000000000000000000000000003")
+		erc721  = common.HexToAddress("0x3000000000000000000000000000000000000001")
+		denied  = common.HexToAddress("0x3000000000000000000000000000000000000002")
+		allowed = common.HexToAddress("0x3000000000000000000000000000000000000003")
+		banned  = common.HexToAddress("0x3000000000000000000000000000000000000004")
+		oneOAS  = rawFromString256("1000000000000000000")
+		nextTx  = uint64(2000)
+	)
+	nftTransfer := func(recipient common.Address) []types.Log {
+		nextTx++
+		return []types.Log{{Address: erc721, Topics: []common.Hash{transferEventTopic, addressTopic(from), addressTopic(recipient), common.BigToHash(new(big.Int).SetUint64(nextTx))}}}
+	}
+	filter := func(to common.Address, value [32]byte, logs []types.Log) (bool, string) {
+		t.Helper()
+		nextTx++
+		blocked, reason, err := p.FilterTransaction(hashFromIndex(nextTx), from, to, value, logs)
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		return blocked, reason
+	}
+
+	p.configCache = ConfigCache{
+		Config: config.PluginConfig{
+			Version:           1,
+			MeasurementWindow: 10 * time.Second,
+			Threshold: config.ThresholdConfig{
+				WarningCountThreshold:  100,
+				BlockCountThreshold:    100,
+				WarningAmountThreshold: 1_000_000,
+				BlockAmountThreshold:   1_000_000,
+			},
+			NativeToken:      config.NativeTokenConfig{ToYenRate: 1},
+			TargetERC721s:    []config.TargetNFTConfig{{Address: erc721, ToYenRate: 100}},
+			AllowedContracts: []common.Address{allowed},
+			Rules: []config.RuleConfig{
+				{
+					Name: "nft",
+					Threshold: &config.RuleThresholdConfig{
+						Tokens:               []common.Address{erc721},
+						MeasurementWindow:    10 * time.Second,
+						BlockCountThreshold:  3,
+						BlockAmountThreshold: 1_000_000,
+					},
+				},
+				{
+					Name: "mule",
+					Velocity: &config.RuleThresholdConfig{
+						MeasurementWindow:    10 * time.Second,
+						BlockCountThreshold:  100,
+						BlockAmountThreshold: 250,
+					},
+				},
+				{
+					Name:            "banned",
+					DeniedContracts: []common.Address{banned},
+				},
+				{
+					Name: "denied-large",
+					And: []config.RuleConfig{
+						{DeniedContracts: []common.Address{denied}},
+						{Threshold: &config.RuleThresholdConfig{
+							MeasurementWindow:    10 * time.Second,
+							BlockCountThreshold:  100,
+							BlockAmountThreshold: 1,
+						}},
+					},
+				},
+			},
+		},
+		updatedAt: time.Now(),
+		ttl:       1 * time.Hour,
+	}
+	p.countedTxs = newCache(100)
+	p.rules = nil
+
+	// The velocity is measured per recipient
+	if blocked, reason := filter(alice, [32]byte{}, nftTransfer(alice)); blocked {
+		t.Fatalf("1st nft transfer blocked: %s", reason)
+	}
+	if blocked, reason := filter(alice, [32]byte{}, nftTransfer(alice)); blocked {
+		t.Fatalf("2nd nft transfer blocked: %s", reason)
+	}
+	blocked, reason := filter(alice, [32]byte{}, nftTransfer(alice))
+	if !blocked || !strings.Contains(reason, "rule mule") || !strings.Contains(reason, alice.Hex()) {
+		t.Fatalf("expected block by velocity rule, blocked=%t reason=%q", blocked, reason)
+	}
+
+	// The per-token threshold counts the transfers regardless of the recipient
+	if blocked, reason := filter(bob, [32]byte{}, nftTransfer(bob)); blocked {
+		t.Fatalf("3rd nft transfer blocked: %s", reason)
+	}
+	blocked, reason = filter(bob, [32]byte{}, nftTransfer(bob))
+	if !blocked || !strings.Contains(reason, "rule nft: over count threshold: 3") {
+		t.Fatalf("expected block by threshold rule, blocked=%t reason=%q", blocked, reason)
+	}
+
+	// The native transfer is not scoped by the nft rule
+	if blocked, reason := filter(bob, oneOAS, nil); blocked {
+		t.Fatalf("native transfer blocked: %s", reason)
+	}
+
+	// Both of the combined conditions must match
+	if blocked, reason := filter(denied, [32]byte{}, nil); blocked {
+		t.Fatalf("denied contract without amount blocked: %s", reason)
+	}
+	blocked, reason = filter(denied, rawFromString256("2000000000000000000"), nil)
+	if !blocked || !strings.Contains(reason, "rule denied-large: denied contract") || !strings.Contains(reason, " and over amount threshold") {
+		t.Fatalf("expected block by and rule, blocked=%t reason=%q", blocked, reason)
+	}
+
+	// The transactions to the allowed contracts are exempted from the thresholds
+	// and the threshold and velocity rules
+	for i := 0; i < 3; i++ {
+		if blocked, reason := filter(allowed, [32]byte{}, nftTransfer(alice)); blocked {
+			t.Fatalf("transaction to allowed contract blocked: %s", reason)
+		}
+	}
+	// But not from the denied contracts, e.g. moving the tokens through a router
+	blocked, reason = filter(allowed, [32]byte{}, []types.Log{{Address: banned}})
+	if !blocked || !strings.Contains(reason, "rule banned: denied contract: "+banned.Hex()) {
+		t.Fatalf("expected block by denied rule, blocked=%t reason=%q", blocked, reason)
+	}
+
+	// The rules are rebuilt with the version
+	p.configCache.Config.Version = 2
+	p.configCache.Config.Rules = []config.RuleConfig{{Name: "invalid"}}
+	nextTx++
+	if _, _, err := p.FilterTransaction(hashFromIndex(nextTx), from, alice, oneOAS, nil); err == nil {
+		t.Fatal("expected error for invalid rules")
+	}
+}
+
+func TestRuleDepth(t *testing.T) {
+	nested := func(depth int) config.RuleConfig {
+		rule := config.RuleConfig{DeniedContracts: []common.Address{{0x01}}}
+		for i := 1; i < depth; i++ {
+			rule = config.RuleConfig{Or: []config.RuleConfig{rule}}
+		}
+		rule.Name = "nested"
+		return rule
+	}
+	if _, err := newRuleEngine(&config.PluginConfig{Rules: []config.RuleConfig{nested(config.MaxRuleDepth)}}); err != nil {
+		t.Fatalf("rules at max depth rejected: %v", err)
+	}
+	if _, err := newRuleEngine(&config.PluginConfig{Rules: []config.RuleConfig{nested(config.MaxRuleDepth + 1)}}); err == nil {
+		t.Fatal("expected error for rules nested too deep")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -115,8 +116,136 @@ func Verify(cfg *config.PluginConfig) error {
 		}
 	}
 
+	// target NFTs: valid address and non-negative rate
+	for _, nfts := range []struct {
+		name    string
+		targets []config.TargetNFTConfig
+	}{
+		{"target_erc721s", cfg.TargetERC721s},
+		{"target_erc1155s", cfg.TargetERC1155s},
+	} {
+		name, targets := nfts.name, nfts.targets
+		for i := range targets {
+			if targets[i].Address == (common.Address{}) {
+				return fmt.Errorf("%w: %s[%d].address must not be zero", ErrValidation, name, i)
+			}
+			if targets[i].ToYenRate < 0 {
+				return fmt.Errorf("%w: %s[%d].to_yen_rate must be non-negative (got %f)", ErrValidation, name, i, targets[i].ToYenRate)
+			}
+		}
+	}
+
+	// allowed contracts: valid address
+	for i, address := range cfg.AllowedContracts {
+		if address == (common.Address{}) {
+			return fmt.Errorf("%w: allowed_contracts[%d] must not be zero", ErrValidation, i)
+		}
+	}
+
+	// rules: unique names and valid conditions
+	names := make(map[string]bool, len(cfg.Rules))
+	for i := range cfg.Rules {
+		rule := &cfg.Rules[i]
+		if rule.Name == "" {
+			return fmt.Errorf("%w: rules[%d].name must not be empty", ErrValidation, i)
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("%w: rules[%d].name %q is duplicated", ErrValidation, i, rule.Name)
+		}
+		names[rule.Name] = true
+		if err := verifyRule(cfg, rule, fmt.Sprintf("rules[%d]", i), 1); err != nil {
+			return err
+		}
+	}
+
 	// Print the config
 	fmt.Printf("config: %+v\n", cfg)
 
 	return nil
 }
+
+// verifyRule validates the rule and its sub rules recursively.
+func verifyRule(cfg *config.PluginConfig, rule *config.RuleConfig, path string, depth int) error {
+	if depth > config.MaxRuleDepth {
+		return fmt.Errorf("%w: %s is nested deeper than %d", ErrValidation, path, config.MaxRuleDepth)
+	}
+
+	kinds := 0
+	for _, set := range []bool{len(rule.And) > 0, len(rule.Or) > 0, rule.Threshold != nil, rule.Velocity != nil, len(rule.DeniedContracts) > 0} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return fmt.Errorf("%w: %s must set exactly one of and, or, threshold, velocity and denied_contracts", ErrValidation, path)
+	}
+
+	for i := range rule.And {
+		if err := verifyRule(cfg, &rule.And[i], fmt.Sprintf("%s.and[%d]", path, i), depth+1); err != nil {
+			return err
+		}
+	}
+	for i := range rule.Or {
+		if err := verifyRule(cfg, &rule.Or[i], fmt.Sprintf("%s.or[%d]", path, i), depth+1); err != nil {
+			return err
+		}
+	}
+	if rule.Threshold != nil {
+		if err := verifyRuleThreshold(cfg, rule.Threshold, path+".threshold"); err != nil {
+			return err
+		}
+	}
+	if rule.Velocity != nil {
+		if err := verifyRuleThreshold(cfg, rule.Velocity, path+".velocity"); err != nil {
+			return err
+		}
+	}
+	for i, address := range rule.DeniedContracts {
+		if address == (common.Address{}) {
+			return fmt.Errorf("%w: %s.denied_contracts[%d] must not be zero", ErrValidation, path, i)
+		}
+		if slices.Contains(cfg.AllowedContracts, address) {
+			return fmt.Errorf("%w: %s.denied_contracts[%d] %s is also allowed", ErrValidation, path, i, address.Hex())
+		}
+	}
+	return nil
+}
+
+// verifyRuleThreshold validates the window and the tokens of the threshold or velocity rule.
+func verifyRuleThreshold(cfg *config.PluginConfig, t *config.RuleThresholdConfig, path string) error {
+	if t.MeasurementWindow < time.Second {
+		return fmt.Errorf("%w: %s.measurement_window must be at least 1 second (got %v)", ErrValidation, path, t.MeasurementWindow)
+	}
+	if t.BlockCountThreshold == 0 {
+		return fmt.Errorf("%w: %s.block_count_threshold must be greater than 0", ErrValidation, path)
+	}
+	if t.BlockAmountThreshold == 0 {
+		return fmt.Errorf("%w: %s.block_amount_threshold must be greater than 0", ErrValidation, path)
+	}
+	for i, token := range t.Tokens {
+		if !isTargetToken(cfg, token) {
+			return fmt.Errorf("%w: %s.tokens[%d] %s is neither the native token nor a target token", ErrValidation, path, i, token.Hex())
+		}
+	}
+	return nil
+}
+
+// isTargetToken reports whether the token is priced by the config, the zero address is the native token.
+func isTargetToken(cfg *config.PluginConfig, token common.Address) bool {
+	if token == (common.Address{}) {
+		return true
+	}
+	for i := range cfg.TargetERC20s {
+		if cfg.TargetERC20s[i].Address == token {
+			return true
+		}
+	}
+	for _, targets := range [][]config.TargetNFTConfig{cfg.TargetERC721s, cfg.TargetERC1155s} {
+		for i := range targets {
+			if targets[i].Address == token {
+				return true
+			}
+		}
+	}
+	return false
+}